@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"os"
@@ -32,15 +33,44 @@ func main() {
 	}
 	defer utils.Sync()
 
+	// Initialize gRPC transport security
+	var tlsConfig *tls.Config
+	if cfg.GRPC.TLSEnabled {
+		var reloader *utils.CertReloader
+		var err error
+		tlsOptions := cfg.GRPC.TLS
+		if tlsOptions.ServerName == "" {
+			// Verify the server against the dialed host, which is not sent as SNI when it is an IP address
+			tlsOptions.ServerName = cfg.GRPC.ServerHost
+		}
+		tlsConfig, reloader, err = utils.NewClientTLSConfig(tlsOptions)
+		if err != nil {
+			utils.Fatal(fmt.Sprintf("Failed to load gRPC TLS configuration: %v", err))
+		}
+		defer reloader.Close()
+	}
+
 	// Initialize gRPC client
-	grpcClient, err := generatorGrpc.NewSensorClient(cfg.GetGRPCAddress())
+	grpcClient, err := generatorGrpc.NewSensorClient(cfg.GetGRPCAddress(), cfg.GRPC.APIKey, tlsConfig)
 	if err != nil {
 		utils.Fatal("Failed to connect to gRPC server")
 	}
 	defer grpcClient.Close()
 
 	// Initialize services
-	generatorService := generatorServices.NewGeneratorService(grpcClient, cfg.Generator.SensorType, cfg.Generator.Frequency)
+	generatorService := generatorServices.NewGeneratorService(grpcClient, cfg.Generator.SensorType, cfg.Generator.Frequency, cfg.Generator.RetryQueueSize)
+
+	// Register with microservice-b's control plane
+	controlCtx, stopControl := context.WithCancel(context.Background())
+	defer stopControl()
+	if cfg.Control.Enabled {
+		controlClient, err := generatorGrpc.NewControlClient(cfg.GetGRPCAddress(), cfg.GRPC.APIKey, tlsConfig, generatorService, cfg.Control.InstanceID, cfg.Generator.SensorType, cfg.Control.StatusInterval)
+		if err != nil {
+			utils.Fatal("Failed to connect to control plane")
+		}
+		defer controlClient.Close()
+		go controlClient.Run(controlCtx)
+	}
 
 	// Initialize handlers
 	generatorHandler := generatorHandlers.NewGeneratorHandler(generatorService)
@@ -82,6 +112,7 @@ func main() {
 	utils.Info("Shutting down server...")
 
 	// Stop data generation
+	stopControl()
 	generatorService.StopGeneration()
 
 	// Graceful shutdown with timeout
@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"os"
 	"time"
 
 	"github.com/worlder-team/microservice-server/shared/utils"
@@ -11,6 +12,7 @@ type Config struct {
 	Server    ServerConfig
 	GRPC      GRPCConfig
 	Generator GeneratorConfig
+	Control   ControlConfig
 	RateLimit RateLimitConfig
 }
 
@@ -27,12 +29,23 @@ type ServerConfig struct {
 type GRPCConfig struct {
 	ServerHost string
 	ServerPort string
+	APIKey     string // API key issued by microservice-b's auth module
+	TLSEnabled bool
+	TLS        utils.TLSOptions
 }
 
 // GeneratorConfig holds sensor generator configuration
 type GeneratorConfig struct {
-	SensorType string
-	Frequency  string
+	SensorType     string
+	Frequency      string
+	RetryQueueSize int // Failed readings kept for re-sending, 0 disables retries
+}
+
+// ControlConfig holds control plane registration configuration
+type ControlConfig struct {
+	Enabled        bool          // Register with microservice-b and accept commands over the control stream
+	InstanceID     string        // Identifies this generator in microservice-b's registry
+	StatusInterval time.Duration // How often status is reported when idle
 }
 
 // RateLimitConfig holds rate limiting configuration
@@ -55,11 +68,26 @@ func LoadConfig() *Config {
 			// GRPC_HOST points to microservice-b (storage service) where generators send data, check service name in docker-compose.yml
 			ServerHost: utils.GetEnvOrDefault("GRPC_HOST", "microservice-b"),
 			ServerPort: utils.GetEnvOrDefault("GRPC_PORT", "50051"),
+			APIKey:     utils.GetEnvOrDefault("GRPC_API_KEY", ""),
+			TLSEnabled: utils.ParseBool(utils.GetEnvOrDefault("GRPC_TLS_ENABLED", "false")),
+			TLS: utils.TLSOptions{
+				CertFile:       utils.GetEnvOrDefault("GRPC_TLS_CERT_FILE", ""),
+				KeyFile:        utils.GetEnvOrDefault("GRPC_TLS_KEY_FILE", ""),
+				CAFile:         utils.GetEnvOrDefault("GRPC_TLS_CA_FILE", ""),
+				ServerName:     utils.GetEnvOrDefault("GRPC_TLS_SERVER_NAME", ""),
+				ReloadInterval: utils.ParseDurationOrZero(utils.GetEnvOrDefault("GRPC_TLS_RELOAD_INTERVAL", "1m")),
+			},
 		},
 		Generator: GeneratorConfig{
 			// SENSOR_TYPE is set by docker-compose.yml for each service instance (not in .env file)
-			SensorType: utils.GetEnvOrDefault("SENSOR_TYPE", "temperature"),
-			Frequency:  utils.GetEnvOrDefault("GENERATION_FREQUENCY", "300s"),
+			SensorType:     utils.GetEnvOrDefault("SENSOR_TYPE", "temperature"),
+			Frequency:      utils.GetEnvOrDefault("GENERATION_FREQUENCY", "300s"),
+			RetryQueueSize: utils.ParseInt(utils.GetEnvOrDefault("GENERATOR_RETRY_QUEUE_SIZE", "1000")),
+		},
+		Control: ControlConfig{
+			Enabled:        utils.ParseBool(utils.GetEnvOrDefault("CONTROL_PLANE_ENABLED", "true")),
+			InstanceID:     utils.GetEnvOrDefault("GENERATOR_INSTANCE_ID", defaultInstanceID()),
+			StatusInterval: utils.ParseDurationOrZero(utils.GetEnvOrDefault("CONTROL_STATUS_INTERVAL", "15s")),
 		},
 		RateLimit: RateLimitConfig{
 			RequestsPerMinute: utils.ParseInt(utils.GetEnvOrDefault("RATE_LIMIT", "100")),
@@ -71,3 +99,12 @@ func LoadConfig() *Config {
 func (c *Config) GetGRPCAddress() string {
 	return c.GRPC.ServerHost + ":" + c.GRPC.ServerPort
 }
+
+// defaultInstanceID identifies the generator by hostname, which is the container ID under docker-compose
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return utils.GenerateID(4)
+	}
+	return hostname
+}
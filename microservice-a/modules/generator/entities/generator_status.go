@@ -10,4 +10,6 @@ type GeneratorStatus struct {
 	LastGenerated time.Time     `json:"last_generated,omitempty"`
 	TotalSent     int64         `json:"total_sent"`
 	Errors        int64         `json:"errors"`
+	Queued        int           `json:"queued"`  // Readings waiting to be re-sent
+	Dropped       int64         `json:"dropped"` // Readings given up on (queue overflow or rejected)
 }
@@ -10,3 +10,11 @@ type SensorData struct {
 	ID2         int32     `json:"id2"`
 	Timestamp   time.Time `json:"timestamp"`
 }
+
+// BatchResult summarises microservice-b's per-item answer to a batch
+type BatchResult struct {
+	Accepted   int
+	Duplicates int
+	Rejected   int
+	Retry      []*SensorData // Rejected items that may be sent again
+}
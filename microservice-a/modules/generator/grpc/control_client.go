@@ -0,0 +1,210 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/worlder-team/microservice-server/microservice-a/modules/generator/interfaces"
+	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+const (
+	controlInitialBackoff = time.Second
+	controlMaxBackoff     = 30 * time.Second
+	stateChangeTimeout    = time.Second
+)
+
+type controlClient struct {
+	conn             *grpc.ClientConn
+	client           pb.GeneratorControlServiceClient
+	generatorService interfaces.GeneratorService
+	instanceID       string
+	sensorType       string
+	statusInterval   time.Duration
+}
+
+// NewControlClient creates a client that registers the generator with microservice-b's control plane
+// and applies the commands pushed down the control stream to generatorService.
+func NewControlClient(serverAddress, apiKey string, tlsConfig *tls.Config, generatorService interfaces.GeneratorService, instanceID, sensorType string, statusInterval time.Duration) (interfaces.ControlClient, error) {
+	conn, err := grpc.NewClient(serverAddress, dialOptions(apiKey, tlsConfig)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %v", err)
+	}
+
+	if statusInterval <= 0 {
+		statusInterval = 15 * time.Second
+	}
+
+	return &controlClient{
+		conn:             conn,
+		client:           pb.NewGeneratorControlServiceClient(conn),
+		generatorService: generatorService,
+		instanceID:       instanceID,
+		sensorType:       sensorType,
+		statusInterval:   statusInterval,
+	}, nil
+}
+
+// Close closes the gRPC connection
+func (c *controlClient) Close() error {
+	return c.conn.Close()
+}
+
+// Run keeps the control stream open, reconnecting with backoff until ctx is cancelled
+func (c *controlClient) Run(ctx context.Context) {
+	backoff := controlInitialBackoff
+	for {
+		connectedAt := time.Now()
+		err := c.runSession(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A session that stayed up for a while resets the backoff
+		if time.Since(connectedAt) > controlMaxBackoff {
+			backoff = controlInitialBackoff
+		}
+		utils.Warn(fmt.Sprintf("Control stream closed: %v, reconnecting in %s", err, backoff))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, controlMaxBackoff)
+	}
+}
+
+// runSession registers the generator and serves a single control stream
+func (c *controlClient) runSession(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Command results and periodic status reports are sent from different goroutines
+	var sendMu sync.Mutex
+	send := func(message *pb.GeneratorMessage) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(message)
+	}
+
+	err = send(&pb.GeneratorMessage{Payload: &pb.GeneratorMessage_Register{Register: &pb.GeneratorRegistration{
+		InstanceId: c.instanceID,
+		SensorType: c.sensorType,
+		Status:     c.statusReport(),
+	}}})
+	if err != nil {
+		return err
+	}
+	utils.Info(fmt.Sprintf("Registered with control plane as %s", c.instanceID))
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			command, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+
+			result := c.applyCommand(command)
+			if err := send(&pb.GeneratorMessage{Payload: &pb.GeneratorMessage_CommandResult{CommandResult: result}}); err != nil {
+				recvErr <- err
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(c.statusInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-recvErr:
+			return err
+		case <-ticker.C:
+			if err := send(&pb.GeneratorMessage{Payload: &pb.GeneratorMessage_Status{Status: c.statusReport()}}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// applyCommand runs a command against the generator service and reports the outcome
+func (c *controlClient) applyCommand(command *pb.GeneratorCommand) *pb.GeneratorCommandResult {
+	var err error
+	switch command.Action {
+	case pb.GeneratorCommand_START:
+		if c.generatorService.IsRunning() {
+			err = errors.New("generator is already running")
+			break
+		}
+		go c.generatorService.StartGeneration(context.Background())
+		c.waitForRunning(true)
+	case pb.GeneratorCommand_STOP:
+		if !c.generatorService.IsRunning() {
+			err = errors.New("generator is not running")
+			break
+		}
+		c.generatorService.StopGeneration()
+	case pb.GeneratorCommand_SET_FREQUENCY:
+		wasRunning := c.generatorService.IsRunning()
+		err = c.generatorService.SetFrequency(command.Frequency)
+		if err == nil && wasRunning {
+			c.waitForRunning(true)
+		}
+	default:
+		err = fmt.Errorf("unsupported command action: %s", command.Action)
+	}
+
+	utils.Info(fmt.Sprintf("Applied control command %s (%s)", command.Action, command.CommandId))
+
+	result := &pb.GeneratorCommandResult{
+		CommandId: command.CommandId,
+		Success:   err == nil,
+		Status:    c.statusReport(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// waitForRunning waits briefly for an asynchronous start or restart to settle
+func (c *controlClient) waitForRunning(running bool) {
+	deadline := time.Now().Add(stateChangeTimeout)
+	for c.generatorService.IsRunning() != running && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// statusReport converts the generator status to its protobuf representation
+func (c *controlClient) statusReport() *pb.GeneratorStatusReport {
+	status := c.generatorService.GetStatus()
+
+	report := &pb.GeneratorStatusReport{
+		IsRunning: status.IsRunning,
+		Frequency: status.Frequency.String(),
+		TotalSent: status.TotalSent,
+		Errors:    status.Errors,
+	}
+	if !status.LastGenerated.IsZero() {
+		report.LastGenerated = timestamppb.New(status.LastGenerated)
+	}
+	return report
+}
@@ -0,0 +1,24 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+// apiKeyCredentials attaches the generator API key to every outgoing RPC
+type apiKeyCredentials struct {
+	apiKey string
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (c apiKeyCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		constants.APIKeyMetadataKey: c.apiKey,
+	}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+func (c apiKeyCredentials) RequireTransportSecurity() bool {
+	return false
+}
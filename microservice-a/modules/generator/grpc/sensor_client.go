@@ -2,10 +2,12 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -19,9 +21,11 @@ type sensorClient struct {
 	client pb.SensorServiceClient
 }
 
-// NewSensorClient creates a new gRPC sensor client
-func NewSensorClient(serverAddress string) (interfaces.SensorClient, error) {
-	conn, err := grpc.NewClient(serverAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewSensorClient creates a new gRPC sensor client.
+// When apiKey is set it is sent with every call for microservice-b to authenticate the generator.
+// A nil tlsConfig uses a plaintext connection.
+func NewSensorClient(serverAddress, apiKey string, tlsConfig *tls.Config) (interfaces.SensorClient, error) {
+	conn, err := grpc.NewClient(serverAddress, dialOptions(apiKey, tlsConfig)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to server: %v", err)
 	}
@@ -34,6 +38,20 @@ func NewSensorClient(serverAddress string) (interfaces.SensorClient, error) {
 	}, nil
 }
 
+// dialOptions returns the transport and per-RPC credentials shared by microservice-b clients
+func dialOptions(apiKey string, tlsConfig *tls.Config) []grpc.DialOption {
+	transportCredentials := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transportCredentials)}
+	if apiKey != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(apiKeyCredentials{apiKey: apiKey}))
+	}
+	return opts
+}
+
 // Close closes the gRPC connection
 func (c *sensorClient) Close() error {
 	return c.conn.Close()
@@ -64,8 +82,9 @@ func (c *sensorClient) SendSensorData(ctx context.Context, data *entities.Sensor
 	return nil
 }
 
-// SendSensorDataBatch sends a batch of sensor data to the server
-func (c *sensorClient) SendSensorDataBatch(ctx context.Context, data []*entities.SensorData) error {
+// SendSensorDataBatch sends a batch of sensor data to the server.
+// Items microservice-b rejected for transient reasons are returned in BatchResult.Retry.
+func (c *sensorClient) SendSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*entities.BatchResult, error) {
 	var pbDataBatch []*pb.SensorData
 
 	for _, sensorData := range data {
@@ -88,14 +107,21 @@ func (c *sensorClient) SendSensorDataBatch(ctx context.Context, data []*entities
 
 	response, err := c.client.SendSensorDataBatch(ctx, request)
 	if err != nil {
-		return fmt.Errorf("failed to send sensor data batch: %v", err)
+		return nil, fmt.Errorf("failed to send sensor data batch: %v", err)
 	}
 
-	if !response.Success {
-		return fmt.Errorf("server error: %s", response.Error)
+	result := &entities.BatchResult{
+		Accepted:   int(response.Accepted),
+		Duplicates: int(response.Duplicates),
+		Rejected:   int(response.Rejected),
+	}
+	for _, item := range response.Results {
+		if item.Status == pb.SensorItemResult_REJECTED && item.Retryable && int(item.Index) < len(data) {
+			result.Retry = append(result.Retry, data[item.Index])
+		}
 	}
 
-	return nil
+	return result, nil
 }
 
 // HealthCheck checks the health of the gRPC server
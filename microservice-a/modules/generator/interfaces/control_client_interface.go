@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// ControlClient keeps the generator registered with microservice-b's control plane
+type ControlClient interface {
+	Run(ctx context.Context)
+	Close() error
+}
@@ -9,7 +9,7 @@ import (
 // SensorClient interface for gRPC client
 type SensorClient interface {
 	SendSensorData(ctx context.Context, data *entities.SensorData) error
-	SendSensorDataBatch(ctx context.Context, data []*entities.SensorData) error
+	SendSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*entities.BatchResult, error)
 	HealthCheck(ctx context.Context) error
 	Close() error
 }
@@ -25,10 +25,17 @@ type generatorService struct {
 	lastGenerated time.Time
 	totalSent     int64
 	errors        int64
+	retryQueue    []*entities.SensorData
+	retryLimit    int
+	dropped       int64
 }
 
-// NewGeneratorService creates a new generator service
-func NewGeneratorService(grpcClient interfaces.SensorClient, sensorType, frequency string) interfaces.GeneratorService {
+// retryBatchSize caps how many queued readings are re-sent per tick
+const retryBatchSize = 100
+
+// NewGeneratorService creates a new generator service.
+// Readings that fail to send are kept in a retry queue of at most retryQueueSize items.
+func NewGeneratorService(grpcClient interfaces.SensorClient, sensorType, frequency string, retryQueueSize int) interfaces.GeneratorService {
 	freq, err := utils.ParseDuration(frequency)
 	if err != nil {
 		freq = time.Second // Default to 1 second
@@ -39,6 +46,7 @@ func NewGeneratorService(grpcClient interfaces.SensorClient, sensorType, frequen
 		sensorType: sensorType,
 		frequency:  freq,
 		stopChan:   make(chan struct{}),
+		retryLimit: retryQueueSize,
 	}
 }
 
@@ -135,6 +143,8 @@ func (s *generatorService) GetStatus() *entities.GeneratorStatus {
 		LastGenerated: s.lastGenerated,
 		TotalSent:     s.totalSent,
 		Errors:        s.errors,
+		Queued:        len(s.retryQueue),
+		Dropped:       s.dropped,
 	}
 }
 
@@ -153,6 +163,7 @@ func (s *generatorService) generateAndSend(ctx context.Context) {
 		s.mu.Lock()
 		s.errors++
 		s.mu.Unlock()
+		s.enqueueRetry([]*entities.SensorData{data})
 		// Log error but continue generation
 		fmt.Printf("Error sending sensor data: %v\n", err)
 		return
@@ -162,6 +173,52 @@ func (s *generatorService) generateAndSend(ctx context.Context) {
 	s.totalSent++
 	s.lastGenerated = time.Now()
 	s.mu.Unlock()
+
+	s.flushRetryQueue(ctx)
+}
+
+// flushRetryQueue re-sends queued readings as a batch once microservice-b is reachable again.
+// Only the items rejected for transient reasons are queued again.
+func (s *generatorService) flushRetryQueue(ctx context.Context) {
+	s.mu.Lock()
+	count := min(len(s.retryQueue), retryBatchSize)
+	batch := s.retryQueue[:count:count]
+	s.retryQueue = s.retryQueue[count:]
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	result, err := s.grpcClient.SendSensorDataBatch(ctx, batch)
+	if err != nil {
+		s.enqueueRetry(batch)
+		fmt.Printf("Error re-sending queued sensor data: %v\n", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.totalSent += int64(result.Accepted + result.Duplicates)
+	s.dropped += int64(result.Rejected - len(result.Retry))
+	s.mu.Unlock()
+
+	s.enqueueRetry(result.Retry)
+}
+
+// enqueueRetry queues readings for another attempt, discarding the oldest when the queue is full
+func (s *generatorService) enqueueRetry(data []*entities.SensorData) {
+	if s.retryLimit <= 0 || len(data) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retryQueue = append(s.retryQueue, data...)
+	if overflow := len(s.retryQueue) - s.retryLimit; overflow > 0 {
+		s.retryQueue = s.retryQueue[overflow:]
+		s.dropped += int64(overflow)
+	}
 }
 
 // generateSensorData generates sensor data based on sensor type
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"os"
@@ -14,22 +16,46 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
-	"gorm.io/driver/mysql"
+	"google.golang.org/grpc/credentials"
 	"gorm.io/gorm"
 
 	"github.com/worlder-team/microservice-server/microservice-b/configs"
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrate"
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrations"
+	alertHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/alerts/handlers"
+	alertServices "github.com/worlder-team/microservice-server/microservice-b/modules/alerts/services"
+	auditHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/audit/handlers"
+	auditServices "github.com/worlder-team/microservice-server/microservice-b/modules/audit/services"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+	authGrpc "github.com/worlder-team/microservice-server/microservice-b/modules/auth/grpc"
 	authHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/auth/handlers"
+	authInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/auth/interfaces"
 	authServices "github.com/worlder-team/microservice-server/microservice-b/modules/auth/services"
+	generatorGrpc "github.com/worlder-team/microservice-server/microservice-b/modules/generators/grpc"
+	generatorHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/generators/handlers"
+	generatorInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/generators/interfaces"
+	generatorServices "github.com/worlder-team/microservice-server/microservice-b/modules/generators/services"
 	healthHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/health/handlers"
-	sensorEntities "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	jobHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/jobs/handlers"
+	jobServices "github.com/worlder-team/microservice-server/microservice-b/modules/jobs/services"
+	retentionHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/retention/handlers"
+	retentionServices "github.com/worlder-team/microservice-server/microservice-b/modules/retention/services"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/cache"
 	sensorGrpc "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/grpc"
 	sensorHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/handlers"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/importer"
 	sensorInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/mqtt"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pipeline"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pubsub"
 	sensorRepositories "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/repositories"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/rollups"
 	sensorServices "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/services"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/streams"
 	sharedServices "github.com/worlder-team/microservice-server/microservice-b/modules/shared/services"
 	"github.com/worlder-team/microservice-server/microservice-b/routes"
+	"github.com/worlder-team/microservice-server/shared/constants"
 	sharedMiddleware "github.com/worlder-team/microservice-server/shared/middleware"
 	"github.com/worlder-team/microservice-server/shared/utils"
 )
@@ -44,10 +70,15 @@ func main() {
 	}
 	defer utils.Sync()
 
+	// Schema migrations subcommand
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
 	// Database connection
 	db, err := initDatabase(cfg)
 	if err != nil {
-		utils.Fatal("Failed to connect to database")
+		utils.Fatal(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
 
 	// Run database seeding
@@ -55,30 +86,135 @@ func main() {
 	if err := seeder.SeedAll(); err != nil {
 		utils.Error(fmt.Sprintf("Database seeding failed: %v", err))
 	}
+	if cfg.GRPC.BootstrapAPIKey != "" {
+		if err := seeder.SeedAPIKey("bootstrap-generators", cfg.GRPC.BootstrapAPIKey, []string{entities.APIKeyScopeSensorWrite}); err != nil {
+			utils.Error(fmt.Sprintf("API key seeding failed: %v", err))
+		}
+	}
 
 	// Redis connection
 	redisClient := initRedis(cfg)
 
 	// Initialize repositories
-	sensorRepo := sensorRepositories.NewSensorRepository(db)
+	sensorRepo := rollups.NewRepository(sensorRepositories.NewSensorRepository(db), db)
+	ingestPipeline := pipeline.NewPipeline(sensorRepo, pipeline.Config{
+		QueueSize:     cfg.Ingest.QueueSize,
+		Workers:       cfg.Ingest.Workers,
+		MaxBatchSize:  cfg.Ingest.MaxBatchSize,
+		FlushInterval: cfg.Ingest.FlushInterval,
+		RetryAfter:    cfg.Ingest.RetryAfter,
+	})
 
 	// Initialize JWT service
 	jwtService := authServices.NewJWTService(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.Expiration)
 
 	// Initialize services
-	sensorService := sensorServices.NewSensorService(sensorRepo)
+	sensorHub := pubsub.NewHub(cfg.Watch.BufferSize, pubsub.ParseSlowConsumerPolicy(cfg.Watch.SlowConsumerPolicy))
+	var sensorRelay *pubsub.Relay
+	publishers := []sensorInterfaces.SensorDataPublisher{sensorHub}
+	if cfg.Live.RedisFanout {
+		// Every replica's hub is fed from the channel, including with the readings it stored itself
+		sensorRelay = pubsub.NewRelay(redisClient, cfg.Live.Channel, sensorHub)
+		sensorRelay.Start()
+		publishers = []sensorInterfaces.SensorDataPublisher{pubsub.NewBroadcaster(redisClient, cfg.Live.Channel, time.Second)}
+		utils.Info(fmt.Sprintf("Fanning out live sensor data through channel %s", cfg.Live.Channel))
+	}
+	if cfg.Streams.ChangesEnabled {
+		publishers = append(publishers, streams.NewPublisher(redisClient, streams.PublisherConfig{
+			Stream: cfg.Streams.ChangesStream,
+			MaxLen: int64(cfg.Streams.ChangesMaxLen),
+		}))
+		utils.Info(fmt.Sprintf("Publishing stored sensor data to stream %s", cfg.Streams.ChangesStream))
+	}
+	var alertEvaluator *alertServices.Evaluator
+	if cfg.Alert.Enabled {
+		alertEvaluator = alertServices.NewEvaluator(db, alertServices.EvaluatorConfig{
+			RefreshInterval: cfg.Alert.RefreshInterval,
+			MaxReadingAge:   cfg.Alert.MaxReadingAge,
+			QueueSize:       cfg.Alert.QueueSize,
+		})
+		alertEvaluator.Start()
+		publishers = append(publishers, alertEvaluator)
+		utils.Info("Evaluating alert rules on ingestion")
+	}
+	var sensorService sensorInterfaces.SensorServiceInterface = sensorServices.NewSensorService(ingestPipeline, publishers...)
+	var sensorCache sensorInterfaces.CacheInvalidator
+	if cfg.Cache.TTL > 0 {
+		cacheService := cache.NewService(sensorService, redisClient, cache.Config{
+			TTL:     cfg.Cache.TTL,
+			Timeout: cfg.Cache.Timeout,
+		})
+		sensorService, sensorCache = cacheService, cacheService
+		utils.Info(fmt.Sprintf("Caching sensor data reads in Redis for %s", cfg.Cache.TTL))
+	}
 	authService := authServices.NewAuthService(db, jwtService)
+	apiKeyService := authServices.NewAPIKeyService(db)
+	generatorRegistry := generatorServices.NewRegistryService()
+	jobService := jobServices.NewJobService(db)
+	auditService := auditServices.NewAuditService(db)
+	alertService := alertServices.NewAlertService(db, alertEvaluator)
+	retentionService := retentionServices.NewRetentionService(db, retentionServices.Config{
+		DeletedGrace:   cfg.Retention.DeletedGrace,
+		ChunkSize:      cfg.Retention.ChunkSize,
+		ChunkPause:     cfg.Retention.ChunkPause,
+		WaitForRollups: cfg.Rollup.Enabled,
+	}, sensorCache)
 
 	// Initialize handlers
 	sensorHandler := sensorHandlers.NewSensorHandler(sensorService)
 	authHandler := authHandlers.NewAuthHandler(authService)
+	apiKeyHandler := authHandlers.NewAPIKeyHandler(apiKeyService)
+	generatorHandler := generatorHandlers.NewGeneratorHandler(generatorRegistry)
+	ingestHandler := sensorHandlers.NewIngestHandler(sensorGrpc.NewSensorServer(sensorService, sensorHub))
+	importHandler := sensorHandlers.NewImportHandler(importer.NewImporter(sensorService, cfg.Import.ChunkSize), jobService, cfg.Import.SyncMaxSize)
+	deleteHandler := sensorHandlers.NewDeleteQueryHandler(sensorService, jobService, auditService,
+		sensorServices.NewDeleteConfirmer(cfg.JWT.Secret, cfg.Delete.ConfirmationTTL), cfg.Delete.ChunkSize, cfg.Delete.SyncMaxRows)
+	trashHandler := sensorHandlers.NewTrashHandler(sensorService, auditService, cfg.Delete.ChunkSize)
+	jobHandler := jobHandlers.NewJobHandler(jobService)
+	retentionHandler := retentionHandlers.NewRetentionHandler(retentionService)
+	auditHandler := auditHandlers.NewAuditHandler(auditService)
+	alertHandler := alertHandlers.NewAlertHandler(alertService)
 	healthHandler := healthHandlers.NewHealthHandler()
 
+	liveHandler := sensorHandlers.NewLiveHandler(sensorHub, cfg.Live.HeartbeatInterval, cfg.Live.WriteTimeout, cfg.Live.MaxConnections)
+
 	// Initialize router
-	router := routes.NewRouter(sensorHandler, authHandler, healthHandler, jwtService, cfg)
+	router := routes.NewRouter(sensorHandler, liveHandler, authHandler, apiKeyHandler, generatorHandler, ingestHandler, importHandler, deleteHandler, trashHandler, jobHandler, retentionHandler, auditHandler, alertHandler, healthHandler, jwtService, apiKeyService, cfg)
 
 	// Start gRPC server in goroutine
-	go startGRPCServer(sensorService, cfg)
+	var grpcTLSConfig *tls.Config
+	var grpcCertReloader *utils.CertReloader
+	if cfg.GRPC.TLSEnabled {
+		grpcTLSConfig, grpcCertReloader, err = utils.NewServerTLSConfig(cfg.GRPC.TLS)
+		if err != nil {
+			utils.Fatal(fmt.Sprintf("Failed to load gRPC TLS configuration: %v", err))
+		}
+	}
+	go startGRPCServer(sensorService, sensorHub, generatorRegistry, apiKeyService, grpcTLSConfig, cfg)
+
+	// Start rollup job
+	var rollupJob *rollups.Job
+	if cfg.Rollup.Enabled {
+		rollupJob = rollups.NewJob(db, rollups.JobConfig{
+			Interval:  cfg.Rollup.Interval,
+			Lag:       cfg.Rollup.Lag,
+			MaxWindow: cfg.Rollup.MaxWindow,
+		}, sensorCache)
+		rollupJob.Start()
+	}
+
+	// Start retention purge
+	var retentionScheduler *retentionServices.Scheduler
+	if cfg.Retention.Enabled {
+		retentionScheduler = retentionServices.NewScheduler(retentionService, cfg.Retention.Interval)
+		retentionScheduler.Start()
+	}
+
+	// Start MQTT ingestion gateway
+	mqttSubscriber := startMQTTSubscriber(sensorService, cfg)
+
+	// Start Redis Stream ingestion
+	streamConsumer := startStreamConsumer(redisClient, sensorService, cfg)
 
 	// Initialize Echo
 	e := echo.New()
@@ -86,6 +222,7 @@ func main() {
 
 	// Middleware
 	e.Use(middleware.Logger())
+	e.Use(sharedMiddleware.RedactQueryParams(constants.AccessTokenParam))
 	e.Use(middleware.Recover())
 	e.Use(sharedMiddleware.CORS())
 	e.Use(sharedMiddleware.SecurityHeaders())
@@ -114,27 +251,62 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Live feeds never end on their own, close them so that Shutdown does not wait for them
+	if sensorRelay != nil {
+		sensorRelay.Close()
+	}
+	sensorHub.Close()
+
 	if err := e.Shutdown(ctx); err != nil {
 		utils.Error("Server forced to shutdown")
 	}
+	if mqttSubscriber != nil {
+		mqttSubscriber.Close()
+	}
+	if streamConsumer != nil {
+		streamConsumer.Close()
+	}
+	jobService.Close()
+	ingestPipeline.Close()
+	if alertEvaluator != nil {
+		alertEvaluator.Close()
+	}
+	if rollupJob != nil {
+		rollupJob.Close()
+	}
+	if retentionScheduler != nil {
+		retentionScheduler.Close()
+	}
+	if grpcCertReloader != nil {
+		grpcCertReloader.Close()
+	}
 
 	utils.Info("Server stopped")
 }
 
 func initDatabase(cfg *configs.Config) (*gorm.DB, error) {
-	dsn := cfg.GetDSN()
-
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := database.Open(cfg.Database.Driver, cfg.GetDSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Auto migrate the schema
-	if err := db.AutoMigrate(&sensorEntities.SensorData{}, &entities.User{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	migrator, err := migrate.New(db, migrations.All(), cfg.Migrate.LockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Migrate.OnStart {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		for _, migration := range applied {
+			utils.Info(fmt.Sprintf("Applied migration %d %s", migration.Version, migration.Name))
+		}
+	} else if err := migrator.Check(context.Background()); err != nil {
+		return nil, fmt.Errorf("refusing to start, run the migrate command first: %w", err)
 	}
 
-	utils.Info("Connected to database successfully")
+	utils.Info(fmt.Sprintf("Connected to %s database successfully", cfg.Database.Driver))
 	return db, nil
 }
 
@@ -148,14 +320,86 @@ func initRedis(cfg *configs.Config) *redis.Client {
 	return client
 }
 
-func startGRPCServer(sensorService sensorInterfaces.SensorServiceInterface, cfg *configs.Config) {
+func startMQTTSubscriber(sensorService sensorInterfaces.SensorServiceInterface, cfg *configs.Config) *mqtt.Subscriber {
+	if !cfg.MQTT.Enabled {
+		return nil
+	}
+
+	subscriber, err := mqtt.NewSubscriber(sensorService, mqtt.Config{
+		BrokerURL:     cfg.MQTT.BrokerURL,
+		ClientID:      cfg.MQTT.ClientID,
+		Username:      cfg.MQTT.Username,
+		Password:      cfg.MQTT.Password,
+		Topics:        cfg.MQTT.Topics,
+		SharedGroup:   cfg.MQTT.SharedGroup,
+		QoS:           byte(cfg.MQTT.QoS),
+		PayloadFormat: cfg.MQTT.PayloadFormat,
+	})
+	if err != nil {
+		utils.Fatal(fmt.Sprintf("Invalid MQTT configuration: %v", err))
+	}
+
+	subscriber.Start()
+	return subscriber
+}
+
+func startStreamConsumer(redisClient *redis.Client, sensorService sensorInterfaces.SensorServiceInterface, cfg *configs.Config) *streams.Consumer {
+	if !cfg.Streams.IngestEnabled {
+		return nil
+	}
+
+	consumer := streams.NewConsumer(redisClient, sensorService, streams.ConsumerConfig{
+		Stream:           cfg.Streams.IngestStream,
+		Group:            cfg.Streams.IngestGroup,
+		Consumer:         cfg.Streams.IngestConsumer,
+		DeadLetterStream: cfg.Streams.DeadLetterStream,
+		BatchSize:        int64(cfg.Streams.BatchSize),
+		ClaimMinIdle:     cfg.Streams.ClaimMinIdle,
+		ReapInterval:     cfg.Streams.ReapInterval,
+		MaxDeliveries:    int64(cfg.Streams.MaxDeliveries),
+	})
+	if err := consumer.Start(); err != nil {
+		utils.Error(fmt.Sprintf("Failed to start Redis Stream consumer: %v", err))
+		return nil
+	}
+	return consumer
+}
+
+func startGRPCServer(sensorService sensorInterfaces.SensorServiceInterface, sensorHub *pubsub.Hub, generatorRegistry generatorInterfaces.GeneratorRegistryInterface, apiKeyService authInterfaces.APIKeyServiceInterface, tlsConfig *tls.Config, cfg *configs.Config) {
 	lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
 	if err != nil {
 		utils.Fatal(fmt.Sprintf("Failed to listen on port %s", cfg.GRPC.Port))
 	}
 
-	s := grpc.NewServer()
-	sensorGrpc.RegisterSensorServer(s, sensorService)
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		if cfg.GRPC.TLS.ClientAuth {
+			utils.Info("gRPC mutual TLS enabled")
+		} else {
+			utils.Info("gRPC TLS enabled")
+		}
+	} else {
+		utils.Warn("gRPC TLS is disabled, traffic is sent in plaintext")
+	}
+
+	if cfg.GRPC.AuthEnabled {
+		methodScopes := make(map[string]string)
+		maps.Copy(methodScopes, sensorGrpc.MethodScopes)
+		maps.Copy(methodScopes, generatorGrpc.MethodScopes)
+
+		authenticator := authGrpc.NewAPIKeyAuthenticator(apiKeyService, methodScopes, sensorGrpc.PublicMethods...)
+		opts = append(opts,
+			grpc.UnaryInterceptor(authenticator.UnaryInterceptor()),
+			grpc.StreamInterceptor(authenticator.StreamInterceptor()),
+		)
+	} else {
+		utils.Warn("gRPC API key authentication is disabled")
+	}
+
+	s := grpc.NewServer(opts...)
+	sensorGrpc.RegisterSensorServer(s, sensorService, sensorHub)
+	generatorGrpc.RegisterControlServer(s, generatorRegistry)
 
 	utils.Info(fmt.Sprintf("Starting gRPC server on port %s", cfg.GRPC.Port))
 	if err := s.Serve(lis); err != nil {
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/worlder-team/microservice-server/microservice-b/configs"
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrate"
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrations"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  status          list the migrations and whether they are applied
+  up              apply all pending migrations
+  down [steps]    revert the last steps applied migrations (default 1)
+  to <version>    apply or revert migrations until version is the latest applied one, 0 reverts all`
+
+// runMigrate runs the migrate subcommand with args, returning the exit code
+func runMigrate(cfg *configs.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := database.Open(cfg.Database.Driver, cfg.GetDSN())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 1
+	}
+	migrator, err := migrate.New(db, migrations.All(), cfg.Migrate.LockTimeout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	ctx := context.Background()
+	var done []migrate.Migration
+	verb := "Applied"
+
+	switch command := args[0]; {
+	case command == "status" && len(args) == 1:
+		return printStatus(ctx, migrator)
+	case command == "up" && len(args) == 1:
+		done, err = migrator.Up(ctx)
+	case command == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, "steps must be a positive integer")
+				return 2
+			}
+		}
+		verb = "Reverted"
+		done, err = migrator.Down(ctx, steps)
+	case command == "to" && len(args) == 2:
+		version, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil || version < 0 {
+			fmt.Fprintln(os.Stderr, "version must be a migration version or 0")
+			return 2
+		}
+		verb = "Migrated"
+		done, err = migrator.To(ctx, version)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	for _, migration := range done {
+		fmt.Printf("%s %d %s\n", verb, migration.Version, migration.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(done) == 0 {
+		fmt.Println("Nothing to do")
+	}
+	return 0
+}
+
+// printStatus prints the migration status table, returning the exit code
+func printStatus(ctx context.Context, migrator *migrate.Migrator) int {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		if status.Unknown {
+			state = "unknown"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+	return 0
+}
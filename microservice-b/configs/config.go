@@ -1,8 +1,13 @@
 package configs
 
 import (
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/labstack/gommon/bytes"
+
 	"github.com/worlder-team/microservice-server/shared/utils"
 )
 
@@ -10,11 +15,22 @@ import (
 type Config struct {
 	Server    ServerConfig
 	Database  DatabaseConfig
+	Migrate   MigrateConfig
 	Redis     RedisConfig
 	GRPC      GRPCConfig
 	JWT       JWTConfig
 	RateLimit RateLimitConfig
 	Cache     CacheConfig
+	Watch     WatchConfig
+	Live      LiveConfig
+	Ingest    IngestConfig
+	MQTT      MQTTConfig
+	Streams   StreamsConfig
+	Rollup    RollupConfig
+	Retention RetentionConfig
+	Import    ImportConfig
+	Delete    DeleteQueryConfig
+	Alert     AlertConfig
 }
 
 // ServerConfig holds HTTP server configuration
@@ -27,11 +43,20 @@ type ServerConfig struct {
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
+	Driver   string // mysql, postgres or sqlite
 	Host     string
 	Port     string
 	Name     string
 	Username string
 	Password string
+	SSLMode  string // PostgreSQL sslmode
+	Path     string // SQLite database file
+}
+
+// MigrateConfig holds schema migration configuration
+type MigrateConfig struct {
+	OnStart     bool          // Apply pending migrations on startup, otherwise refuse to start unless the schema is current
+	LockTimeout time.Duration // How long to wait for another replica holding the migration lock
 }
 
 // RedisConfig holds Redis configuration
@@ -43,7 +68,11 @@ type RedisConfig struct {
 
 // GRPCConfig holds gRPC server configuration
 type GRPCConfig struct {
-	Port string
+	Port            string
+	AuthEnabled     bool   // Require generator API keys on ingestion RPCs
+	BootstrapAPIKey string // Raw API key seeded for the bundled generators (optional)
+	TLSEnabled      bool
+	TLS             utils.TLSOptions
 }
 
 // JWTConfig holds JWT configuration
@@ -58,13 +87,108 @@ type RateLimitConfig struct {
 	RequestsPerMinute int
 }
 
-// CacheConfig holds cache configuration
+// CacheConfig holds Redis query cache configuration
 type CacheConfig struct {
-	TTL time.Duration
+	TTL     time.Duration // Lifetime of cached reads, 0 disables the cache
+	Timeout time.Duration // Timeout of each cache round trip before falling back to the database
+}
+
+// WatchConfig holds live subscription configuration
+type WatchConfig struct {
+	BufferSize         int    // Maximum readings buffered per subscriber
+	SlowConsumerPolicy string // drop_oldest or disconnect
+}
+
+// LiveConfig holds WebSocket and SSE feed configuration
+type LiveConfig struct {
+	RedisFanout       bool          // Deliver readings ingested by any replica through Redis pub/sub
+	Channel           string        // Redis pub/sub channel of the fan-out
+	HeartbeatInterval time.Duration // How often idle connections are sent a heartbeat
+	WriteTimeout      time.Duration // Connections whose writes block this long are closed
+	MaxConnections    int           // Live connections per replica, 0 for no limit
+}
+
+// IngestConfig holds ingestion pipeline configuration
+type IngestConfig struct {
+	QueueSize     int           // Maximum queued write requests before callers get ResourceExhausted
+	Workers       int           // Concurrent database writers
+	MaxBatchSize  int           // Maximum rows coalesced into one insert
+	FlushInterval time.Duration // How long a writer waits to coalesce more requests
+	RetryAfter    time.Duration // Retry hint sent when the queue is full
+}
+
+// MQTTConfig holds MQTT ingestion gateway configuration
+type MQTTConfig struct {
+	Enabled       bool
+	BrokerURL     string
+	ClientID      string
+	Username      string
+	Password      string
+	Topics        []string // Topic patterns with {sensor_type}, {id1} and {id2} placeholders
+	QoS           int
+	PayloadFormat string // auto, json or protobuf
+	SharedGroup   string // Shared subscription group so replicas split the messages
+}
+
+// StreamsConfig holds Redis Streams ingestion and change feed configuration
+type StreamsConfig struct {
+	IngestEnabled    bool
+	IngestStream     string
+	IngestGroup      string
+	IngestConsumer   string
+	DeadLetterStream string
+	BatchSize        int
+	ClaimMinIdle     time.Duration // Pending entries idle this long are retried by another read
+	ReapInterval     time.Duration
+	MaxDeliveries    int // Deliveries before an entry is moved to the dead-letter stream
+	ChangesEnabled   bool
+	ChangesStream    string
+	ChangesMaxLen    int // Approximate length cap of the change feed stream
+}
+
+// RollupConfig holds rollup job configuration
+type RollupConfig struct {
+	Enabled   bool
+	Interval  time.Duration // How often new data is rolled up
+	Lag       time.Duration // Grace period for in-flight writes before they are rolled up
+	MaxWindow time.Duration // Span of modifications processed per step while catching up
+}
+
+// RetentionConfig holds retention purge configuration
+type RetentionConfig struct {
+	Enabled      bool
+	Interval     time.Duration // How often the retention policies are enforced
+	DeletedGrace time.Duration // How long soft-deleted readings are kept, 0 keeps them forever
+	ChunkSize    int           // Rows removed per statement
+	ChunkPause   time.Duration // Pause between statements
+}
+
+// AlertConfig holds alert rule evaluation configuration
+type AlertConfig struct {
+	Enabled         bool          // Evaluate the alert rules against ingested readings on this replica
+	RefreshInterval time.Duration // How often rule and alert changes made on other replicas are picked up
+	MaxReadingAge   time.Duration // Older readings are not evaluated, 0 evaluates all
+	QueueSize       int           // Alert changes waiting to be written before ingestion blocks
+}
+
+// ImportConfig holds bulk import configuration
+type ImportConfig struct {
+	MaxSize     string // Largest accepted upload, e.g. 1G
+	SyncMaxSize int64  // Uploads up to this many bytes are imported within the request, larger ones as jobs
+	ChunkSize   int    // Rows stored per batch
+}
+
+// DeleteQueryConfig holds delete-by-filter configuration
+type DeleteQueryConfig struct {
+	ConfirmationTTL time.Duration // How long a dry run's confirmation token is valid
+	ChunkSize       int           // Rows deleted per statement
+	SyncMaxRows     int64         // Deletes of up to this many rows run within the request, larger ones as jobs
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
+	driver := utils.GetEnvOrDefault("DB_DRIVER", "mysql")
+
 	return &Config{
 		Server: ServerConfig{
 			Port:         utils.GetEnvOrDefault("MICROSERVICE_B_PORT", "8080"),
@@ -73,11 +197,18 @@ func LoadConfig() *Config {
 			WriteTimeout: utils.ParseDurationOrZero(utils.GetEnvOrDefault("WRITE_TIMEOUT", "10s")),
 		},
 		Database: DatabaseConfig{
+			Driver:   driver,
 			Host:     utils.GetEnvOrDefault("DB_HOST", "localhost"),
-			Port:     utils.GetEnvOrDefault("DB_PORT", "3306"),
+			Port:     utils.GetEnvOrDefault("DB_PORT", defaultDBPort(driver)),
 			Name:     utils.GetEnvOrDefault("DB_NAME", "sensor_data"),
 			Username: utils.GetEnvOrDefault("DB_USER", "root"),
 			Password: utils.GetEnvOrDefault("DB_PASSWORD", "password"),
+			SSLMode:  utils.GetEnvOrDefault("DB_SSLMODE", "disable"),
+			Path:     utils.GetEnvOrDefault("DB_PATH", "sensor_data.db"),
+		},
+		Migrate: MigrateConfig{
+			OnStart:     utils.ParseBool(utils.GetEnvOrDefault("MIGRATE_ON_START", "true")),
+			LockTimeout: utils.ParseDurationOrZero(utils.GetEnvOrDefault("MIGRATE_LOCK_TIMEOUT", "1m")),
 		},
 		Redis: RedisConfig{
 			Host:     utils.GetEnvOrDefault("REDIS_HOST", "localhost"),
@@ -85,7 +216,17 @@ func LoadConfig() *Config {
 			Password: utils.GetEnvOrDefault("REDIS_PASSWORD", ""),
 		},
 		GRPC: GRPCConfig{
-			Port: utils.GetEnvOrDefault("GRPC_PORT", "50051"),
+			Port:            utils.GetEnvOrDefault("GRPC_PORT", "50051"),
+			AuthEnabled:     utils.ParseBool(utils.GetEnvOrDefault("GRPC_AUTH_ENABLED", "true")),
+			BootstrapAPIKey: utils.GetEnvOrDefault("GRPC_BOOTSTRAP_API_KEY", ""),
+			TLSEnabled:      utils.ParseBool(utils.GetEnvOrDefault("GRPC_TLS_ENABLED", "false")),
+			TLS: utils.TLSOptions{
+				CertFile:       utils.GetEnvOrDefault("GRPC_TLS_CERT_FILE", ""),
+				KeyFile:        utils.GetEnvOrDefault("GRPC_TLS_KEY_FILE", ""),
+				CAFile:         utils.GetEnvOrDefault("GRPC_TLS_CA_FILE", ""),
+				ClientAuth:     utils.ParseBool(utils.GetEnvOrDefault("GRPC_TLS_CLIENT_AUTH", "false")),
+				ReloadInterval: utils.ParseDurationOrZero(utils.GetEnvOrDefault("GRPC_TLS_RELOAD_INTERVAL", "1m")),
+			},
 		},
 		JWT: JWTConfig{
 			Secret:     utils.GetEnvOrDefault("JWT_SECRET", "your-super-secret-jwt-key-here"),
@@ -96,17 +237,152 @@ func LoadConfig() *Config {
 			RequestsPerMinute: utils.ParseInt(utils.GetEnvOrDefault("RATE_LIMIT", "100")),
 		},
 		Cache: CacheConfig{
-			TTL: utils.ParseDurationOrZero(utils.GetEnvOrDefault("CACHE_TTL_SECONDS", "300s")),
+			TTL:     time.Duration(utils.ParseInt(utils.GetEnvOrDefault("CACHE_TTL_SECONDS", "300"))) * time.Second,
+			Timeout: utils.ParseDurationOrZero(utils.GetEnvOrDefault("CACHE_TIMEOUT", "100ms")),
+		},
+		Watch: WatchConfig{
+			BufferSize:         utils.ParseInt(utils.GetEnvOrDefault("WATCH_BUFFER_SIZE", "256")),
+			SlowConsumerPolicy: utils.GetEnvOrDefault("WATCH_SLOW_CONSUMER_POLICY", "drop_oldest"),
+		},
+		Live: LiveConfig{
+			RedisFanout:       utils.ParseBool(utils.GetEnvOrDefault("LIVE_REDIS_FANOUT", "true")),
+			Channel:           utils.GetEnvOrDefault("LIVE_REDIS_CHANNEL", "sensor-data:live"),
+			HeartbeatInterval: utils.ParseDurationOrZero(utils.GetEnvOrDefault("LIVE_HEARTBEAT_INTERVAL", "15s")),
+			WriteTimeout:      utils.ParseDurationOrZero(utils.GetEnvOrDefault("LIVE_WRITE_TIMEOUT", "10s")),
+			MaxConnections:    utils.ParseInt(utils.GetEnvOrDefault("LIVE_MAX_CONNECTIONS", "1000")),
+		},
+		Ingest: IngestConfig{
+			QueueSize:     utils.ParseInt(utils.GetEnvOrDefault("INGEST_QUEUE_SIZE", "1000")),
+			Workers:       utils.ParseInt(utils.GetEnvOrDefault("INGEST_WORKERS", "4")),
+			MaxBatchSize:  utils.ParseInt(utils.GetEnvOrDefault("INGEST_MAX_BATCH_SIZE", "500")),
+			FlushInterval: utils.ParseDurationOrZero(utils.GetEnvOrDefault("INGEST_FLUSH_INTERVAL", "10ms")),
+			RetryAfter:    utils.ParseDurationOrZero(utils.GetEnvOrDefault("INGEST_RETRY_AFTER", "1s")),
+		},
+		MQTT: MQTTConfig{
+			Enabled:       utils.ParseBool(utils.GetEnvOrDefault("MQTT_ENABLED", "false")),
+			BrokerURL:     utils.GetEnvOrDefault("MQTT_BROKER_URL", "tcp://localhost:1883"),
+			ClientID:      utils.GetEnvOrDefault("MQTT_CLIENT_ID", defaultMQTTClientID()),
+			Username:      utils.GetEnvOrDefault("MQTT_USERNAME", ""),
+			Password:      utils.GetEnvOrDefault("MQTT_PASSWORD", ""),
+			Topics:        splitList(utils.GetEnvOrDefault("MQTT_TOPICS", "sensors/{sensor_type}/{id1}/{id2}")),
+			QoS:           utils.ParseInt(utils.GetEnvOrDefault("MQTT_QOS", "1")),
+			PayloadFormat: utils.GetEnvOrDefault("MQTT_PAYLOAD_FORMAT", "auto"),
+			SharedGroup:   utils.GetEnvOrDefault("MQTT_SHARED_GROUP", ""),
+		},
+		Streams: StreamsConfig{
+			IngestEnabled:    utils.ParseBool(utils.GetEnvOrDefault("STREAM_INGEST_ENABLED", "false")),
+			IngestStream:     utils.GetEnvOrDefault("STREAM_INGEST_NAME", "sensor-data:ingest"),
+			IngestGroup:      utils.GetEnvOrDefault("STREAM_INGEST_GROUP", "microservice-b"),
+			IngestConsumer:   utils.GetEnvOrDefault("STREAM_INGEST_CONSUMER", defaultStreamConsumer()),
+			DeadLetterStream: utils.GetEnvOrDefault("STREAM_INGEST_DEAD_LETTER", "sensor-data:ingest:dead"),
+			BatchSize:        utils.ParseInt(utils.GetEnvOrDefault("STREAM_INGEST_BATCH_SIZE", "100")),
+			ClaimMinIdle:     utils.ParseDurationOrZero(utils.GetEnvOrDefault("STREAM_INGEST_CLAIM_MIN_IDLE", "1m")),
+			ReapInterval:     utils.ParseDurationOrZero(utils.GetEnvOrDefault("STREAM_INGEST_REAP_INTERVAL", "30s")),
+			MaxDeliveries:    utils.ParseInt(utils.GetEnvOrDefault("STREAM_INGEST_MAX_DELIVERIES", "5")),
+			ChangesEnabled:   utils.ParseBool(utils.GetEnvOrDefault("STREAM_CHANGES_ENABLED", "false")),
+			ChangesStream:    utils.GetEnvOrDefault("STREAM_CHANGES_NAME", "sensor-data:changes"),
+			ChangesMaxLen:    utils.ParseInt(utils.GetEnvOrDefault("STREAM_CHANGES_MAX_LEN", "100000")),
+		},
+		Rollup: RollupConfig{
+			Enabled:   utils.ParseBool(utils.GetEnvOrDefault("ROLLUP_ENABLED", "true")),
+			Interval:  utils.ParseDurationOrZero(utils.GetEnvOrDefault("ROLLUP_INTERVAL", "1m")),
+			Lag:       utils.ParseDurationOrZero(utils.GetEnvOrDefault("ROLLUP_LAG", "30s")),
+			MaxWindow: utils.ParseDurationOrZero(utils.GetEnvOrDefault("ROLLUP_MAX_WINDOW", "1h")),
+		},
+		Retention: RetentionConfig{
+			Enabled:      utils.ParseBool(utils.GetEnvOrDefault("RETENTION_ENABLED", "true")),
+			Interval:     utils.ParseDurationOrZero(utils.GetEnvOrDefault("RETENTION_INTERVAL", "1h")),
+			DeletedGrace: utils.ParseDurationOrZero(utils.GetEnvOrDefault("RETENTION_DELETED_GRACE", "168h")),
+			ChunkSize:    utils.ParseInt(utils.GetEnvOrDefault("RETENTION_CHUNK_SIZE", "1000")),
+			ChunkPause:   utils.ParseDurationOrZero(utils.GetEnvOrDefault("RETENTION_CHUNK_PAUSE", "100ms")),
+		},
+		Alert: AlertConfig{
+			Enabled:         utils.ParseBool(utils.GetEnvOrDefault("ALERT_ENABLED", "true")),
+			RefreshInterval: utils.ParseDurationOrZero(utils.GetEnvOrDefault("ALERT_REFRESH_INTERVAL", "30s")),
+			MaxReadingAge:   utils.ParseDurationOrZero(utils.GetEnvOrDefault("ALERT_MAX_READING_AGE", "1h")),
+			QueueSize:       utils.ParseInt(utils.GetEnvOrDefault("ALERT_QUEUE_SIZE", "1000")),
+		},
+		Import: ImportConfig{
+			MaxSize:     utils.GetEnvOrDefault("IMPORT_MAX_SIZE", "1G"),
+			SyncMaxSize: parseSize(utils.GetEnvOrDefault("IMPORT_SYNC_MAX_SIZE", "10M")),
+			ChunkSize:   utils.ParseInt(utils.GetEnvOrDefault("IMPORT_CHUNK_SIZE", "500")),
+		},
+		Delete: DeleteQueryConfig{
+			ConfirmationTTL: utils.ParseDurationOrZero(utils.GetEnvOrDefault("DELETE_QUERY_CONFIRMATION_TTL", "5m")),
+			ChunkSize:       utils.ParseInt(utils.GetEnvOrDefault("DELETE_QUERY_CHUNK_SIZE", "1000")),
+			SyncMaxRows:     int64(utils.ParseInt(utils.GetEnvOrDefault("DELETE_QUERY_SYNC_MAX_ROWS", "10000"))),
 		},
 	}
 }
 
-// GetDSN returns database connection string
+// GetDSN returns the connection string of the configured database driver
 func (c *Config) GetDSN() string {
-	return c.Database.Username + ":" + c.Database.Password + "@tcp(" + c.Database.Host + ":" + c.Database.Port + ")/" + c.Database.Name + "?charset=utf8mb4&parseTime=True&loc=Local"
+	switch c.Database.Driver {
+	case "postgres":
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(c.Database.Username, c.Database.Password),
+			Host:     c.Database.Host + ":" + c.Database.Port,
+			Path:     "/" + c.Database.Name,
+			RawQuery: url.Values{"sslmode": {c.Database.SSLMode}, "TimeZone": {"UTC"}}.Encode(),
+		}
+		return dsn.String()
+	case "sqlite":
+		// WAL and the busy timeout let readers proceed while the ingestion workers write,
+		// immediate transactions avoid upgrade deadlocks between concurrent writers
+		return c.Database.Path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate"
+	default:
+		return c.Database.Username + ":" + c.Database.Password + "@tcp(" + c.Database.Host + ":" + c.Database.Port + ")/" + c.Database.Name + "?charset=utf8mb4&parseTime=True&loc=Local"
+	}
 }
 
 // GetRedisAddr returns Redis connection address
 func (c *Config) GetRedisAddr() string {
 	return c.Redis.Host + ":" + c.Redis.Port
 }
+
+// defaultDBPort returns the default port of the database driver
+func defaultDBPort(driver string) string {
+	if driver == "postgres" {
+		return "5432"
+	}
+	return "3306"
+}
+
+// defaultMQTTClientID derives a per-host MQTT client ID so replicas do not kick each other off the broker
+func defaultMQTTClientID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "microservice-b-" + utils.GenerateID(4)
+	}
+	return "microservice-b-" + hostname
+}
+
+// defaultStreamConsumer names the stream consumer after the host, each replica needs its own name
+func defaultStreamConsumer() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return utils.GenerateID(4)
+	}
+	return hostname
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseSize parses a byte size such as 10M, returning 0 if it is invalid
+func parseSize(value string) int64 {
+	size, err := bytes.Parse(value)
+	if err != nil {
+		return 0
+	}
+	return size
+}
@@ -0,0 +1,46 @@
+// Package database opens the microservice-b database with the configured driver and builds the
+// SQL that differs between drivers.
+package database
+
+import (
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Supported drivers
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Open connects to the database of driver with dsn. On PostgreSQL and SQLite, time arguments are
+// converted to UTC before they reach the database, see utcConnPool.
+func Open(driver, dsn string) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case DriverMySQL:
+		dialector = mysql.Open(dsn)
+	case DriverPostgres:
+		dialector = postgres.Open(dsn)
+	case DriverSQLite:
+		dialector = sqlite.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q, use %s, %s or %s", driver, DriverMySQL, DriverPostgres, DriverSQLite)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	if driver != DriverMySQL {
+		db.ConnPool = &utcConnPool{ConnPool: db.ConnPool}
+		db.Statement.ConnPool = db.ConnPool
+	}
+	return db, nil
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"gorm.io/gorm"
+)
+
+// Dialect builds the SQL that differs between drivers. Expressions take trusted column names and
+// integers only; values are passed as query arguments.
+type Dialect interface {
+	// BucketEpoch returns an integer expression flooring the time column to a multiple of seconds since the Unix epoch (UTC)
+	BucketEpoch(column string, seconds int64) string
+	// FromEpoch returns an expression converting Unix seconds to a UTC timestamp
+	FromEpoch(expr string) string
+	// DeleteLimit returns a statement deleting at most ? rows of table matching where; the limit is the last argument
+	DeleteLimit(table, where string) string
+	// TryLock returns a query taking the named session lock without waiting and selecting whether it
+	// was taken, or an empty query if the driver has no such locks
+	TryLock(name string) (string, []interface{})
+	// Unlock returns a statement releasing the named session lock
+	Unlock(name string) (string, []interface{})
+}
+
+// DialectOf returns the dialect of the driver db is connected with
+func DialectOf(db *gorm.DB) Dialect {
+	switch db.Dialector.Name() {
+	case DriverPostgres:
+		return postgresDialect{}
+	case DriverSQLite:
+		return sqliteDialect{}
+	default:
+		return mysqlDialect{}
+	}
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) BucketEpoch(column string, seconds int64) string {
+	return fmt.Sprintf("FLOOR(UNIX_TIMESTAMP(%s) / %d) * %d", column, seconds, seconds)
+}
+
+func (mysqlDialect) FromEpoch(expr string) string {
+	return "FROM_UNIXTIME(" + expr + ")"
+}
+
+func (mysqlDialect) DeleteLimit(table, where string) string {
+	return "DELETE FROM " + table + " WHERE " + where + " LIMIT ?"
+}
+
+func (mysqlDialect) TryLock(name string) (string, []interface{}) {
+	return "SELECT GET_LOCK(?, 0) = 1", []interface{}{name}
+}
+
+func (mysqlDialect) Unlock(name string) (string, []interface{}) {
+	return "SELECT RELEASE_LOCK(?)", []interface{}{name}
+}
+
+// postgresDialect relies on timestamp columns holding UTC wall clock times
+type postgresDialect struct{}
+
+func (postgresDialect) BucketEpoch(column string, seconds int64) string {
+	return fmt.Sprintf("CAST(FLOOR(EXTRACT(EPOCH FROM %s) / %d) * %d AS BIGINT)", column, seconds, seconds)
+}
+
+func (postgresDialect) FromEpoch(expr string) string {
+	return "(TO_TIMESTAMP(" + expr + ") AT TIME ZONE 'UTC')"
+}
+
+// DeleteLimit selects the rows by ctid, PostgreSQL has no DELETE ... LIMIT
+func (postgresDialect) DeleteLimit(table, where string) string {
+	return "DELETE FROM " + table + " WHERE ctid IN (SELECT ctid FROM " + table + " WHERE " + where + " LIMIT ?)"
+}
+
+// TryLock takes an advisory lock keyed by a hash of name, PostgreSQL advisory locks are keyed by integers
+func (postgresDialect) TryLock(name string) (string, []interface{}) {
+	return "SELECT pg_try_advisory_lock(?)", []interface{}{lockKey(name)}
+}
+
+func (postgresDialect) Unlock(name string) (string, []interface{}) {
+	return "SELECT pg_advisory_unlock(?)", []interface{}{lockKey(name)}
+}
+
+// lockKey derives the advisory lock key of name
+func lockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	return int64(hash.Sum64())
+}
+
+// sqliteDialect relies on times being stored as text in the driver's format, in UTC
+type sqliteDialect struct{}
+
+// BucketEpoch floors with integer arithmetic, SQLite may be built without math functions
+func (sqliteDialect) BucketEpoch(column string, seconds int64) string {
+	epoch := "CAST(strftime('%s', " + column + ") AS INTEGER)"
+	return fmt.Sprintf("(%[1]s - ((%[1]s %% %[2]d) + %[2]d) %% %[2]d)", epoch, seconds)
+}
+
+// FromEpoch formats the time the way the driver writes UTC times, so that stored times compare as text
+func (sqliteDialect) FromEpoch(expr string) string {
+	return "strftime('%Y-%m-%d %H:%M:%S+00:00', " + expr + ", 'unixepoch')"
+}
+
+// DeleteLimit selects the rows by rowid, SQLite is usually built without DELETE ... LIMIT
+func (sqliteDialect) DeleteLimit(table, where string) string {
+	return "DELETE FROM " + table + " WHERE rowid IN (SELECT rowid FROM " + table + " WHERE " + where + " LIMIT ?)"
+}
+
+// TryLock returns no query, writers to an SQLite database are serialized by its write lock
+func (sqliteDialect) TryLock(string) (string, []interface{}) {
+	return "", nil
+}
+
+func (sqliteDialect) Unlock(string) (string, []interface{}) {
+	return "", nil
+}
@@ -0,0 +1,385 @@
+// Package migrate applies versioned schema migrations, recording the applied versions in the
+// schema_migrations table. Runs take a database session lock so replicas starting together migrate
+// one at a time.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+)
+
+const (
+	// lockName names the session lock held while migrating
+	lockName = "microservice-b:schema_migrations"
+
+	lockPollInterval = 500 * time.Millisecond
+)
+
+var (
+	// ErrUnknownVersion is returned when the database has migrations applied that this build does not know,
+	// i.e. it was migrated by a newer build
+	ErrUnknownVersion = errors.New("database has migrations applied that this build does not know")
+	// ErrPending is returned by Check when migrations are not applied yet
+	ErrPending = errors.New("database has pending migrations")
+	// ErrIrreversible is returned when reverting a migration without a Down step
+	ErrIrreversible = errors.New("migration cannot be reverted")
+	// ErrLockTimeout is returned when another process holds the migration lock for too long
+	ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+)
+
+// Migration is a versioned schema change. Each step runs in a transaction together with the update of
+// schema_migrations; on MySQL, DDL statements commit implicitly, so a failing step may be partly applied.
+type Migration struct {
+	Version int64 // Unique, increasing version, e.g. 20260101000000
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // nil if the migration cannot be reverted
+}
+
+// SQL returns a migration step executing statements in order
+func SQL(statements ...string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// record is a row of schema_migrations
+type record struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+func (record) TableName() string {
+	return "schema_migrations"
+}
+
+// createTable creates schema_migrations; written out so that replicas creating it together do not conflict
+const createTable = "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+	"version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)"
+
+// Status describes a migration known to this build or applied to the database
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Unknown   bool       `json:"unknown"` // Applied but not known to this build
+}
+
+// Migrator applies a set of migrations to a database
+type Migrator struct {
+	db          *gorm.DB
+	migrations  []Migration
+	lockTimeout time.Duration
+}
+
+// New creates a migrator for migrations, which are sorted by version. lockTimeout bounds the wait for
+// another process holding the migration lock, 0 waits as long as the context allows.
+func New(db *gorm.DB, migrations []Migration, lockTimeout time.Duration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, migration := range sorted {
+		if migration.Version <= 0 || migration.Up == nil {
+			return nil, fmt.Errorf("migration %d %q needs a positive version and an Up step", migration.Version, migration.Name)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+	}
+	return &Migrator{db: db, migrations: sorted, lockTimeout: lockTimeout}, nil
+}
+
+// Latest returns the version of the newest known migration, 0 if there are none
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status lists the known migrations followed by unknown applied ones, by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+		statuses = m.status(applied)
+		return nil
+	})
+	return statuses, err
+}
+
+// Check returns ErrPending or ErrUnknownVersion, wrapped with the versions concerned, if the database
+// schema does not match the known migrations
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if unknown := versions(statuses, func(s Status) bool { return s.Unknown }); len(unknown) > 0 {
+		return fmt.Errorf("%w: %v", ErrUnknownVersion, unknown)
+	}
+	if pending := versions(statuses, func(s Status) bool { return !s.Applied }); len(pending) > 0 {
+		return fmt.Errorf("%w: %v", ErrPending, pending)
+	}
+	return nil
+}
+
+// Up applies all pending migrations, returning the applied ones
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the last steps applied migrations, returning the reverted ones
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.checkKnown(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			reverted, err := revert(conn, migration)
+			if err != nil {
+				return err
+			}
+			if reverted {
+				done = append(done, migration)
+			}
+		}
+		return nil
+	})
+	return done, err
+}
+
+// To migrates up or down to version: migrations up to version are applied and later ones reverted.
+// Version 0 reverts every migration. It returns the migrations applied or reverted, in order.
+func (m *Migrator) To(ctx context.Context, version int64) ([]Migration, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.checkKnown(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+				continue
+			}
+			reverted, err := revert(conn, migration)
+			if err != nil {
+				return err
+			}
+			if reverted {
+				done = append(done, migration)
+			}
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+			ran, err := apply(conn, migration)
+			if err != nil {
+				return err
+			}
+			if ran {
+				done = append(done, migration)
+			}
+		}
+		return nil
+	})
+	return done, err
+}
+
+// find returns the known migration of version, nil if there is none
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// checkKnown returns the applied versions, failing with ErrUnknownVersion if any of them is unknown
+func (m *Migrator) checkKnown(conn *gorm.DB) (map[int64]time.Time, error) {
+	applied, err := appliedVersions(conn)
+	if err != nil {
+		return nil, err
+	}
+	for version := range applied {
+		if m.find(version) == nil {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+	}
+	return applied, nil
+}
+
+func (m *Migrator) status(applied map[int64]time.Time) []Status {
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if at, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	for version, at := range applied {
+		if m.find(version) == nil {
+			at := at
+			statuses = append(statuses, Status{Version: version, Applied: true, AppliedAt: &at, Unknown: true})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+// withLock runs fn on a single connection holding the migration lock, after creating schema_migrations
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	if m.lockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.lockTimeout)
+		defer cancel()
+	}
+
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		dialect := database.DialectOf(conn)
+		if query, args := dialect.TryLock(lockName); query != "" {
+			if err := waitForLock(ctx, conn, query, args); err != nil {
+				return err
+			}
+			defer func() {
+				// The lock is released with the session otherwise, so a failure here is harmless
+				unlock, args := dialect.Unlock(lockName)
+				conn.WithContext(context.Background()).Exec(unlock, args...)
+			}()
+		}
+
+		// Migrations are not bound by the lock timeout
+		conn = conn.WithContext(context.WithoutCancel(ctx))
+		if err := conn.Exec(createTable).Error; err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+// waitForLock polls the lock query until the lock is taken or ctx is done
+func waitForLock(ctx context.Context, conn *gorm.DB, query string, args []interface{}) error {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var locked bool
+		if err := conn.Raw(query, args...).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("failed to take the migration lock: %w", err)
+		}
+		if locked {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrLockTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// appliedVersions returns the applied versions with the time they were applied
+func appliedVersions(conn *gorm.DB) (map[int64]time.Time, error) {
+	var records []record
+	if err := conn.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]time.Time, len(records))
+	for _, r := range records {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
+
+// apply runs the Up step of migration and records it, returning false if another process applied it meanwhile
+func apply(conn *gorm.DB, migration Migration) (bool, error) {
+	var applied bool
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if done, err := isApplied(tx, migration.Version); err != nil || done {
+			return err
+		}
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		applied = true
+		return tx.Create(&record{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to apply migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	return applied, nil
+}
+
+// revert runs the Down step of migration and removes its record, returning false if another process
+// reverted it meanwhile
+func revert(conn *gorm.DB, migration Migration) (bool, error) {
+	if migration.Down == nil {
+		return false, fmt.Errorf("%w: %d %s", ErrIrreversible, migration.Version, migration.Name)
+	}
+	var reverted bool
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if done, err := isApplied(tx, migration.Version); err != nil || !done {
+			return err
+		}
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		reverted = true
+		return tx.Where("version = ?", migration.Version).Delete(&record{}).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revert migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	return reverted, nil
+}
+
+// isApplied reports whether version is recorded as applied
+func isApplied(tx *gorm.DB, version int64) (bool, error) {
+	var count int64
+	err := tx.Model(&record{}).Where("version = ?", version).Count(&count).Error
+	return count > 0, err
+}
+
+// versions returns the versions of the statuses matching keep
+func versions(statuses []Status, keep func(Status) bool) []int64 {
+	var selected []int64
+	for _, status := range statuses {
+		if keep(status) {
+			selected = append(selected, status.Version)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,196 @@
+package migrations
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrate"
+)
+
+// baseline creates the schema microservice-b had when versioned migrations were introduced. Tables that
+// exist are left alone: databases created by the earlier AutoMigrate on startup already have the schema,
+// the migration then only records itself.
+var baseline = migrate.Migration{
+	Version: 20261018000000,
+	Name:    "baseline",
+	Up: func(tx *gorm.DB) error {
+		for _, model := range baselineModels {
+			if tx.Migrator().HasTable(model) {
+				continue
+			}
+			if err := tx.Migrator().CreateTable(model); err != nil {
+				return err
+			}
+		}
+		for _, table := range baselineRollupTables {
+			if tx.Migrator().HasTable(table) {
+				continue
+			}
+			if err := tx.Table(table).Migrator().CreateTable(&baselineSensorRollup{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range baselineRollupTables {
+			if err := tx.Migrator().DropTable(table); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropTable(baselineModels...)
+	},
+}
+
+var baselineModels = []interface{}{
+	&baselineSensorData{}, &baselineSensorDataHistory{}, &baselineRollupWatermark{}, &baselineUser{},
+	&baselineAPIKey{}, &baselineRetentionPolicy{}, &baselineJob{}, &baselineAuditEntry{},
+}
+
+var baselineRollupTables = []string{"sensor_rollups_1m", "sensor_rollups_1h", "sensor_rollups_1d"}
+
+type baselineSensorData struct {
+	ID          uint           `gorm:"primaryKey;autoIncrement"`
+	SensorValue float64        `gorm:"type:decimal(10,4);not null"`
+	SensorType  string         `gorm:"type:varchar(50);not null;index"`
+	ID1         string         `gorm:"type:varchar(50);not null;index:idx_id_combination"`
+	ID2         int32          `gorm:"not null;index:idx_id_combination"`
+	Timestamp   time.Time      `gorm:"type:timestamp;not null;index"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime;index"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (baselineSensorData) TableName() string {
+	return "sensor_data"
+}
+
+type baselineSensorDataValues struct {
+	SensorValue float64   `gorm:"type:decimal(10,4)"`
+	SensorType  string    `gorm:"type:varchar(50)"`
+	Timestamp   time.Time `gorm:"type:timestamp"`
+}
+
+type baselineSensorDataHistory struct {
+	ID           uint                      `gorm:"primaryKey;autoIncrement"`
+	SensorDataID uint                      `gorm:"not null;index"`
+	Action       string                    `gorm:"type:varchar(20);not null"`
+	Old          baselineSensorDataValues  `gorm:"embedded;embeddedPrefix:old_"`
+	New          *baselineSensorDataValues `gorm:"embedded;embeddedPrefix:new_"`
+	ActorID      uint                      `gorm:"index"`
+	ActorEmail   string                    `gorm:"type:varchar(255)"`
+	RequestID    string                    `gorm:"type:varchar(64)"`
+	CreatedAt    time.Time                 `gorm:"autoCreateTime"`
+}
+
+func (baselineSensorDataHistory) TableName() string {
+	return "sensor_data_history"
+}
+
+type baselineSensorRollup struct {
+	BucketStart time.Time `gorm:"type:timestamp;primaryKey;autoIncrement:false"`
+	SensorType  string    `gorm:"type:varchar(50);primaryKey"`
+	ID1         string    `gorm:"type:varchar(50);primaryKey"`
+	ID2         int32     `gorm:"primaryKey;autoIncrement:false"`
+	SampleCount int64     `gorm:"not null"`
+	MinValue    float64   `gorm:"not null"`
+	MaxValue    float64   `gorm:"not null"`
+	SumValue    float64   `gorm:"not null"`
+	FirstValue  float64   `gorm:"not null"`
+	FirstAt     time.Time `gorm:"type:timestamp;not null"`
+	LastValue   float64   `gorm:"not null"`
+	LastAt      time.Time `gorm:"type:timestamp;not null"`
+}
+
+type baselineRollupWatermark struct {
+	Name      string    `gorm:"type:varchar(50);primaryKey"`
+	Watermark time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (baselineRollupWatermark) TableName() string {
+	return "rollup_watermarks"
+}
+
+type baselineUser struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement"`
+	Username  string         `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Email     string         `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Password  string         `gorm:"type:varchar(255);not null"`
+	Role      string         `gorm:"type:varchar(50);default:'user';not null"`
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (baselineUser) TableName() string {
+	return "users"
+}
+
+type baselineAPIKey struct {
+	ID                 uint   `gorm:"primaryKey;autoIncrement"`
+	Name               string `gorm:"type:varchar(100);not null"`
+	KeyPrefix          string `gorm:"type:varchar(16);not null"`
+	KeyHash            string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Scopes             string `gorm:"type:varchar(255);not null"`
+	AllowedSensorTypes string `gorm:"type:varchar(255)"`
+	AllowedDevices     string `gorm:"type:text"`
+	CreatedBy          uint
+	ExpiresAt          *time.Time
+	RevokedAt          *time.Time
+	CreatedAt          time.Time `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime"`
+}
+
+func (baselineAPIKey) TableName() string {
+	return "api_keys"
+}
+
+type baselineRetentionPolicy struct {
+	ID                  uint      `gorm:"primaryKey;autoIncrement"`
+	SensorType          string    `gorm:"type:varchar(50);uniqueIndex;not null"`
+	RawRetentionDays    int       `gorm:"not null;default:0"`
+	RollupRetentionDays int       `gorm:"not null;default:0"`
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime"`
+}
+
+func (baselineRetentionPolicy) TableName() string {
+	return "retention_policies"
+}
+
+type baselineJob struct {
+	ID         string          `gorm:"primaryKey;type:varchar(32)"`
+	Type       string          `gorm:"type:varchar(50);not null;index"`
+	Status     string          `gorm:"type:varchar(20);not null"`
+	Progress   json.RawMessage `gorm:"type:text"`
+	Result     json.RawMessage `gorm:"type:string;size:16777215"`
+	Error      string          `gorm:"type:text"`
+	CreatedBy  uint
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	FinishedAt *time.Time
+}
+
+func (baselineJob) TableName() string {
+	return "jobs"
+}
+
+type baselineAuditEntry struct {
+	ID         uint            `gorm:"primaryKey;autoIncrement"`
+	Action     string          `gorm:"type:varchar(100);not null;index"`
+	Resource   string          `gorm:"type:varchar(50);not null;index:idx_audit_resource"`
+	ResourceID string          `gorm:"type:varchar(64);index:idx_audit_resource"`
+	ActorID    uint            `gorm:"index"`
+	ActorEmail string          `gorm:"type:varchar(255)"`
+	ActorRole  string          `gorm:"type:varchar(20)"`
+	RequestID  string          `gorm:"type:varchar(64)"`
+	Details    json.RawMessage `gorm:"type:text"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime;index"`
+}
+
+func (baselineAuditEntry) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrate"
+)
+
+// alerts creates the tables of the alert rules, alerts and their state changes
+var alerts = migrate.Migration{
+	Version: 20261018120000,
+	Name:    "alerts",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(alertsModels...)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(alertsModels...)
+	},
+}
+
+var alertsModels = []interface{}{&alertsRule{}, &alertsAlert{}, &alertsEvent{}}
+
+type alertsRule struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	SensorType  string `gorm:"type:varchar(50);not null;default:'';index"`
+	ID1         string `gorm:"type:varchar(50);not null;default:''"`
+	ID2         *int32
+	Condition   string `gorm:"type:varchar(20);not null"`
+	Threshold   *float64
+	Low         *float64
+	High        *float64
+	Consecutive int     `gorm:"not null;default:1"`
+	Hysteresis  float64 `gorm:"not null;default:0"`
+	Severity    string  `gorm:"type:varchar(20);not null"`
+	Enabled     bool    `gorm:"not null;index"`
+	CreatedBy   uint
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (alertsRule) TableName() string {
+	return "alert_rules"
+}
+
+type alertsAlert struct {
+	ID                  uint   `gorm:"primaryKey;autoIncrement"`
+	RuleID              uint   `gorm:"not null;index"`
+	RuleName            string `gorm:"type:varchar(100);not null"`
+	Condition           string `gorm:"type:varchar(255);not null"`
+	Severity            string `gorm:"type:varchar(20);not null;index"`
+	SensorType          string `gorm:"type:varchar(50);not null;index"`
+	ID1                 string `gorm:"type:varchar(50);not null"`
+	ID2                 int32  `gorm:"not null"`
+	State               string `gorm:"type:varchar(20);not null;index"`
+	Value               float64
+	FiredAt             time.Time  `gorm:"type:timestamp;not null;index"`
+	AcknowledgedAt      *time.Time `gorm:"type:timestamp"`
+	AcknowledgedBy      uint
+	AcknowledgedByEmail string     `gorm:"type:varchar(255)"`
+	ResolvedAt          *time.Time `gorm:"type:timestamp"`
+	OpenKey             *string    `gorm:"type:varchar(191);uniqueIndex"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (alertsAlert) TableName() string {
+	return "alerts"
+}
+
+type alertsEvent struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	AlertID    uint   `gorm:"not null;index"`
+	Event      string `gorm:"type:varchar(20);not null"`
+	Value      *float64
+	Note       string `gorm:"type:varchar(255)"`
+	ActorID    uint
+	ActorEmail string `gorm:"type:varchar(255)"`
+	CreatedAt  time.Time
+}
+
+func (alertsEvent) TableName() string {
+	return "alert_events"
+}
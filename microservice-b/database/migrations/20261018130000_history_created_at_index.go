@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrate"
+)
+
+// historyCreatedAtIndex indexes sensor_data_history.created_at, which the rollup job scans for
+// readings moved to another minute since its watermark
+var historyCreatedAtIndex = migrate.Migration{
+	Version: 20261018130000,
+	Name:    "history_created_at_index",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateIndex(&historyCreatedAtIndexHistory{}, "CreatedAt")
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropIndex(&historyCreatedAtIndexHistory{}, "CreatedAt")
+	},
+}
+
+type historyCreatedAtIndexHistory struct {
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (historyCreatedAtIndexHistory) TableName() string {
+	return "sensor_data_history"
+}
@@ -0,0 +1,19 @@
+// Package migrations holds the schema migrations of microservice-b.
+//
+// Migrations are frozen once released: change the schema by adding a migration with a newer
+// version, never by editing an existing one. Go migrations declare the models they need in their
+// own file rather than using the module entities, whose definitions move on.
+package migrations
+
+import (
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrate"
+)
+
+// All returns the migrations of microservice-b
+func All() []migrate.Migration {
+	return []migrate.Migration{
+		baseline,
+		alerts,
+		historyCreatedAtIndex,
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// utcConnPool converts time arguments to UTC before passing them on. PostgreSQL timestamp columns
+// and SQLite text timestamps keep the wall clock of the time they are given, so times written with
+// different offsets would neither compare nor bucket correctly.
+type utcConnPool struct {
+	gorm.ConnPool
+}
+
+func (p *utcConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.ConnPool.ExecContext(ctx, query, toUTC(args)...)
+}
+
+func (p *utcConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.ConnPool.QueryContext(ctx, query, toUTC(args)...)
+}
+
+func (p *utcConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.ConnPool.QueryRowContext(ctx, query, toUTC(args)...)
+}
+
+// BeginTx starts a transaction converting time arguments the same way
+func (p *utcConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	beginner, ok := p.ConnPool.(gorm.TxBeginner)
+	if !ok {
+		return nil, gorm.ErrInvalidTransaction
+	}
+	tx, err := beginner.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &utcTx{Tx: tx}, nil
+}
+
+// GetDBConn returns the wrapped *sql.DB, for gorm.DB.DB
+func (p *utcConnPool) GetDBConn() (*sql.DB, error) {
+	if db, ok := p.ConnPool.(*sql.DB); ok {
+		return db, nil
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// utcTx is a transaction of utcConnPool
+type utcTx struct {
+	*sql.Tx
+}
+
+func (tx *utcTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, query, toUTC(args)...)
+}
+
+func (tx *utcTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, query, toUTC(args)...)
+}
+
+func (tx *utcTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, query, toUTC(args)...)
+}
+
+// toUTC returns args with times, including valuers such as gorm.DeletedAt, converted to UTC
+func toUTC(args []interface{}) []interface{} {
+	converted, copied := args, false
+	for i, arg := range args {
+		var t time.Time
+		switch value := arg.(type) {
+		case time.Time:
+			t = value
+		case *time.Time:
+			if value == nil {
+				continue
+			}
+			t = *value
+		case driver.Valuer:
+			v, err := value.Value()
+			if err != nil {
+				continue
+			}
+			valueTime, ok := v.(time.Time)
+			if !ok {
+				continue
+			}
+			t = valueTime
+		default:
+			continue
+		}
+
+		if !copied {
+			converted, copied = append([]interface{}(nil), args...), true
+		}
+		converted[i] = t.UTC()
+	}
+	return converted
+}
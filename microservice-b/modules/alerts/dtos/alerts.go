@@ -0,0 +1,134 @@
+package dtos
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/entities"
+)
+
+const (
+	maxNameLength       = 100
+	maxScopeFieldLength = 50
+	maxConsecutive      = 1000
+	maxNoteLength       = 255
+)
+
+// RuleRequest represents alert rule create or replace request. Empty scope fields match any series;
+// threshold is used by above, below and rate_of_change (per minute), low and high by outside_band.
+type RuleRequest struct {
+	Name        string   `json:"name" example:"Greenhouse too hot"`
+	SensorType  string   `json:"sensor_type,omitempty" example:"temperature"`
+	ID1         string   `json:"id1,omitempty" example:"A1B2"`
+	ID2         *int32   `json:"id2,omitempty" example:"1"`
+	Condition   string   `json:"condition" example:"above" enums:"above,below,rate_of_change,outside_band"`
+	Threshold   *float64 `json:"threshold,omitempty" example:"35"`
+	Low         *float64 `json:"low,omitempty"`
+	High        *float64 `json:"high,omitempty"`
+	Consecutive int      `json:"consecutive,omitempty" example:"3"`
+	Hysteresis  float64  `json:"hysteresis,omitempty" example:"1.5"`
+	Severity    string   `json:"severity,omitempty" example:"critical" enums:"info,warning,critical"`
+	Enabled     *bool    `json:"enabled,omitempty" example:"true"`
+}
+
+// Validate checks the rule and applies the defaults: 1 consecutive reading, warning severity, enabled
+func (r *RuleRequest) Validate() error {
+	if r.Name == "" || len(r.Name) > maxNameLength {
+		return fmt.Errorf("name must be 1 to %d characters", maxNameLength)
+	}
+	if len(r.SensorType) > maxScopeFieldLength || len(r.ID1) > maxScopeFieldLength {
+		return fmt.Errorf("sensor_type and id1 must be at most %d characters", maxScopeFieldLength)
+	}
+
+	switch r.Condition {
+	case entities.ConditionAbove, entities.ConditionBelow, entities.ConditionRateOfChange:
+		if r.Threshold == nil {
+			return fmt.Errorf("condition %s needs a threshold", r.Condition)
+		}
+		if r.Low != nil || r.High != nil {
+			return fmt.Errorf("low and high only apply to %s", entities.ConditionOutsideBand)
+		}
+	case entities.ConditionOutsideBand:
+		if r.Low == nil || r.High == nil || *r.Low >= *r.High {
+			return errors.New("condition outside_band needs low below high")
+		}
+		if r.Threshold != nil {
+			return errors.New("threshold does not apply to outside_band")
+		}
+	default:
+		return fmt.Errorf("condition must be one of %s, %s, %s, %s", entities.ConditionAbove, entities.ConditionBelow,
+			entities.ConditionRateOfChange, entities.ConditionOutsideBand)
+	}
+
+	if r.Hysteresis < 0 {
+		return errors.New("hysteresis must not be negative")
+	}
+	switch r.Condition {
+	case entities.ConditionRateOfChange:
+		if *r.Threshold <= 0 || r.Hysteresis >= *r.Threshold {
+			return errors.New("rate_of_change needs a positive threshold greater than the hysteresis")
+		}
+	case entities.ConditionOutsideBand:
+		if 2*r.Hysteresis >= *r.High-*r.Low {
+			return errors.New("hysteresis must be less than half the band, values could not clear it otherwise")
+		}
+	}
+
+	if r.Consecutive == 0 {
+		r.Consecutive = 1
+	}
+	if r.Consecutive < 1 || r.Consecutive > maxConsecutive {
+		return fmt.Errorf("consecutive must be between 1 and %d", maxConsecutive)
+	}
+
+	switch r.Severity {
+	case "":
+		r.Severity = entities.SeverityWarning
+	case entities.SeverityInfo, entities.SeverityWarning, entities.SeverityCritical:
+	default:
+		return fmt.Errorf("severity must be one of %s, %s, %s", entities.SeverityInfo, entities.SeverityWarning, entities.SeverityCritical)
+	}
+
+	if r.Enabled == nil {
+		enabled := true
+		r.Enabled = &enabled
+	}
+	return nil
+}
+
+// AcknowledgeRequest represents alert acknowledgement request
+type AcknowledgeRequest struct {
+	Note string `json:"note,omitempty" example:"Vent opened, watching it"`
+}
+
+// Validate checks the note length
+func (r *AcknowledgeRequest) Validate() error {
+	if len(r.Note) > maxNoteLength {
+		return fmt.Errorf("note must be at most %d characters", maxNoteLength)
+	}
+	return nil
+}
+
+// AlertStateOpen selects firing and acknowledged alerts in AlertFilter
+const AlertStateOpen = "open"
+
+// AlertFilter represents filter criteria for alert queries
+type AlertFilter struct {
+	State      *string // firing, acknowledged, resolved or open
+	Severity   *string
+	RuleID     *uint
+	SensorType *string
+	ID1        *string
+	FromTime   *time.Time // Fired at or after
+	ToTime     *time.Time // Fired at or before
+}
+
+// AlertPage represents a page of alerts, most recently fired first
+type AlertPage struct {
+	Data       interface{} `json:"data"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	Total      int64       `json:"total"`
+	TotalPages int         `json:"total_pages"`
+}
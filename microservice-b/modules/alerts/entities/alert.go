@@ -0,0 +1,64 @@
+package entities
+
+import "time"
+
+// Alert states
+const (
+	AlertStateFiring       = "firing"
+	AlertStateAcknowledged = "acknowledged"
+	AlertStateResolved     = "resolved"
+)
+
+// Alert events
+const (
+	AlertEventFired        = "fired"
+	AlertEventAcknowledged = "acknowledged"
+	AlertEventResolved     = "resolved"
+)
+
+// Alert is the firing of a rule for one series, from the reading that fired it until it resolves.
+// The rule name, condition and severity are copied so the history stays meaningful when the rule
+// changes or is deleted.
+type Alert struct {
+	ID                  uint         `json:"id" gorm:"primaryKey;autoIncrement"`
+	RuleID              uint         `json:"rule_id" gorm:"not null;index"`
+	RuleName            string       `json:"rule_name" gorm:"type:varchar(100);not null"`
+	Condition           string       `json:"condition" gorm:"type:varchar(255);not null"`
+	Severity            string       `json:"severity" gorm:"type:varchar(20);not null;index"`
+	SensorType          string       `json:"sensor_type" gorm:"type:varchar(50);not null;index"`
+	ID1                 string       `json:"id1" gorm:"type:varchar(50);not null"`
+	ID2                 int32        `json:"id2" gorm:"not null"`
+	State               string       `json:"state" gorm:"type:varchar(20);not null;index"`
+	Value               float64      `json:"value"` // Value that fired the alert, the rate per minute for rate_of_change
+	FiredAt             time.Time    `json:"fired_at" gorm:"type:timestamp;not null;index"`
+	AcknowledgedAt      *time.Time   `json:"acknowledged_at,omitempty" gorm:"type:timestamp"`
+	AcknowledgedBy      uint         `json:"acknowledged_by,omitempty"`
+	AcknowledgedByEmail string       `json:"acknowledged_by_email,omitempty" gorm:"type:varchar(255)"`
+	ResolvedAt          *time.Time   `json:"resolved_at,omitempty" gorm:"type:timestamp"`
+	OpenKey             *string      `json:"-" gorm:"type:varchar(191);uniqueIndex"` // Set while open, so a series has one open alert per rule
+	Events              []AlertEvent `json:"events,omitempty" gorm:"foreignKey:AlertID"`
+	CreatedAt           time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName sets the table name for GORM
+func (Alert) TableName() string {
+	return "alerts"
+}
+
+// AlertEvent records a state change of an alert. ActorID is 0 for changes made by the evaluator.
+type AlertEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	AlertID    uint      `json:"alert_id" gorm:"not null;index"`
+	Event      string    `json:"event" gorm:"type:varchar(20);not null"`
+	Value      *float64  `json:"value,omitempty"`
+	Note       string    `json:"note,omitempty" gorm:"type:varchar(255)"`
+	ActorID    uint      `json:"actor_id,omitempty"`
+	ActorEmail string    `json:"actor_email,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName sets the table name for GORM
+func (AlertEvent) TableName() string {
+	return "alert_events"
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// Alert rule conditions
+const (
+	ConditionAbove        = "above"          // Value above Threshold
+	ConditionBelow        = "below"          // Value below Threshold
+	ConditionRateOfChange = "rate_of_change" // Change between consecutive readings above Threshold per minute
+	ConditionOutsideBand  = "outside_band"   // Value below Low or above High
+)
+
+// Alert severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// AlertRule fires an alert for a series (sensor type, id1, id2) once its readings meet the condition
+// Consecutive times in a row. The alert resolves when a reading is back past the threshold by Hysteresis,
+// so values hovering around the threshold do not make it flap. Empty scope fields match any series.
+type AlertRule struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null"`
+	SensorType  string    `json:"sensor_type,omitempty" gorm:"type:varchar(50);not null;default:'';index"`
+	ID1         string    `json:"id1,omitempty" gorm:"type:varchar(50);not null;default:''"`
+	ID2         *int32    `json:"id2,omitempty"`
+	Condition   string    `json:"condition" gorm:"type:varchar(20);not null"`
+	Threshold   *float64  `json:"threshold,omitempty"`
+	Low         *float64  `json:"low,omitempty"`
+	High        *float64  `json:"high,omitempty"`
+	Consecutive int       `json:"consecutive" gorm:"not null;default:1"`
+	Hysteresis  float64   `json:"hysteresis" gorm:"not null;default:0"`
+	Severity    string    `json:"severity" gorm:"type:varchar(20);not null"`
+	Enabled     bool      `json:"enabled" gorm:"not null;index"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName sets the table name for GORM
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// Matches reports whether the rule covers the series
+func (r *AlertRule) Matches(sensorType, id1 string, id2 int32) bool {
+	return (r.SensorType == "" || r.SensorType == sensorType) &&
+		(r.ID1 == "" || r.ID1 == id1) &&
+		(r.ID2 == nil || *r.ID2 == id2)
+}
+
+// Breached reports whether x, the value or for rate_of_change the rate per minute, meets the condition
+func (r *AlertRule) Breached(x float64) bool {
+	switch r.Condition {
+	case ConditionAbove, ConditionRateOfChange:
+		return x > *r.Threshold
+	case ConditionBelow:
+		return x < *r.Threshold
+	case ConditionOutsideBand:
+		return x < *r.Low || x > *r.High
+	}
+	return false
+}
+
+// Cleared reports whether x is back past the threshold by the hysteresis, resolving a firing alert
+func (r *AlertRule) Cleared(x float64) bool {
+	switch r.Condition {
+	case ConditionAbove, ConditionRateOfChange:
+		return x <= *r.Threshold-r.Hysteresis
+	case ConditionBelow:
+		return x >= *r.Threshold+r.Hysteresis
+	case ConditionOutsideBand:
+		return x >= *r.Low+r.Hysteresis && x <= *r.High-r.Hysteresis
+	}
+	return false
+}
+
+// Describe returns the condition in words, e.g. "value > 30 for 3 consecutive readings"
+func (r *AlertRule) Describe() string {
+	var condition string
+	switch r.Condition {
+	case ConditionAbove:
+		condition = fmt.Sprintf("value > %g", *r.Threshold)
+	case ConditionBelow:
+		condition = fmt.Sprintf("value < %g", *r.Threshold)
+	case ConditionRateOfChange:
+		condition = fmt.Sprintf("change > %g per minute", *r.Threshold)
+	case ConditionOutsideBand:
+		condition = fmt.Sprintf("value outside [%g, %g]", *r.Low, *r.High)
+	}
+	if r.Consecutive > 1 {
+		condition += fmt.Sprintf(" for %d consecutive readings", r.Consecutive)
+	}
+	if r.Hysteresis > 0 {
+		condition += fmt.Sprintf(", hysteresis %g", r.Hysteresis)
+	}
+	return condition
+}
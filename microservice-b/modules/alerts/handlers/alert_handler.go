@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/services"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+type AlertHandler struct {
+	alertService interfaces.AlertServiceInterface
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alertService interfaces.AlertServiceInterface) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// ListRules godoc
+// @Summary List alert rules
+// @Description List all alert rules, enabled or not
+// @Tags alerts
+// @Produce json
+// @Success 200 {object} shared.APIResponse{data=[]entities.AlertRule}
+// @Security Bearer
+// @Router /alerts/rules [get]
+func (h *AlertHandler) ListRules(c echo.Context) error {
+	rules, err := h.alertService.ListRules(c.Request().Context())
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alert rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// GetRule godoc
+// @Summary Get alert rule
+// @Description Get an alert rule by ID
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} shared.APIResponse{data=entities.AlertRule}
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 404 {object} shared.APIResponse "Rule not found"
+// @Security Bearer
+// @Router /alerts/rules/{id} [get]
+func (h *AlertHandler) GetRule(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return invalidRequest(c, err)
+	}
+
+	rule, err := h.alertService.GetRule(c.Request().Context(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alert rule retrieved successfully",
+		Data:    rule,
+	})
+}
+
+// CreateRule godoc
+// @Summary Create alert rule
+// @Description Create a rule evaluated against every reading stored from now on. It fires an alert for a series (sensor type, id1, id2) once consecutive readings meet the condition: above or below threshold, changing by more than threshold per minute (rate_of_change), or outside [low, high] (outside_band). The alert resolves when a reading is back past the threshold by the hysteresis. Empty sensor_type, id1 and id2 match any series
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param request body dtos.RuleRequest true "Alert rule"
+// @Success 201 {object} shared.APIResponse{data=entities.AlertRule}
+// @Failure 400 {object} shared.APIResponse "Invalid rule"
+// @Security Bearer
+// @Router /alerts/rules [post]
+func (h *AlertHandler) CreateRule(c echo.Context) error {
+	var request dtos.RuleRequest
+	if err := c.Bind(&request); err != nil {
+		return invalidRequest(c, err)
+	}
+
+	rule, err := h.alertService.CreateRule(c.Request().Context(), &request)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alert rule created successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateRule godoc
+// @Summary Replace alert rule
+// @Description Replace an alert rule. The evaluation of its series starts over; open alerts stay open until the new condition clears, or are resolved if the rule is disabled
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param request body dtos.RuleRequest true "Alert rule"
+// @Success 200 {object} shared.APIResponse{data=entities.AlertRule}
+// @Failure 400 {object} shared.APIResponse "Invalid rule"
+// @Failure 404 {object} shared.APIResponse "Rule not found"
+// @Security Bearer
+// @Router /alerts/rules/{id} [put]
+func (h *AlertHandler) UpdateRule(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return invalidRequest(c, err)
+	}
+	var request dtos.RuleRequest
+	if err := c.Bind(&request); err != nil {
+		return invalidRequest(c, err)
+	}
+
+	rule, err := h.alertService.UpdateRule(c.Request().Context(), id, &request)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alert rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteRule godoc
+// @Summary Delete alert rule
+// @Description Delete an alert rule and resolve its open alerts; its alerts stay in the history
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 404 {object} shared.APIResponse "Rule not found"
+// @Security Bearer
+// @Router /alerts/rules/{id} [delete]
+func (h *AlertHandler) DeleteRule(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return invalidRequest(c, err)
+	}
+
+	if err := h.alertService.DeleteRule(c.Request().Context(), id); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alert rule deleted successfully",
+	})
+}
+
+// List godoc
+// @Summary List alerts
+// @Description List alerts, most recently fired first
+// @Tags alerts
+// @Produce json
+// @Param state query string false "State filter (firing, acknowledged, resolved, or open for firing and acknowledged)"
+// @Param severity query string false "Severity filter (info, warning, critical)"
+// @Param rule_id query int false "Rule ID filter"
+// @Param sensor_type query string false "Sensor type filter"
+// @Param id1 query string false "ID1 filter"
+// @Param from_time query string false "Fired at or after (RFC3339)"
+// @Param to_time query string false "Fired at or before (RFC3339)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} shared.APIResponse{data=dtos.AlertPage}
+// @Failure 400 {object} shared.APIResponse "Invalid parameters"
+// @Security Bearer
+// @Router /alerts [get]
+func (h *AlertHandler) List(c echo.Context) error {
+	filter, err := parseAlertFilter(c)
+	if err != nil {
+		return invalidRequest(c, err)
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	result, err := h.alertService.ListAlerts(c.Request().Context(), filter, page, pageSize)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alerts retrieved successfully",
+		Data:    result,
+	})
+}
+
+// Get godoc
+// @Summary Get alert
+// @Description Get an alert with its history of state changes
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Success 200 {object} shared.APIResponse{data=entities.Alert}
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 404 {object} shared.APIResponse "Alert not found"
+// @Security Bearer
+// @Router /alerts/{id} [get]
+func (h *AlertHandler) Get(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return invalidRequest(c, err)
+	}
+
+	alert, err := h.alertService.GetAlert(c.Request().Context(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alert retrieved successfully",
+		Data:    alert,
+	})
+}
+
+// Acknowledge godoc
+// @Summary Acknowledge alert
+// @Description Acknowledge a firing alert. It stays open until its condition clears
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param request body dtos.AcknowledgeRequest false "Optional note"
+// @Success 200 {object} shared.APIResponse{data=entities.Alert}
+// @Failure 400 {object} shared.APIResponse "Invalid request"
+// @Failure 404 {object} shared.APIResponse "Alert not found"
+// @Failure 409 {object} shared.APIResponse "Alert not firing"
+// @Security Bearer
+// @Router /alerts/{id}/acknowledge [post]
+func (h *AlertHandler) Acknowledge(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return invalidRequest(c, err)
+	}
+	var request dtos.AcknowledgeRequest
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&request); err != nil {
+			return invalidRequest(c, err)
+		}
+	}
+	if err := request.Validate(); err != nil {
+		return invalidRequest(c, err)
+	}
+
+	alert, err := h.alertService.Acknowledge(c.Request().Context(), id, request.Note)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Alert acknowledged successfully",
+		Data:    alert,
+	})
+}
+
+// parseID reads the id path parameter
+func parseID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, errors.New("Invalid ID format")
+	}
+	return uint(id), nil
+}
+
+// parseAlertFilter reads AlertFilter query parameters, rejecting malformed values
+func parseAlertFilter(c echo.Context) (*dtos.AlertFilter, error) {
+	filter := &dtos.AlertFilter{}
+
+	if state := c.QueryParam("state"); state != "" {
+		switch state {
+		case entities.AlertStateFiring, entities.AlertStateAcknowledged, entities.AlertStateResolved, dtos.AlertStateOpen:
+		default:
+			return nil, fmt.Errorf("invalid state %q", state)
+		}
+		filter.State = &state
+	}
+	if severity := c.QueryParam("severity"); severity != "" {
+		switch severity {
+		case entities.SeverityInfo, entities.SeverityWarning, entities.SeverityCritical:
+		default:
+			return nil, fmt.Errorf("invalid severity %q", severity)
+		}
+		filter.Severity = &severity
+	}
+	if sensorType := c.QueryParam("sensor_type"); sensorType != "" {
+		filter.SensorType = &sensorType
+	}
+	if id1 := c.QueryParam("id1"); id1 != "" {
+		filter.ID1 = &id1
+	}
+
+	if ruleIDStr := c.QueryParam("rule_id"); ruleIDStr != "" {
+		ruleID, err := strconv.ParseUint(ruleIDStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule_id %q", ruleIDStr)
+		}
+		id := uint(ruleID)
+		filter.RuleID = &id
+	}
+
+	if fromTimeStr := c.QueryParam("from_time"); fromTimeStr != "" {
+		fromTime, err := time.Parse(time.RFC3339, fromTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from_time %q, use RFC3339", fromTimeStr)
+		}
+		filter.FromTime = &fromTime
+	}
+
+	if toTimeStr := c.QueryParam("to_time"); toTimeStr != "" {
+		toTime, err := time.Parse(time.RFC3339, toTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to_time %q, use RFC3339", toTimeStr)
+		}
+		filter.ToTime = &toTime
+	}
+
+	return filter, nil
+}
+
+// errorResponse maps service errors to responses
+func errorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidRule):
+		return invalidRequest(c, err)
+	case errors.Is(err, services.ErrRuleNotFound), errors.Is(err, services.ErrAlertNotFound):
+		return c.JSON(http.StatusNotFound, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrNotFound,
+			Error:   err.Error(),
+		})
+	case errors.Is(err, services.ErrAlertNotFiring):
+		return c.JSON(http.StatusConflict, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: "Alert is not firing",
+			Error:   err.Error(),
+		})
+	}
+	return internalError(c, err)
+}
+
+func invalidRequest(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInvalidRequest,
+		Error:   err.Error(),
+	})
+}
+
+func internalError(c echo.Context, err error) error {
+	return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInternalServer,
+		Error:   err.Error(),
+	})
+}
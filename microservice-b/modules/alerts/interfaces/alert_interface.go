@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/entities"
+)
+
+// AlertServiceInterface defines the interface for alert rules and alerts
+type AlertServiceInterface interface {
+	ListRules(ctx context.Context) ([]*entities.AlertRule, error)
+	GetRule(ctx context.Context, id uint) (*entities.AlertRule, error)
+	CreateRule(ctx context.Context, request *dtos.RuleRequest) (*entities.AlertRule, error)
+	UpdateRule(ctx context.Context, id uint, request *dtos.RuleRequest) (*entities.AlertRule, error)
+	DeleteRule(ctx context.Context, id uint) error
+	ListAlerts(ctx context.Context, filter *dtos.AlertFilter, page, pageSize int) (*dtos.AlertPage, error)
+	GetAlert(ctx context.Context, id uint) (*entities.Alert, error)
+	Acknowledge(ctx context.Context, id uint, note string) (*entities.Alert, error)
+}
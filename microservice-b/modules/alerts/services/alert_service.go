@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/actor"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+var (
+	// ErrInvalidRule is returned for rules with an invalid scope, condition or severity
+	ErrInvalidRule = errors.New("invalid alert rule")
+	// ErrRuleNotFound is returned when a rule does not exist
+	ErrRuleNotFound = errors.New("alert rule not found")
+	// ErrAlertNotFound is returned when an alert does not exist
+	ErrAlertNotFound = errors.New("alert not found")
+	// ErrAlertNotFiring is returned when acknowledging an alert that is acknowledged or resolved
+	ErrAlertNotFiring = errors.New("alert is not firing")
+)
+
+type alertService struct {
+	db        *gorm.DB
+	evaluator *Evaluator
+}
+
+// NewAlertService creates a new alert service. Rule changes are applied to evaluator right away,
+// which may be nil when alerts are not evaluated on this replica.
+func NewAlertService(db *gorm.DB, evaluator *Evaluator) interfaces.AlertServiceInterface {
+	return &alertService{
+		db:        db,
+		evaluator: evaluator,
+	}
+}
+
+// ListRules returns all alert rules
+func (s *alertService) ListRules(ctx context.Context) ([]*entities.AlertRule, error) {
+	var rules []*entities.AlertRule
+	if err := s.db.WithContext(ctx).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetRule returns a rule by ID
+func (s *alertService) GetRule(ctx context.Context, id uint) (*entities.AlertRule, error) {
+	var rule entities.AlertRule
+	if err := s.db.WithContext(ctx).First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// CreateRule creates a rule, attributed to the actor of ctx
+func (s *alertService) CreateRule(ctx context.Context, request *dtos.RuleRequest) (*entities.AlertRule, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	rule := &entities.AlertRule{}
+	applyRuleRequest(rule, request)
+	if a, ok := actor.FromContext(ctx); ok {
+		rule.CreatedBy = a.UserID
+	}
+	if err := s.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, err
+	}
+
+	s.reload(ctx)
+	return rule, nil
+}
+
+// UpdateRule replaces a rule. Disabling it resolves its open alerts; otherwise they stay open until
+// the new condition clears.
+func (s *alertService) UpdateRule(ctx context.Context, id uint, request *dtos.RuleRequest) (*entities.AlertRule, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	var rule entities.AlertRule
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&rule, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRuleNotFound
+			}
+			return err
+		}
+
+		applyRuleRequest(&rule, request)
+		// Select all fields so that a cleared ID2, threshold or band is written too
+		if err := tx.Select("*").Omit("id", "created_by", "created_at").Save(&rule).Error; err != nil {
+			return err
+		}
+		if !rule.Enabled {
+			return resolveRuleAlerts(ctx, tx, rule.ID, "rule disabled")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reload(ctx)
+	return &rule, nil
+}
+
+// DeleteRule deletes a rule and resolves its open alerts, which stay in the history
+func (s *alertService) DeleteRule(ctx context.Context, id uint) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entities.AlertRule{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRuleNotFound
+		}
+		return resolveRuleAlerts(ctx, tx, id, "rule deleted")
+	})
+	if err != nil {
+		return err
+	}
+
+	s.reload(ctx)
+	return nil
+}
+
+// ListAlerts returns a page of alerts matching the filter, most recently fired first
+func (s *alertService) ListAlerts(ctx context.Context, filter *dtos.AlertFilter, page, pageSize int) (*dtos.AlertPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > constants.MaxPageSize {
+		pageSize = constants.DefaultPageSize
+	}
+
+	query := s.db.WithContext(ctx).Model(&entities.Alert{})
+	if filter != nil {
+		if filter.State != nil {
+			if *filter.State == dtos.AlertStateOpen {
+				query = query.Where("state <> ?", entities.AlertStateResolved)
+			} else {
+				query = query.Where("state = ?", *filter.State)
+			}
+		}
+		if filter.Severity != nil {
+			query = query.Where("severity = ?", *filter.Severity)
+		}
+		if filter.RuleID != nil {
+			query = query.Where("rule_id = ?", *filter.RuleID)
+		}
+		if filter.SensorType != nil {
+			query = query.Where("sensor_type = ?", *filter.SensorType)
+		}
+		if filter.ID1 != nil {
+			query = query.Where("id1 = ?", *filter.ID1)
+		}
+		if filter.FromTime != nil {
+			query = query.Where("fired_at >= ?", *filter.FromTime)
+		}
+		if filter.ToTime != nil {
+			query = query.Where("fired_at <= ?", *filter.ToTime)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var alerts []*entities.Alert
+	err := query.Order("fired_at DESC, id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.AlertPage{
+		Data:       alerts,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+	}, nil
+}
+
+// GetAlert returns an alert with its events, oldest first
+func (s *alertService) GetAlert(ctx context.Context, id uint) (*entities.Alert, error) {
+	var alert entities.Alert
+	err := s.db.WithContext(ctx).
+		Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&alert, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlertNotFound
+		}
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Acknowledge marks a firing alert as acknowledged by the actor of ctx. It stays open until its condition clears.
+func (s *alertService) Acknowledge(ctx context.Context, id uint, note string) (*entities.Alert, error) {
+	a, _ := actor.FromContext(ctx)
+	now := time.Now().UTC()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Alert{}).
+			Where("id = ? AND state = ?", id, entities.AlertStateFiring).
+			Updates(map[string]interface{}{
+				"state":                 entities.AlertStateAcknowledged,
+				"acknowledged_at":       now,
+				"acknowledged_by":       a.UserID,
+				"acknowledged_by_email": a.Email,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&entities.Alert{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrAlertNotFound
+			}
+			return ErrAlertNotFiring
+		}
+
+		return tx.Create(&entities.AlertEvent{
+			AlertID:    id,
+			Event:      entities.AlertEventAcknowledged,
+			Note:       note,
+			ActorID:    a.UserID,
+			ActorEmail: a.Email,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.Info(fmt.Sprintf("Alert %d acknowledged by user %d", id, a.UserID))
+	return s.GetAlert(ctx, id)
+}
+
+// reload applies rule changes to the evaluator of this replica, others pick them up on their next refresh
+func (s *alertService) reload(ctx context.Context) {
+	if s.evaluator == nil {
+		return
+	}
+	if err := s.evaluator.Reload(ctx); err != nil {
+		utils.Error(fmt.Sprintf("Failed to reload alert rules: %v", err))
+	}
+}
+
+// applyRuleRequest copies a validated request to rule
+func applyRuleRequest(rule *entities.AlertRule, request *dtos.RuleRequest) {
+	rule.Name = request.Name
+	rule.SensorType = request.SensorType
+	rule.ID1 = request.ID1
+	rule.ID2 = request.ID2
+	rule.Condition = request.Condition
+	rule.Threshold = request.Threshold
+	rule.Low = request.Low
+	rule.High = request.High
+	rule.Consecutive = request.Consecutive
+	rule.Hysteresis = request.Hysteresis
+	rule.Severity = request.Severity
+	rule.Enabled = *request.Enabled
+}
+
+// resolveRuleAlerts resolves the open alerts of a rule, recording note and the actor of ctx
+func resolveRuleAlerts(ctx context.Context, tx *gorm.DB, ruleID uint, note string) error {
+	var ids []uint
+	err := tx.Model(&entities.Alert{}).
+		Where("rule_id = ? AND state <> ?", ruleID, entities.AlertStateResolved).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	a, _ := actor.FromContext(ctx)
+	now := time.Now().UTC()
+	for _, id := range ids {
+		event := &entities.AlertEvent{Event: entities.AlertEventResolved, Note: note, ActorID: a.UserID, ActorEmail: a.Email}
+		if _, err := resolveAlert(ctx, tx, id, now, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
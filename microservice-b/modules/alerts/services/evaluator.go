@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/alerts/entities"
+	sensorEntities "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+const (
+	// staleSeriesAfter is how long the state of a series without readings or open alert is kept
+	staleSeriesAfter = 24 * time.Hour
+	// writeTimeout bounds each database write of the evaluator
+	writeTimeout = 10 * time.Second
+)
+
+var alertTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "alert_transitions_total",
+	Help: "Alerts fired and resolved by the rule evaluator, by severity.",
+}, []string{"transition", "severity"})
+
+// EvaluatorConfig holds alert evaluation settings
+type EvaluatorConfig struct {
+	RefreshInterval time.Duration // How often rules and open alerts changed by other replicas are picked up
+	MaxReadingAge   time.Duration // Older readings, e.g. imported history, are not evaluated; 0 evaluates all
+	QueueSize       int           // Alert changes waiting to be written before ingestion blocks
+}
+
+// seriesKey identifies the readings of one device evaluated against one rule
+type seriesKey struct {
+	RuleID     uint
+	SensorType string
+	ID1        string
+	ID2        int32
+}
+
+// String returns the open key of the alerts of the series
+func (k seriesKey) String() string {
+	return fmt.Sprintf("%d:%s:%s:%d", k.RuleID, k.SensorType, k.ID1, k.ID2)
+}
+
+func keyOf(alert *entities.Alert) seriesKey {
+	return seriesKey{RuleID: alert.RuleID, SensorType: alert.SensorType, ID1: alert.ID1, ID2: alert.ID2}
+}
+
+// seriesState is the evaluation state of a series
+type seriesState struct {
+	matches   int // Consecutive readings meeting the condition
+	firing    bool
+	lastValue float64
+	lastAt    time.Time
+	hasLast   bool
+	seen      time.Time // Wall clock time of the last reading, for eviction
+	seq       uint64    // Sequence number of the last change queued for the series
+}
+
+// observe evaluates a reading of the series and returns the resulting change: fire, resolve or none
+func (s *seriesState) observe(rule *entities.AlertRule, value float64, at time.Time) (changeKind, float64) {
+	s.seen = time.Now()
+	if s.hasLast && at.Before(s.lastAt) {
+		// Late readings would make the rate of change meaningless
+		return changeNone, 0
+	}
+
+	x := value
+	if rule.Condition == entities.ConditionRateOfChange {
+		if !s.hasLast || !at.After(s.lastAt) {
+			s.lastValue, s.lastAt, s.hasLast = value, at, true
+			return changeNone, 0
+		}
+		x = math.Abs(value-s.lastValue) / at.Sub(s.lastAt).Minutes()
+	}
+	s.lastValue, s.lastAt, s.hasLast = value, at, true
+
+	switch {
+	case rule.Breached(x):
+		s.matches++
+		if !s.firing && s.matches >= rule.Consecutive {
+			s.firing = true
+			return changeFire, x
+		}
+	case s.firing && rule.Cleared(x):
+		s.firing, s.matches = false, 0
+		return changeResolve, x
+	default:
+		s.matches = 0
+	}
+	return changeNone, 0
+}
+
+type changeKind int
+
+const (
+	changeNone changeKind = iota
+	changeFire
+	changeResolve
+	changeSync
+)
+
+// change is queued for the writer: firing or resolving the alert of a series, or syncing with the open
+// alerts in the database once the changes up to seq are written
+type change struct {
+	kind  changeKind
+	key   seriesKey
+	rule  *entities.AlertRule
+	value float64
+	at    time.Time
+	seq   uint64
+}
+
+// Evaluator evaluates the enabled alert rules against newly stored readings. It is registered with the
+// sensor service as a SensorDataPublisher, so rules are evaluated in the ingestion path of every transport.
+//
+// Series state (consecutive matches, the previous reading for rates) is kept in memory. Alerts are fired
+// and resolved in the background by a single writer, in the order the readings were evaluated. A series has
+// at most one open alert per rule across replicas; replicas pick up alerts opened, resolved or whose rule
+// changed elsewhere on the next refresh.
+type Evaluator struct {
+	db  *gorm.DB
+	cfg EvaluatorConfig
+
+	// order serializes evaluation with queueing, so changes are queued in sequence order;
+	// mu guards the state, which the writer also updates when syncing
+	order  sync.Mutex
+	mu     sync.Mutex
+	rules  []*entities.AlertRule
+	series map[seriesKey]*seriesState
+	seq    uint64
+	closed bool
+
+	changes  chan change
+	alertIDs map[seriesKey]uint // Open alerts by series, owned by the writer
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewEvaluator creates an alert rule evaluator
+func NewEvaluator(db *gorm.DB, cfg EvaluatorConfig) *Evaluator {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 30 * time.Second
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1000
+	}
+	return &Evaluator{
+		db:       db,
+		cfg:      cfg,
+		series:   make(map[seriesKey]*seriesState),
+		changes:  make(chan change, cfg.QueueSize),
+		alertIDs: make(map[seriesKey]uint),
+	}
+}
+
+// Start loads the rules and open alerts, then writes alert changes and refreshes in the background
+// until Close is called
+func (e *Evaluator) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+
+	e.wg.Add(2)
+	go e.write()
+	if err := e.Reload(ctx); err != nil {
+		utils.Error(fmt.Sprintf("Failed to load alert rules: %v", err))
+	}
+	go e.loop(ctx)
+}
+
+// Close stops the evaluator after writing the queued alert changes. Readings published afterwards are not evaluated.
+func (e *Evaluator) Close() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+
+	e.order.Lock()
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.changes)
+	}
+	e.mu.Unlock()
+	e.order.Unlock()
+
+	e.wg.Wait()
+}
+
+// Publish evaluates the rules against stored readings, in timestamp order
+func (e *Evaluator) Publish(_ context.Context, data []*sensorEntities.SensorData) {
+	readings := make([]*sensorEntities.SensorData, 0, len(data))
+	var oldest time.Time
+	if e.cfg.MaxReadingAge > 0 {
+		oldest = time.Now().Add(-e.cfg.MaxReadingAge)
+	}
+	for _, reading := range data {
+		if !reading.Timestamp.Before(oldest) {
+			readings = append(readings, reading)
+		}
+	}
+	if len(readings) == 0 {
+		return
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].Timestamp.Before(readings[j].Timestamp) })
+
+	e.order.Lock()
+	defer e.order.Unlock()
+
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return
+	}
+	var changes []change
+	for _, reading := range readings {
+		for _, rule := range e.rules {
+			if !rule.Matches(reading.SensorType, reading.ID1, reading.ID2) {
+				continue
+			}
+			key := seriesKey{RuleID: rule.ID, SensorType: reading.SensorType, ID1: reading.ID1, ID2: reading.ID2}
+			state, ok := e.series[key]
+			if !ok {
+				state = &seriesState{}
+				e.series[key] = state
+			}
+			if kind, value := state.observe(rule, reading.SensorValue, reading.Timestamp); kind != changeNone {
+				e.seq++
+				state.seq = e.seq
+				changes = append(changes, change{kind: kind, key: key, rule: rule, value: value, at: reading.Timestamp, seq: e.seq})
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	// Blocks while the writer is behind, holding back ingestion rather than losing alerts
+	for _, change := range changes {
+		e.changes <- change
+	}
+}
+
+// Reload loads the enabled rules and syncs with the open alerts. Series of removed rules are dropped and
+// those of changed rules start over, keeping their open alert until the new condition clears.
+func (e *Evaluator) Reload(ctx context.Context) error {
+	var rules []*entities.AlertRule
+	if err := e.db.WithContext(ctx).Where("enabled = ?", true).Order("id").Find(&rules).Error; err != nil {
+		return err
+	}
+
+	e.order.Lock()
+	defer e.order.Unlock()
+
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	previous := make(map[uint]*entities.AlertRule, len(e.rules))
+	for _, rule := range e.rules {
+		previous[rule.ID] = rule
+	}
+	current := make(map[uint]*entities.AlertRule, len(rules))
+	for _, rule := range rules {
+		current[rule.ID] = rule
+	}
+
+	now := time.Now()
+	for key, state := range e.series {
+		rule, ok := current[key.RuleID]
+		switch {
+		case !ok, !state.firing && now.Sub(state.seen) > staleSeriesAfter:
+			delete(e.series, key)
+		case previous[key.RuleID] != nil && !previous[key.RuleID].UpdatedAt.Equal(rule.UpdatedAt):
+			state.matches, state.hasLast = 0, false
+		}
+	}
+	e.rules = rules
+	e.seq++
+	seq := e.seq
+	e.mu.Unlock()
+
+	e.changes <- change{kind: changeSync, seq: seq}
+	return nil
+}
+
+// loop reloads every refresh interval
+func (e *Evaluator) loop(ctx context.Context) {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := e.Reload(ctx); err != nil && ctx.Err() == nil {
+			utils.Error(fmt.Sprintf("Failed to reload alert rules: %v", err))
+		}
+	}
+}
+
+// write applies the queued changes in order
+func (e *Evaluator) write() {
+	defer e.wg.Done()
+
+	for change := range e.changes {
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		var err error
+		switch change.kind {
+		case changeFire:
+			err = e.fire(ctx, change)
+		case changeResolve:
+			err = e.resolve(ctx, change)
+		case changeSync:
+			err = e.sync(ctx, change.seq)
+		}
+		cancel()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to update alert of rule %d for %s/%s/%d: %v", change.key.RuleID, change.key.SensorType, change.key.ID1, change.key.ID2, err))
+		}
+	}
+}
+
+// fire opens an alert for the series, or adopts the one another replica opened
+func (e *Evaluator) fire(ctx context.Context, change change) error {
+	openKey := change.key.String()
+	alert := &entities.Alert{
+		RuleID:     change.rule.ID,
+		RuleName:   change.rule.Name,
+		Condition:  change.rule.Describe(),
+		Severity:   change.rule.Severity,
+		SensorType: change.key.SensorType,
+		ID1:        change.key.ID1,
+		ID2:        change.key.ID2,
+		State:      entities.AlertStateFiring,
+		Value:      change.value,
+		FiredAt:    change.at,
+		OpenKey:    &openKey,
+	}
+
+	var adopted bool
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var open []*entities.Alert
+		if err := tx.Where("open_key = ?", openKey).Limit(1).Find(&open).Error; err != nil {
+			return err
+		}
+		if len(open) > 0 {
+			alert, adopted = open[0], true
+			return nil
+		}
+
+		if err := tx.Create(alert).Error; err != nil {
+			return err
+		}
+		value := change.value
+		return tx.Create(&entities.AlertEvent{AlertID: alert.ID, Event: entities.AlertEventFired, Value: &value}).Error
+	})
+	if err != nil {
+		// Another replica may have opened it meanwhile
+		var open []*entities.Alert
+		if e.db.WithContext(ctx).Where("open_key = ?", openKey).Limit(1).Find(&open).Error != nil || len(open) == 0 {
+			return err
+		}
+		alert, adopted = open[0], true
+	}
+
+	e.alertIDs[change.key] = alert.ID
+	if !adopted {
+		alertTransitions.WithLabelValues(entities.AlertEventFired, alert.Severity).Inc()
+		utils.Warn(fmt.Sprintf("Alert %d fired: %s for %s/%s/%d (%s), value %g", alert.ID, alert.RuleName, alert.SensorType, alert.ID1, alert.ID2, alert.Condition, alert.Value))
+	}
+	return nil
+}
+
+// resolve resolves the open alert of the series, if it is still open
+func (e *Evaluator) resolve(ctx context.Context, change change) error {
+	id, ok := e.alertIDs[change.key]
+	if !ok {
+		// Firing it failed, or it was resolved elsewhere
+		return nil
+	}
+	delete(e.alertIDs, change.key)
+
+	value := change.value
+	resolved, err := resolveAlert(ctx, e.db, id, change.at, &entities.AlertEvent{Event: entities.AlertEventResolved, Value: &value})
+	if err != nil || !resolved {
+		return err
+	}
+	alertTransitions.WithLabelValues(entities.AlertEventResolved, change.rule.Severity).Inc()
+	utils.Info(fmt.Sprintf("Alert %d resolved: %s for %s/%s/%d, value %g", id, change.rule.Name, change.key.SensorType, change.key.ID1, change.key.ID2, change.value))
+	return nil
+}
+
+// sync aligns the series with the open alerts in the database. Series with changes queued after seq
+// are left alone, the database does not reflect them yet.
+func (e *Evaluator) sync(ctx context.Context, seq uint64) error {
+	var open []*entities.Alert
+	err := e.db.WithContext(ctx).Select("id", "rule_id", "sensor_type", "id1", "id2").
+		Where("state <> ?", entities.AlertStateResolved).Find(&open).Error
+	if err != nil {
+		return err
+	}
+
+	e.alertIDs = make(map[seriesKey]uint, len(open))
+	for _, alert := range open {
+		e.alertIDs[keyOf(alert)] = alert.ID
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rules := make(map[uint]bool, len(e.rules))
+	for _, rule := range e.rules {
+		rules[rule.ID] = true
+	}
+	for key, state := range e.series {
+		if _, isOpen := e.alertIDs[key]; state.seq <= seq && state.firing != isOpen {
+			state.firing, state.matches = isOpen, 0
+		}
+	}
+	for key := range e.alertIDs {
+		if _, ok := e.series[key]; !ok && rules[key.RuleID] {
+			e.series[key] = &seriesState{firing: true, seen: time.Now()}
+		}
+	}
+	return nil
+}
+
+// resolveAlert resolves an open alert and records event, returning false if it was already resolved
+func resolveAlert(ctx context.Context, db *gorm.DB, id uint, at time.Time, event *entities.AlertEvent) (bool, error) {
+	var resolved bool
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Alert{}).
+			Where("id = ? AND state <> ?", id, entities.AlertStateResolved).
+			Updates(map[string]interface{}{
+				"state":       entities.AlertStateResolved,
+				"resolved_at": at,
+				"open_key":    nil,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		resolved = true
+		event.AlertID = id
+		return tx.Create(event).Error
+	})
+	return resolved, err
+}
@@ -0,0 +1,47 @@
+// Package actor carries the user performing a request through context.Context, so that services can
+// attribute the changes they make without depending on the HTTP layer.
+package actor
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Actor identifies who performs an action
+type Actor struct {
+	UserID    uint
+	Email     string
+	Role      string
+	RequestID string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying actor
+func NewContext(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// FromContext returns the actor carried by ctx, if any
+func FromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(contextKey{}).(Actor)
+	return actor, ok
+}
+
+// Middleware stores the authenticated user as the actor of the request context.
+// It must run after JWTAuth.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			a := Actor{}
+			a.UserID, _ = c.Get("user_id").(uint)
+			a.Email, _ = c.Get("user_email").(string)
+			a.Role, _ = c.Get("user_role").(string)
+			a.RequestID, _ = c.Get("request_id").(string)
+
+			c.SetRequest(c.Request().WithContext(NewContext(c.Request().Context(), a)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package dtos
+
+import "time"
+
+// AuditFilter represents filter criteria for audit trail queries
+type AuditFilter struct {
+	Action     *string
+	Resource   *string
+	ResourceID *string
+	ActorID    *uint
+	FromTime   *time.Time
+	ToTime     *time.Time
+}
+
+// AuditPage represents a page of audit entries, most recent first
+type AuditPage struct {
+	Data       interface{} `json:"data"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	Total      int64       `json:"total"`
+	TotalPages int         `json:"total_pages"`
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records an administrative action, who performed it and on what
+type AuditEntry struct {
+	ID         uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Action     string          `json:"action" gorm:"type:varchar(100);not null;index"`
+	Resource   string          `json:"resource" gorm:"type:varchar(50);not null;index:idx_audit_resource"`
+	ResourceID string          `json:"resource_id,omitempty" gorm:"type:varchar(64);index:idx_audit_resource"`
+	ActorID    uint            `json:"actor_id" gorm:"index"`
+	ActorEmail string          `json:"actor_email,omitempty" gorm:"type:varchar(255)"`
+	ActorRole  string          `json:"actor_role,omitempty" gorm:"type:varchar(20)"`
+	RequestID  string          `json:"request_id,omitempty" gorm:"type:varchar(64)"`
+	Details    json.RawMessage `json:"details,omitempty" gorm:"type:text" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName sets the table name for GORM
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+type AuditHandler struct {
+	auditService interfaces.AuditServiceInterface
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService interfaces.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List godoc
+// @Summary List audit trail
+// @Description List recorded administrative actions, most recent first (admin only)
+// @Tags audit
+// @Produce json
+// @Param action query string false "Action filter, e.g. sensor_data.restore"
+// @Param resource query string false "Resource filter, e.g. sensor_data"
+// @Param resource_id query string false "Resource ID filter"
+// @Param actor_id query int false "Actor user ID filter"
+// @Param from_time query string false "From time filter (RFC3339)"
+// @Param to_time query string false "To time filter (RFC3339)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} shared.APIResponse{data=dtos.AuditPage}
+// @Failure 400 {object} shared.APIResponse "Invalid parameters"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /audit [get]
+func (h *AuditHandler) List(c echo.Context) error {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	result, err := h.auditService.List(c.Request().Context(), filter, page, pageSize)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Audit trail retrieved successfully",
+		Data:    result,
+	})
+}
+
+// parseAuditFilter reads AuditFilter query parameters, rejecting malformed values
+func parseAuditFilter(c echo.Context) (*dtos.AuditFilter, error) {
+	filter := &dtos.AuditFilter{}
+
+	if action := c.QueryParam("action"); action != "" {
+		filter.Action = &action
+	}
+	if resource := c.QueryParam("resource"); resource != "" {
+		filter.Resource = &resource
+	}
+	if resourceID := c.QueryParam("resource_id"); resourceID != "" {
+		filter.ResourceID = &resourceID
+	}
+
+	if actorIDStr := c.QueryParam("actor_id"); actorIDStr != "" {
+		actorID, err := strconv.ParseUint(actorIDStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actor_id %q", actorIDStr)
+		}
+		id := uint(actorID)
+		filter.ActorID = &id
+	}
+
+	if fromTimeStr := c.QueryParam("from_time"); fromTimeStr != "" {
+		fromTime, err := time.Parse(time.RFC3339, fromTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from_time %q, use RFC3339", fromTimeStr)
+		}
+		filter.FromTime = &fromTime
+	}
+
+	if toTimeStr := c.QueryParam("to_time"); toTimeStr != "" {
+		toTime, err := time.Parse(time.RFC3339, toTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to_time %q, use RFC3339", toTimeStr)
+		}
+		filter.ToTime = &toTime
+	}
+
+	return filter, nil
+}
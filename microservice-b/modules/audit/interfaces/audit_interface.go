@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/dtos"
+)
+
+// AuditServiceInterface defines the interface for the audit trail
+type AuditServiceInterface interface {
+	Record(ctx context.Context, action, resource, resourceID string, details interface{}) error
+	List(ctx context.Context, filter *dtos.AuditFilter, page, pageSize int) (*dtos.AuditPage, error)
+}
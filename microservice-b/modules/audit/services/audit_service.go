@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/actor"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/interfaces"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+type auditService struct {
+	db *gorm.DB
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(db *gorm.DB) interfaces.AuditServiceInterface {
+	return &auditService{
+		db: db,
+	}
+}
+
+// Record appends an entry to the audit trail, attributed to the actor of ctx.
+// Actions without an actor, such as scheduled jobs, are recorded with actor ID 0.
+func (s *auditService) Record(ctx context.Context, action, resource, resourceID string, details interface{}) error {
+	entry := &entities.AuditEntry{
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+	}
+	if a, ok := actor.FromContext(ctx); ok {
+		entry.ActorID = a.UserID
+		entry.ActorEmail = a.Email
+		entry.ActorRole = a.Role
+		entry.RequestID = a.RequestID
+	}
+	if details != nil {
+		payload, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		entry.Details = payload
+	}
+
+	utils.Info(fmt.Sprintf("Audit: user %d %s %s %s", entry.ActorID, action, resource, resourceID))
+	return s.db.WithContext(ctx).Create(entry).Error
+}
+
+// List returns a page of audit entries matching the filter, most recent first
+func (s *auditService) List(ctx context.Context, filter *dtos.AuditFilter, page, pageSize int) (*dtos.AuditPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > constants.MaxPageSize {
+		pageSize = constants.DefaultPageSize
+	}
+
+	query := s.db.WithContext(ctx).Model(&entities.AuditEntry{})
+	if filter != nil {
+		if filter.Action != nil {
+			query = query.Where("action = ?", *filter.Action)
+		}
+		if filter.Resource != nil {
+			query = query.Where("resource = ?", *filter.Resource)
+		}
+		if filter.ResourceID != nil {
+			query = query.Where("resource_id = ?", *filter.ResourceID)
+		}
+		if filter.ActorID != nil {
+			query = query.Where("actor_id = ?", *filter.ActorID)
+		}
+		if filter.FromTime != nil {
+			query = query.Where("created_at >= ?", *filter.FromTime)
+		}
+		if filter.ToTime != nil {
+			query = query.Where("created_at <= ?", *filter.ToTime)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var entries []*entities.AuditEntry
+	err := query.Order("created_at DESC, id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.AuditPage{
+		Data:       entries,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+	}, nil
+}
@@ -0,0 +1,33 @@
+package dtos
+
+import "time"
+
+// CreateAPIKeyRequest represents API key issuance request
+type CreateAPIKeyRequest struct {
+	Name               string     `json:"name" validate:"required" example:"temperature-generator"`
+	Scopes             []string   `json:"scopes,omitempty" example:"sensor:write,sensor:read"`
+	AllowedSensorTypes []string   `json:"allowed_sensor_types,omitempty" example:"temperature"`
+	AllowedDevices     []string   `json:"allowed_devices,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse represents API key response (without the secret)
+type APIKeyResponse struct {
+	ID                 uint       `json:"id"`
+	Name               string     `json:"name"`
+	KeyPrefix          string     `json:"key_prefix"`
+	Scopes             []string   `json:"scopes"`
+	AllowedSensorTypes []string   `json:"allowed_sensor_types"`
+	AllowedDevices     []string   `json:"allowed_devices"`
+	CreatedBy          uint       `json:"created_by"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse represents API key issuance response.
+// The raw key is only returned once, at creation time.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// API key scopes
+const (
+	APIKeyScopeSensorWrite = "sensor:write"
+	APIKeyScopeSensorRead  = "sensor:read"
+)
+
+// APIKey represents a credential issued to a sensor data generator
+type APIKey struct {
+	ID                 uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name               string     `json:"name" gorm:"type:varchar(100);not null"`
+	KeyPrefix          string     `json:"key_prefix" gorm:"type:varchar(16);not null"`
+	KeyHash            string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	Scopes             string     `json:"scopes" gorm:"type:varchar(255);not null"`
+	AllowedSensorTypes string     `json:"allowed_sensor_types" gorm:"type:varchar(255)"`
+	AllowedDevices     string     `json:"allowed_devices" gorm:"type:text"`
+	CreatedBy          uint       `json:"created_by"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName sets the table name for GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key is neither revoked nor expired
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// HasScope reports whether the key grants the given scope
+func (k *APIKey) HasScope(scope string) bool {
+	return utils.Contains(SplitList(k.Scopes), scope)
+}
+
+// AllowsSensorType reports whether the key may send data for the given sensor type.
+// An empty list means every sensor type is allowed.
+func (k *APIKey) AllowsSensorType(sensorType string) bool {
+	allowed := SplitList(k.AllowedSensorTypes)
+	return len(allowed) == 0 || utils.Contains(allowed, sensorType)
+}
+
+// AllowsDevice reports whether the key may send data for the given device (ID1).
+// An empty list means every device is allowed.
+func (k *APIKey) AllowsDevice(id1 string) bool {
+	allowed := SplitList(k.AllowedDevices)
+	return len(allowed) == 0 || utils.Contains(allowed, id1)
+}
+
+// JoinList stores a list as a comma separated column value
+func JoinList(values []string) string {
+	var cleaned []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+// SplitList reads a comma separated column value back into a list
+func SplitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/interfaces"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+type apiKeyContextKey struct{}
+
+// NewContextWithAPIKey returns a copy of ctx carrying the authenticated API key
+func NewContextWithAPIKey(ctx context.Context, apiKey *entities.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+}
+
+// APIKeyFromContext returns the authenticated API key stored in ctx, if any
+func APIKeyFromContext(ctx context.Context) (*entities.APIKey, bool) {
+	apiKey, ok := ctx.Value(apiKeyContextKey{}).(*entities.APIKey)
+	return apiKey, ok
+}
+
+// APIKeyAuthenticator verifies API keys sent as gRPC metadata
+type APIKeyAuthenticator struct {
+	apiKeyService interfaces.APIKeyServiceInterface
+	methodScopes  map[string]string
+	publicMethods map[string]bool
+}
+
+// NewAPIKeyAuthenticator creates an authenticator that requires a valid API key for every
+// method except publicMethods. methodScopes maps full method names to the scope they require.
+func NewAPIKeyAuthenticator(apiKeyService interfaces.APIKeyServiceInterface, methodScopes map[string]string, publicMethods ...string) *APIKeyAuthenticator {
+	public := make(map[string]bool, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return &APIKeyAuthenticator{
+		apiKeyService: apiKeyService,
+		methodScopes:  methodScopes,
+		publicMethods: public,
+	}
+}
+
+// UnaryInterceptor returns a unary server interceptor enforcing API key authentication
+func (a *APIKeyAuthenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns a stream server interceptor enforcing API key authentication
+func (a *APIKeyAuthenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate validates the API key in the incoming metadata and stores it in the context
+func (a *APIKeyAuthenticator) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if a.publicMethods[fullMethod] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(constants.APIKeyMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing api key")
+	}
+
+	apiKey, err := a.apiKeyService.ValidateAPIKey(ctx, values[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if scope, ok := a.methodScopes[fullMethod]; ok && !apiKey.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "api key lacks scope %s", scope)
+	}
+
+	return NewContextWithAPIKey(ctx, apiKey), nil
+}
+
+// authenticatedStream overrides the stream context with the authenticated one
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
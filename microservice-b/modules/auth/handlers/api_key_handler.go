@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+type APIKeyHandler struct {
+	apiKeyService interfaces.APIKeyServiceInterface
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService interfaces.APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Create godoc
+// @Summary Issue API key
+// @Description Issue a new generator API key for gRPC ingestion (admin only). The raw key is only returned once.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body dtos.CreateAPIKeyRequest true "API key parameters"
+// @Success 201 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid request"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /auth/api-keys [post]
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	var request dtos.CreateAPIKeyRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	userID, _ := c.Get("user_id").(uint)
+
+	response, err := h.apiKeyService.CreateAPIKey(c.Request().Context(), &request, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "API key created successfully",
+		Data:    response,
+	})
+}
+
+// List godoc
+// @Summary List API keys
+// @Description List issued generator API keys without their secrets (admin only)
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} shared.APIResponse
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /auth/api-keys [get]
+func (h *APIKeyHandler) List(c echo.Context) error {
+	apiKeys, err := h.apiKeyService.ListAPIKeys(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "API keys retrieved successfully",
+		Data:    apiKeys,
+	})
+}
+
+// Revoke godoc
+// @Summary Revoke API key
+// @Description Revoke a generator API key so it can no longer send data (admin only)
+// @Tags api-keys
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 404 {object} shared.APIResponse "API key not found"
+// @Security Bearer
+// @Router /auth/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   "Invalid ID format",
+		})
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.Request().Context(), uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, shared.APIResponse{
+				Status:  constants.StatusError,
+				Message: "API key not found",
+				Error:   err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "API key revoked successfully",
+		Data:    map[string]uint{"id": uint(id)},
+	})
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+)
+
+// APIKeyServiceInterface defines the interface for generator API key management
+type APIKeyServiceInterface interface {
+	CreateAPIKey(ctx context.Context, request *dtos.CreateAPIKeyRequest, createdBy uint) (*dtos.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context) ([]*dtos.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, id uint) error
+	ValidateAPIKey(ctx context.Context, rawKey string) (*entities.APIKey, error)
+}
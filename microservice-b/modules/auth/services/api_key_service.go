@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/interfaces"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+const (
+	apiKeyPrefix       = "wk_"
+	apiKeyDisplayChars = 11
+	apiKeyCacheTTL     = 30 * time.Second
+)
+
+var knownAPIKeyScopes = []string{
+	entities.APIKeyScopeSensorWrite,
+	entities.APIKeyScopeSensorRead,
+}
+
+type cachedAPIKey struct {
+	key      *entities.APIKey
+	cachedAt time.Time
+}
+
+type apiKeyService struct {
+	db    *gorm.DB
+	mu    sync.RWMutex
+	cache map[string]cachedAPIKey
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(db *gorm.DB) interfaces.APIKeyServiceInterface {
+	return &apiKeyService{
+		db:    db,
+		cache: make(map[string]cachedAPIKey),
+	}
+}
+
+// CreateAPIKey issues a new API key and returns the raw key once
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, request *dtos.CreateAPIKeyRequest, createdBy uint) (*dtos.CreateAPIKeyResponse, error) {
+	if request.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	scopes := request.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{entities.APIKeyScopeSensorWrite}
+	}
+	for _, scope := range scopes {
+		if !utils.Contains(knownAPIKeyScopes, scope) {
+			return nil, fmt.Errorf("unknown scope: %s", scope)
+		}
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := entities.APIKey{
+		Name:               request.Name,
+		KeyPrefix:          APIKeyDisplayPrefix(rawKey),
+		KeyHash:            HashAPIKey(rawKey),
+		Scopes:             entities.JoinList(scopes),
+		AllowedSensorTypes: entities.JoinList(request.AllowedSensorTypes),
+		AllowedDevices:     entities.JoinList(request.AllowedDevices),
+		CreatedBy:          createdBy,
+		ExpiresAt:          request.ExpiresAt,
+	}
+
+	if err := s.db.WithContext(ctx).Create(&apiKey).Error; err != nil {
+		return nil, err
+	}
+
+	return &dtos.CreateAPIKeyResponse{
+		APIKeyResponse: toAPIKeyResponse(&apiKey),
+		Key:            rawKey,
+	}, nil
+}
+
+// ListAPIKeys returns all issued API keys without their secrets
+func (s *apiKeyService) ListAPIKeys(ctx context.Context) ([]*dtos.APIKeyResponse, error) {
+	var apiKeys []*entities.APIKey
+	if err := s.db.WithContext(ctx).Order("created_at desc").Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dtos.APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		response := toAPIKeyResponse(apiKey)
+		responses = append(responses, &response)
+	}
+	return responses, nil
+}
+
+// RevokeAPIKey marks an API key as revoked so it can no longer authenticate
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id uint) error {
+	var apiKey entities.APIKey
+	if err := s.db.WithContext(ctx).First(&apiKey, id).Error; err != nil {
+		return err
+	}
+
+	if apiKey.RevokedAt == nil {
+		now := time.Now()
+		if err := s.db.WithContext(ctx).Model(&apiKey).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.cache, apiKey.KeyHash)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ValidateAPIKey resolves a raw API key to an active key record.
+// Lookups are cached briefly, so a revocation may take up to apiKeyCacheTTL to reach other replicas.
+func (s *apiKeyService) ValidateAPIKey(ctx context.Context, rawKey string) (*entities.APIKey, error) {
+	if rawKey == "" {
+		return nil, errors.New("api key is required")
+	}
+
+	keyHash := HashAPIKey(rawKey)
+	now := time.Now()
+
+	s.mu.RLock()
+	cached, ok := s.cache[keyHash]
+	s.mu.RUnlock()
+
+	apiKey := cached.key
+	if !ok || now.Sub(cached.cachedAt) > apiKeyCacheTTL {
+		var record entities.APIKey
+		err := s.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&record).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.New("invalid api key")
+			}
+			return nil, err
+		}
+
+		apiKey = &record
+		s.mu.Lock()
+		s.cache[keyHash] = cachedAPIKey{key: apiKey, cachedAt: now}
+		s.mu.Unlock()
+	}
+
+	if !apiKey.IsActive(now) {
+		return nil, errors.New("api key is revoked or expired")
+	}
+
+	return apiKey, nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 digest stored for an API key
+func HashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyDisplayPrefix returns the leading characters of a raw API key stored to identify it
+func APIKeyDisplayPrefix(rawKey string) string {
+	if len(rawKey) > apiKeyDisplayChars {
+		return rawKey[:apiKeyDisplayChars]
+	}
+	return rawKey
+}
+
+// generateAPIKey creates a new random raw API key
+func generateAPIKey() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(bytes), nil
+}
+
+// toAPIKeyResponse converts an API key entity to its response representation
+func toAPIKeyResponse(apiKey *entities.APIKey) dtos.APIKeyResponse {
+	return dtos.APIKeyResponse{
+		ID:                 apiKey.ID,
+		Name:               apiKey.Name,
+		KeyPrefix:          apiKey.KeyPrefix,
+		Scopes:             entities.SplitList(apiKey.Scopes),
+		AllowedSensorTypes: entities.SplitList(apiKey.AllowedSensorTypes),
+		AllowedDevices:     entities.SplitList(apiKey.AllowedDevices),
+		CreatedBy:          apiKey.CreatedBy,
+		ExpiresAt:          apiKey.ExpiresAt,
+		RevokedAt:          apiKey.RevokedAt,
+		CreatedAt:          apiKey.CreatedAt,
+	}
+}
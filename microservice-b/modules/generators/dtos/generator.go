@@ -0,0 +1,6 @@
+package dtos
+
+// FrequencyRequest represents generator frequency change request
+type FrequencyRequest struct {
+	Frequency string `json:"frequency" validate:"required" example:"5s"`
+}
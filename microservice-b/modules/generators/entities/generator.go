@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+// Generator connection states
+const (
+	GeneratorStateOnline  = "online"
+	GeneratorStateOffline = "offline"
+)
+
+// Generator commands
+const (
+	GeneratorActionStart        = "start"
+	GeneratorActionStop         = "stop"
+	GeneratorActionSetFrequency = "set_frequency"
+)
+
+// GeneratorStatus represents the state last reported by a generator
+type GeneratorStatus struct {
+	IsRunning     bool       `json:"is_running"`
+	Frequency     string     `json:"frequency"`
+	TotalSent     int64      `json:"total_sent"`
+	Errors        int64      `json:"errors"`
+	LastGenerated *time.Time `json:"last_generated,omitempty"`
+}
+
+// Generator represents a microservice-a instance registered over the control stream
+type Generator struct {
+	InstanceID     string     `json:"instance_id"`
+	SensorType     string     `json:"sensor_type"`
+	State          string     `json:"state"`
+	ConnectedAt    time.Time  `json:"connected_at"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	DisconnectedAt *time.Time `json:"disconnected_at,omitempty"`
+	GeneratorStatus
+}
+
+// GeneratorCommand represents a command pushed to a generator
+type GeneratorCommand struct {
+	ID        string
+	Action    string
+	Frequency string
+}
+
+// GeneratorCommandResult represents a generator's answer to a command
+type GeneratorCommandResult struct {
+	CommandID string
+	Success   bool
+	Error     string
+}
@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authEntities "github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+	authGrpc "github.com/worlder-team/microservice-server/microservice-b/modules/auth/grpc"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/interfaces"
+	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+)
+
+// MethodScopes maps each GeneratorControlService method to the API key scope it requires
+var MethodScopes = map[string]string{
+	pb.GeneratorControlService_Connect_FullMethodName: authEntities.APIKeyScopeSensorWrite,
+}
+
+type controlServer struct {
+	pb.UnimplementedGeneratorControlServiceServer
+	registry interfaces.GeneratorRegistryInterface
+}
+
+// NewControlServer creates a new gRPC generator control server
+func NewControlServer(registry interfaces.GeneratorRegistryInterface) *controlServer {
+	return &controlServer{
+		registry: registry,
+	}
+}
+
+// RegisterControlServer registers the generator control server with gRPC
+func RegisterControlServer(s *grpc.Server, registry interfaces.GeneratorRegistryInterface) {
+	pb.RegisterGeneratorControlServiceServer(s, NewControlServer(registry))
+}
+
+// Connect registers a generator and relays commands to it until either side closes the stream
+func (s *controlServer) Connect(stream pb.GeneratorControlService_ConnectServer) error {
+	message, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	registration := message.GetRegister()
+	if registration == nil || registration.InstanceId == "" || registration.SensorType == "" {
+		return status.Error(codes.InvalidArgument, "first message must register the generator with instance_id and sensor_type")
+	}
+	if apiKey, ok := authGrpc.APIKeyFromContext(stream.Context()); ok && !apiKey.AllowsSensorType(registration.SensorType) {
+		return status.Errorf(codes.PermissionDenied, "api key may not send %s data", registration.SensorType)
+	}
+
+	session := s.registry.Connect(&entities.Generator{
+		InstanceID:      registration.InstanceId,
+		SensorType:      registration.SensorType,
+		GeneratorStatus: toGeneratorStatus(registration.Status),
+	})
+	defer session.Close()
+
+	// Receive reports on a separate goroutine so commands can be pushed while waiting
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			message, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			handleGeneratorMessage(session, message)
+		}
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-session.Done():
+			return status.Error(codes.Aborted, "generator reconnected on another stream")
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case command := <-session.Commands():
+			if err := stream.Send(toPBCommand(command)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleGeneratorMessage applies a status report or command result to the session
+func handleGeneratorMessage(session interfaces.GeneratorSession, message *pb.GeneratorMessage) {
+	switch payload := message.Payload.(type) {
+	case *pb.GeneratorMessage_Status:
+		session.UpdateStatus(toGeneratorStatus(payload.Status))
+	case *pb.GeneratorMessage_CommandResult:
+		if payload.CommandResult.Status != nil {
+			session.UpdateStatus(toGeneratorStatus(payload.CommandResult.Status))
+		}
+		session.CompleteCommand(&entities.GeneratorCommandResult{
+			CommandID: payload.CommandResult.CommandId,
+			Success:   payload.CommandResult.Success,
+			Error:     payload.CommandResult.Error,
+		})
+	}
+}
+
+// toGeneratorStatus converts a protobuf status report to the domain status
+func toGeneratorStatus(report *pb.GeneratorStatusReport) entities.GeneratorStatus {
+	if report == nil {
+		return entities.GeneratorStatus{}
+	}
+
+	generatorStatus := entities.GeneratorStatus{
+		IsRunning: report.IsRunning,
+		Frequency: report.Frequency,
+		TotalSent: report.TotalSent,
+		Errors:    report.Errors,
+	}
+	if report.LastGenerated != nil {
+		lastGenerated := report.LastGenerated.AsTime()
+		if lastGenerated.After(time.Unix(0, 0)) {
+			generatorStatus.LastGenerated = &lastGenerated
+		}
+	}
+	return generatorStatus
+}
+
+// toPBCommand converts a domain command to its protobuf representation
+func toPBCommand(command *entities.GeneratorCommand) *pb.GeneratorCommand {
+	action := pb.GeneratorCommand_UNKNOWN
+	switch command.Action {
+	case entities.GeneratorActionStart:
+		action = pb.GeneratorCommand_START
+	case entities.GeneratorActionStop:
+		action = pb.GeneratorCommand_STOP
+	case entities.GeneratorActionSetFrequency:
+		action = pb.GeneratorCommand_SET_FREQUENCY
+	}
+
+	return &pb.GeneratorCommand{
+		CommandId: command.ID,
+		Action:    action,
+		Frequency: command.Frequency,
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/services"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+type GeneratorHandler struct {
+	registry interfaces.GeneratorRegistryInterface
+}
+
+// NewGeneratorHandler creates a new generator control handler
+func NewGeneratorHandler(registry interfaces.GeneratorRegistryInterface) *GeneratorHandler {
+	return &GeneratorHandler{
+		registry: registry,
+	}
+}
+
+// List godoc
+// @Summary List generators
+// @Description List generators registered over the control stream with their last reported status (admin only)
+// @Tags generators
+// @Produce json
+// @Success 200 {object} shared.APIResponse
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /generators [get]
+func (h *GeneratorHandler) List(c echo.Context) error {
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Generators retrieved successfully",
+		Data:    h.registry.ListGenerators(),
+	})
+}
+
+// Get godoc
+// @Summary Get generator
+// @Description Get a single registered generator (admin only)
+// @Tags generators
+// @Produce json
+// @Param id path string true "Generator instance ID"
+// @Success 200 {object} shared.APIResponse
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 404 {object} shared.APIResponse "Generator not found"
+// @Security Bearer
+// @Router /generators/{id} [get]
+func (h *GeneratorHandler) Get(c echo.Context) error {
+	generator, err := h.registry.GetGenerator(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrNotFound,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Generator retrieved successfully",
+		Data:    generator,
+	})
+}
+
+// Start godoc
+// @Summary Start generator
+// @Description Start data generation on a registered generator (admin only)
+// @Tags generators
+// @Produce json
+// @Param id path string true "Generator instance ID"
+// @Success 200 {object} shared.APIResponse
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 404 {object} shared.APIResponse "Generator not found"
+// @Failure 409 {object} shared.APIResponse "Generator offline or already running"
+// @Failure 504 {object} shared.APIResponse "Generator did not respond"
+// @Security Bearer
+// @Router /generators/{id}/start [post]
+func (h *GeneratorHandler) Start(c echo.Context) error {
+	return h.sendCommand(c, &entities.GeneratorCommand{Action: entities.GeneratorActionStart}, "Data generation started successfully")
+}
+
+// Stop godoc
+// @Summary Stop generator
+// @Description Stop data generation on a registered generator (admin only)
+// @Tags generators
+// @Produce json
+// @Param id path string true "Generator instance ID"
+// @Success 200 {object} shared.APIResponse
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 404 {object} shared.APIResponse "Generator not found"
+// @Failure 409 {object} shared.APIResponse "Generator offline or not running"
+// @Failure 504 {object} shared.APIResponse "Generator did not respond"
+// @Security Bearer
+// @Router /generators/{id}/stop [post]
+func (h *GeneratorHandler) Stop(c echo.Context) error {
+	return h.sendCommand(c, &entities.GeneratorCommand{Action: entities.GeneratorActionStop}, "Data generation stopped successfully")
+}
+
+// SetFrequency godoc
+// @Summary Set generator frequency
+// @Description Change the data generation frequency of a registered generator (admin only)
+// @Tags generators
+// @Accept json
+// @Produce json
+// @Param id path string true "Generator instance ID"
+// @Param request body dtos.FrequencyRequest true "Frequency parameters"
+// @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid frequency"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 404 {object} shared.APIResponse "Generator not found"
+// @Failure 409 {object} shared.APIResponse "Generator offline"
+// @Failure 504 {object} shared.APIResponse "Generator did not respond"
+// @Security Bearer
+// @Router /generators/{id}/frequency [put]
+func (h *GeneratorHandler) SetFrequency(c echo.Context) error {
+	var request dtos.FrequencyRequest
+	if err := c.Bind(&request); err != nil || request.Frequency == "" {
+		message := "frequency is required"
+		if err != nil {
+			message = err.Error()
+		}
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   message,
+		})
+	}
+
+	command := &entities.GeneratorCommand{
+		Action:    entities.GeneratorActionSetFrequency,
+		Frequency: request.Frequency,
+	}
+	return h.sendCommand(c, command, "Frequency updated successfully")
+}
+
+// sendCommand pushes a command to the generator and reports its outcome
+func (h *GeneratorHandler) sendCommand(c echo.Context, command *entities.GeneratorCommand, message string) error {
+	generator, err := h.registry.SendCommand(c.Request().Context(), c.Param("id"), command)
+	if err != nil {
+		statusCode := http.StatusConflict
+		switch {
+		case errors.Is(err, services.ErrGeneratorNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, services.ErrCommandTimeout):
+			statusCode = http.StatusGatewayTimeout
+		case command.Action == entities.GeneratorActionSetFrequency && !errors.Is(err, services.ErrGeneratorOffline):
+			statusCode = http.StatusBadRequest
+		}
+
+		return c.JSON(statusCode, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: "Generator command failed",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: message,
+		Data:    generator,
+	})
+}
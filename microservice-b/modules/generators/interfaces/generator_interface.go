@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/entities"
+)
+
+// GeneratorSession is the registry's side of a generator's control stream
+type GeneratorSession interface {
+	Commands() <-chan *entities.GeneratorCommand
+	Done() <-chan struct{}
+	UpdateStatus(status entities.GeneratorStatus)
+	CompleteCommand(result *entities.GeneratorCommandResult)
+	Close()
+}
+
+// GeneratorRegistryInterface defines the interface for the live generator registry
+type GeneratorRegistryInterface interface {
+	Connect(generator *entities.Generator) GeneratorSession
+	ListGenerators() []*entities.Generator
+	GetGenerator(instanceID string) (*entities.Generator, error)
+	SendCommand(ctx context.Context, instanceID string, command *entities.GeneratorCommand) (*entities.Generator, error)
+}
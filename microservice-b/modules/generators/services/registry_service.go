@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/generators/interfaces"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+const (
+	commandBufferSize = 16
+	commandTimeout    = 10 * time.Second
+	offlineRetention  = time.Hour
+)
+
+var (
+	// ErrGeneratorNotFound is returned for instance IDs that never registered
+	ErrGeneratorNotFound = errors.New("generator not found")
+	// ErrGeneratorOffline is returned when commanding a generator without a live control stream
+	ErrGeneratorOffline = errors.New("generator is offline")
+	// ErrCommandTimeout is returned when a generator does not answer a command in time
+	ErrCommandTimeout = errors.New("generator did not acknowledge the command in time")
+)
+
+// generatorSession tracks a single control stream
+type generatorSession struct {
+	registry   *registryService
+	instanceID string
+	commands   chan *entities.GeneratorCommand
+	done       chan struct{}
+	once       sync.Once
+	pending    map[string]chan *entities.GeneratorCommandResult
+}
+
+// Commands returns the commands to push down the control stream
+func (s *generatorSession) Commands() <-chan *entities.GeneratorCommand {
+	return s.commands
+}
+
+// Done is closed once the session has been closed or replaced by a newer connection
+func (s *generatorSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// UpdateStatus records a status report from the generator
+func (s *generatorSession) UpdateStatus(status entities.GeneratorStatus) {
+	s.registry.mu.Lock()
+	defer s.registry.mu.Unlock()
+
+	if generator, ok := s.registry.currentGenerator(s); ok {
+		generator.GeneratorStatus = status
+		generator.LastSeenAt = time.Now()
+	}
+}
+
+// CompleteCommand hands a command result to the caller waiting on it
+func (s *generatorSession) CompleteCommand(result *entities.GeneratorCommandResult) {
+	s.registry.mu.Lock()
+	waiter, ok := s.pending[result.CommandID]
+	delete(s.pending, result.CommandID)
+	s.registry.mu.Unlock()
+
+	if ok {
+		waiter <- result
+	}
+}
+
+// Close marks the generator offline unless a newer connection already replaced this one
+func (s *generatorSession) Close() {
+	s.registry.mu.Lock()
+	defer s.registry.mu.Unlock()
+
+	if generator, ok := s.registry.currentGenerator(s); ok {
+		now := time.Now()
+		generator.State = entities.GeneratorStateOffline
+		generator.DisconnectedAt = &now
+		delete(s.registry.sessions, s.instanceID)
+	}
+	s.close()
+}
+
+// close releases the session, the registry lock must be held
+func (s *generatorSession) close() {
+	s.once.Do(func() {
+		close(s.done)
+		for commandID, waiter := range s.pending {
+			delete(s.pending, commandID)
+			close(waiter)
+		}
+	})
+}
+
+type registryService struct {
+	mu         sync.Mutex
+	generators map[string]*entities.Generator
+	sessions   map[string]*generatorSession
+}
+
+// NewRegistryService creates a new in-memory generator registry
+func NewRegistryService() interfaces.GeneratorRegistryInterface {
+	return &registryService{
+		generators: make(map[string]*entities.Generator),
+		sessions:   make(map[string]*generatorSession),
+	}
+}
+
+// Connect registers a generator and returns its session, replacing any previous connection
+func (r *registryService) Connect(generator *entities.Generator) interfaces.GeneratorSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if previous, ok := r.sessions[generator.InstanceID]; ok {
+		previous.close()
+	}
+	r.pruneOffline()
+
+	now := time.Now()
+	generator.State = entities.GeneratorStateOnline
+	generator.ConnectedAt = now
+	generator.LastSeenAt = now
+	generator.DisconnectedAt = nil
+	r.generators[generator.InstanceID] = generator
+
+	session := &generatorSession{
+		registry:   r,
+		instanceID: generator.InstanceID,
+		commands:   make(chan *entities.GeneratorCommand, commandBufferSize),
+		done:       make(chan struct{}),
+		pending:    make(map[string]chan *entities.GeneratorCommandResult),
+	}
+	r.sessions[generator.InstanceID] = session
+
+	return session
+}
+
+// ListGenerators returns a snapshot of all known generators ordered by instance ID
+func (r *registryService) ListGenerators() []*entities.Generator {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	generators := make([]*entities.Generator, 0, len(r.generators))
+	for _, generator := range r.generators {
+		snapshot := *generator
+		generators = append(generators, &snapshot)
+	}
+	sort.Slice(generators, func(i, j int) bool {
+		return generators[i].InstanceID < generators[j].InstanceID
+	})
+	return generators
+}
+
+// GetGenerator returns a snapshot of a single generator
+func (r *registryService) GetGenerator(instanceID string) (*entities.Generator, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	generator, ok := r.generators[instanceID]
+	if !ok {
+		return nil, ErrGeneratorNotFound
+	}
+	snapshot := *generator
+	return &snapshot, nil
+}
+
+// SendCommand pushes a command to a generator and waits until it has been applied
+func (r *registryService) SendCommand(ctx context.Context, instanceID string, command *entities.GeneratorCommand) (*entities.Generator, error) {
+	if command.Action == entities.GeneratorActionSetFrequency {
+		if _, err := utils.ParseDuration(command.Frequency); err != nil {
+			return nil, fmt.Errorf("invalid frequency format: %v", err)
+		}
+	}
+	command.ID = utils.GenerateID(8)
+
+	r.mu.Lock()
+	if _, ok := r.generators[instanceID]; !ok {
+		r.mu.Unlock()
+		return nil, ErrGeneratorNotFound
+	}
+	session, ok := r.sessions[instanceID]
+	if !ok {
+		r.mu.Unlock()
+		return nil, ErrGeneratorOffline
+	}
+	waiter := make(chan *entities.GeneratorCommandResult, 1)
+	session.pending[command.ID] = waiter
+	r.mu.Unlock()
+
+	select {
+	case session.commands <- command:
+	default:
+		r.forgetCommand(session, command.ID)
+		return nil, fmt.Errorf("too many commands pending for generator %s", instanceID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	select {
+	case result, ok := <-waiter:
+		if !ok {
+			return nil, ErrGeneratorOffline
+		}
+		if !result.Success {
+			return nil, errors.New(result.Error)
+		}
+	case <-ctx.Done():
+		r.forgetCommand(session, command.ID)
+		return nil, ErrCommandTimeout
+	}
+
+	return r.GetGenerator(instanceID)
+}
+
+// forgetCommand stops waiting for a command result
+func (r *registryService) forgetCommand(session *generatorSession, commandID string) {
+	r.mu.Lock()
+	delete(session.pending, commandID)
+	r.mu.Unlock()
+}
+
+// currentGenerator returns the generator owned by the session, the lock must be held
+func (r *registryService) currentGenerator(session *generatorSession) (*entities.Generator, bool) {
+	if r.sessions[session.instanceID] != session {
+		return nil, false
+	}
+	generator, ok := r.generators[session.instanceID]
+	return generator, ok
+}
+
+// pruneOffline forgets generators that have been offline for longer than offlineRetention
+func (r *registryService) pruneOffline() {
+	cutoff := time.Now().Add(-offlineRetention)
+	for instanceID, generator := range r.generators {
+		if generator.DisconnectedAt != nil && generator.DisconnectedAt.Before(cutoff) {
+			delete(r.generators, instanceID)
+		}
+	}
+}
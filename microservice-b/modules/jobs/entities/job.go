@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job states
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Job represents a long running background task, such as a bulk import, whose progress is polled by clients.
+// Jobs are stored in the database so that any replica can answer a poll.
+type Job struct {
+	ID         string          `json:"id" gorm:"primaryKey;type:varchar(32)"`
+	Type       string          `json:"type" gorm:"type:varchar(50);not null;index"`
+	Status     string          `json:"status" gorm:"type:varchar(20);not null"`
+	Progress   json.RawMessage `json:"progress,omitempty" gorm:"type:text" swaggertype:"object"`
+	Result     json.RawMessage `json:"result,omitempty" gorm:"type:string;size:16777215" swaggertype:"object"`
+	Error      string          `json:"error,omitempty" gorm:"type:text"`
+	CreatedBy  uint            `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+// TableName sets the table name for GORM
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// IsFinished reports whether the job has stopped running
+func (j *Job) IsFinished() bool {
+	return j.Status != JobStatusRunning
+}
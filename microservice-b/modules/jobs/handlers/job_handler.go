@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/jobs/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/jobs/services"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+type JobHandler struct {
+	jobService interfaces.JobServiceInterface
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobService interfaces.JobServiceInterface) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// Get godoc
+// @Summary Get job
+// @Description Poll the status, progress and result of a background job. Users can only see their own jobs, admins see all jobs.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} shared.APIResponse{data=entities.Job}
+// @Failure 404 {object} shared.APIResponse "Job not found"
+// @Security Bearer
+// @Router /jobs/{id} [get]
+func (h *JobHandler) Get(c echo.Context) error {
+	job, err := h.jobService.GetJob(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, services.ErrJobNotFound) {
+		return c.JSON(http.StatusNotFound, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrNotFound,
+			Error:   err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Job retrieved successfully",
+		Data:    job,
+	})
+}
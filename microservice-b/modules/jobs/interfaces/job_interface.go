@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/jobs/entities"
+)
+
+// JobTask is the work of a job. It reports progress with any JSON serialisable value and returns the job result.
+// ctx is cancelled when the service shuts down.
+type JobTask func(ctx context.Context, progress func(value interface{})) (interface{}, error)
+
+// JobServiceInterface defines the interface for background jobs
+type JobServiceInterface interface {
+	Start(ctx context.Context, jobType string, createdBy uint, task JobTask) (*entities.Job, error)
+	GetJob(ctx context.Context, id string) (*entities.Job, error)
+	Close()
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/actor"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/jobs/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/jobs/interfaces"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// ErrJobNotFound is returned for unknown job IDs
+var ErrJobNotFound = errors.New("job not found")
+
+const (
+	// heartbeatInterval is how often a running job's progress is saved
+	heartbeatInterval = 5 * time.Second
+	// staleAfter is how long a running job may go without a heartbeat before it is considered
+	// lost, e.g. because the replica running it crashed
+	staleAfter = time.Minute
+	// saveTimeout bounds the final save of a job, which also runs during shutdown
+	saveTimeout = 10 * time.Second
+)
+
+type jobService struct {
+	db     *gorm.DB
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewJobService creates a new job service. Jobs run in the background of the replica that started them.
+func NewJobService(db *gorm.DB) interfaces.JobServiceInterface {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &jobService{
+		db:     db,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start records a new job and runs task in the background
+func (s *jobService) Start(ctx context.Context, jobType string, createdBy uint, task interfaces.JobTask) (*entities.Job, error) {
+	if s.ctx.Err() != nil {
+		return nil, errors.New("job service is shutting down")
+	}
+
+	job := &entities.Job{
+		ID:        utils.GenerateID(8),
+		Type:      jobType,
+		Status:    entities.JobStatusRunning,
+		CreatedBy: createdBy,
+	}
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.run(*job, task)
+
+	return job, nil
+}
+
+// GetJob returns a job, marking it failed if the replica running it stopped reporting. Only admins
+// and the user who started the job, taken from the actor of ctx, can see it; it is not found for others.
+func (s *jobService) GetJob(ctx context.Context, id string) (*entities.Job, error) {
+	a, ok := actor.FromContext(ctx)
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	query := s.db.WithContext(ctx).Where("id = ?", id)
+	if a.Role != constants.RoleAdmin {
+		query = query.Where("created_by = ?", a.UserID)
+	}
+
+	var job entities.Job
+	err := query.First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !job.IsFinished() && time.Since(job.UpdatedAt) > staleAfter {
+		now := time.Now()
+		result := s.db.WithContext(ctx).Model(&entities.Job{}).
+			Where("id = ? AND status = ? AND updated_at = ?", job.ID, entities.JobStatusRunning, job.UpdatedAt).
+			Updates(map[string]interface{}{"status": entities.JobStatusFailed, "error": "job was interrupted", "finished_at": now})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			job.Status, job.Error, job.FinishedAt = entities.JobStatusFailed, "job was interrupted", &now
+		}
+	}
+
+	return &job, nil
+}
+
+// Close cancels running jobs and waits for them to record their outcome
+func (s *jobService) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run executes a job, saving its progress every heartbeat and its outcome at the end
+func (s *jobService) run(job entities.Job, task interfaces.JobTask) {
+	defer s.wg.Done()
+
+	var mu sync.Mutex
+	var progress interface{}
+	report := func(value interface{}) {
+		mu.Lock()
+		progress = value
+		mu.Unlock()
+	}
+	snapshot := func() json.RawMessage {
+		mu.Lock()
+		defer mu.Unlock()
+		if progress == nil {
+			return nil
+		}
+		encoded, err := json.Marshal(progress)
+		if err != nil {
+			return nil
+		}
+		return encoded
+	}
+
+	done := make(chan struct{})
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// Saving also refreshes updated_at, which tells pollers the job is alive
+				updates := map[string]interface{}{"updated_at": time.Now()}
+				if encoded := snapshot(); encoded != nil {
+					updates["progress"] = encoded
+				}
+				if err := s.db.WithContext(s.ctx).Model(&entities.Job{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil && s.ctx.Err() == nil {
+					utils.Warn(fmt.Sprintf("Failed to save progress of job %s: %v", job.ID, err))
+				}
+			}
+		}
+	}()
+
+	result, err := s.execute(task, report)
+	close(done)
+	<-heartbeatDone
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": entities.JobStatusSucceeded, "finished_at": now, "progress": snapshot()}
+	if err != nil {
+		updates["status"] = entities.JobStatusFailed
+		updates["error"] = err.Error()
+	}
+	if result != nil {
+		encoded, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			utils.Error(fmt.Sprintf("Failed to encode result of job %s: %v", job.ID, marshalErr))
+		} else {
+			updates["result"] = json.RawMessage(encoded)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+	if err := s.db.WithContext(ctx).Model(&entities.Job{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		utils.Error(fmt.Sprintf("Failed to save outcome of job %s: %v", job.ID, err))
+		return
+	}
+	utils.Info(fmt.Sprintf("Job %s (%s) %s", job.ID, job.Type, updates["status"]))
+}
+
+// execute runs a task, turning a panic into a job failure
+func (s *jobService) execute(task interfaces.JobTask, report func(value interface{})) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return task(s.ctx, report)
+}
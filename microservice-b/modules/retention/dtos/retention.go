@@ -0,0 +1,51 @@
+package dtos
+
+import (
+	"errors"
+	"time"
+)
+
+// maxRetentionDays bounds retention periods to 100 years
+const maxRetentionDays = 36500
+
+// PolicyRequest represents retention policy create or update request
+type PolicyRequest struct {
+	RawRetentionDays    int `json:"raw_retention_days" example:"30"`
+	RollupRetentionDays int `json:"rollup_retention_days" example:"730"`
+}
+
+// Validate checks the retention periods; 0 keeps data forever
+func (r *PolicyRequest) Validate() error {
+	if r.RawRetentionDays < 0 || r.RawRetentionDays > maxRetentionDays {
+		return errors.New("raw_retention_days must be between 0 and 36500")
+	}
+	if r.RollupRetentionDays < 0 || r.RollupRetentionDays > maxRetentionDays {
+		return errors.New("rollup_retention_days must be between 0 and 36500")
+	}
+	return nil
+}
+
+// Purge target kinds
+const (
+	PurgeKindRaw     = "raw"     // Readings older than the raw retention
+	PurgeKindRollup  = "rollup"  // Rollup buckets older than the rollup retention
+	PurgeKindDeleted = "deleted" // Soft-deleted readings past the grace period
+)
+
+// PurgeTarget is a set of rows removed by a purge
+type PurgeTarget struct {
+	Kind       string    `json:"kind"`
+	SensorType string    `json:"sensor_type,omitempty"`
+	Table      string    `json:"table"`
+	Cutoff     time.Time `json:"cutoff"`
+	Rows       int64     `json:"rows"`
+}
+
+// PurgeReport summarises a purge, or the rows it would remove for a dry run
+type PurgeReport struct {
+	DryRun     bool          `json:"dry_run"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Rows       int64         `json:"rows"`
+	Targets    []PurgeTarget `json:"targets"`
+}
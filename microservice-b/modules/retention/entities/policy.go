@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// DefaultPolicySensorType is the sensor type of the policy applied to sensor types without a policy of their own
+const DefaultPolicySensorType = "*"
+
+// RetentionPolicy defines how long readings and rollups of a sensor type are kept.
+// A retention of 0 days keeps the data forever.
+type RetentionPolicy struct {
+	ID                  uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SensorType          string    `json:"sensor_type" gorm:"type:varchar(50);uniqueIndex;not null"`
+	RawRetentionDays    int       `json:"raw_retention_days" gorm:"not null;default:0"`
+	RollupRetentionDays int       `json:"rollup_retention_days" gorm:"not null;default:0"`
+	CreatedAt           time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName sets the table name for GORM
+func (RetentionPolicy) TableName() string {
+	return "retention_policies"
+}
+
+// IsDefault reports whether the policy applies to sensor types without a policy of their own
+func (p *RetentionPolicy) IsDefault() bool {
+	return p.SensorType == DefaultPolicySensorType
+}
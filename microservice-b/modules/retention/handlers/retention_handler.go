@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/services"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+type RetentionHandler struct {
+	retentionService interfaces.RetentionServiceInterface
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retentionService interfaces.RetentionServiceInterface) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// ListPolicies godoc
+// @Summary List retention policies
+// @Description List the retention policies per sensor type; the "*" policy applies to sensor types without their own (admin only)
+// @Tags retention
+// @Produce json
+// @Success 200 {object} shared.APIResponse
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /retention/policies [get]
+func (h *RetentionHandler) ListPolicies(c echo.Context) error {
+	policies, err := h.retentionService.ListPolicies(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Retention policies retrieved successfully",
+		Data:    policies,
+	})
+}
+
+// SetPolicy godoc
+// @Summary Set retention policy
+// @Description Create or replace the retention policy of a sensor type, "*" for the default policy. A retention of 0 days keeps data forever (admin only)
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Param sensor_type path string true "Sensor type or *"
+// @Param request body dtos.PolicyRequest true "Retention periods"
+// @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid policy"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /retention/policies/{sensor_type} [put]
+func (h *RetentionHandler) SetPolicy(c echo.Context) error {
+	var request dtos.PolicyRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	policy, err := h.retentionService.SetPolicy(c.Request().Context(), c.Param("sensor_type"), &request)
+	if err != nil {
+		statusCode, message := http.StatusInternalServerError, constants.ErrInternalServer
+		if errors.Is(err, services.ErrInvalidPolicy) {
+			statusCode, message = http.StatusBadRequest, constants.ErrInvalidRequest
+		}
+		return c.JSON(statusCode, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: message,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Retention policy saved successfully",
+		Data:    policy,
+	})
+}
+
+// DeletePolicy godoc
+// @Summary Delete retention policy
+// @Description Delete the retention policy of a sensor type, which then falls back to the default policy (admin only)
+// @Tags retention
+// @Produce json
+// @Param sensor_type path string true "Sensor type or *"
+// @Success 200 {object} shared.APIResponse
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 404 {object} shared.APIResponse "Policy not found"
+// @Security Bearer
+// @Router /retention/policies/{sensor_type} [delete]
+func (h *RetentionHandler) DeletePolicy(c echo.Context) error {
+	if err := h.retentionService.DeletePolicy(c.Request().Context(), c.Param("sensor_type")); err != nil {
+		statusCode, message := http.StatusInternalServerError, constants.ErrInternalServer
+		if errors.Is(err, services.ErrPolicyNotFound) {
+			statusCode, message = http.StatusNotFound, constants.ErrNotFound
+		}
+		return c.JSON(statusCode, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: message,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Retention policy deleted successfully",
+	})
+}
+
+// Preview godoc
+// @Summary Preview purge
+// @Description Dry run of a purge: count the rows each policy would remove right now, without deleting anything (admin only)
+// @Tags retention
+// @Produce json
+// @Success 200 {object} shared.APIResponse{data=dtos.PurgeReport}
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /retention/preview [get]
+func (h *RetentionHandler) Preview(c echo.Context) error {
+	return h.purge(c, true, "Purge preview computed successfully")
+}
+
+// Purge godoc
+// @Summary Run purge
+// @Description Remove the data past its retention now instead of waiting for the scheduled purge (admin only)
+// @Tags retention
+// @Produce json
+// @Success 200 {object} shared.APIResponse{data=dtos.PurgeReport}
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 409 {object} shared.APIResponse "Purge already running"
+// @Security Bearer
+// @Router /retention/purge [post]
+func (h *RetentionHandler) Purge(c echo.Context) error {
+	return h.purge(c, false, "Purge completed successfully")
+}
+
+// purge runs a purge or dry run and reports the removed rows
+func (h *RetentionHandler) purge(c echo.Context, dryRun bool, message string) error {
+	report, err := h.retentionService.Purge(c.Request().Context(), dryRun)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPurgeRunning) {
+			statusCode = http.StatusConflict
+		}
+		return c.JSON(statusCode, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: "Purge failed",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: message,
+		Data:    report,
+	})
+}
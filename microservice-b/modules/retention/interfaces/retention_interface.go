@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/entities"
+)
+
+// RetentionServiceInterface defines the interface for data retention management
+type RetentionServiceInterface interface {
+	ListPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error)
+	SetPolicy(ctx context.Context, sensorType string, request *dtos.PolicyRequest) (*entities.RetentionPolicy, error)
+	DeletePolicy(ctx context.Context, sensorType string) error
+	Purge(ctx context.Context, dryRun bool) (*dtos.PurgeReport, error)
+}
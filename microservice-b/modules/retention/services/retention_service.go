@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/interfaces"
+	sensorInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/rollups"
+)
+
+var (
+	// ErrInvalidPolicy is returned for policies with an invalid sensor type or retention period
+	ErrInvalidPolicy = errors.New("invalid retention policy")
+	// ErrPolicyNotFound is returned when deleting a policy that does not exist
+	ErrPolicyNotFound = errors.New("retention policy not found")
+	// ErrPurgeRunning is returned when a purge is started while another one is running on this replica
+	ErrPurgeRunning = errors.New("a purge is already running")
+)
+
+const (
+	maxSensorTypeLength = 50
+	rawTable            = "sensor_data"
+)
+
+// Config holds retention settings
+type Config struct {
+	DeletedGrace   time.Duration // Soft-deleted readings are purged this long after deletion, 0 keeps them
+	ChunkSize      int           // Maximum rows removed per statement
+	ChunkPause     time.Duration // Pause between statements so other writers get the table
+	WaitForRollups bool          // Only purge readings whose changes the rollup job has processed
+}
+
+type retentionService struct {
+	db      *gorm.DB
+	cfg     Config
+	cache   sensorInterfaces.CacheInvalidator
+	running atomic.Bool
+}
+
+// NewRetentionService creates a new retention service. The sensor data cache is invalidated after
+// rows are purged; cache may be nil when reads are not cached.
+func NewRetentionService(db *gorm.DB, cfg Config, cache sensorInterfaces.CacheInvalidator) interfaces.RetentionServiceInterface {
+	if cfg.ChunkSize < 1 {
+		cfg.ChunkSize = 1000
+	}
+	if cfg.ChunkPause < 0 {
+		cfg.ChunkPause = 0
+	}
+	return &retentionService{db: db, cfg: cfg, cache: cache}
+}
+
+// ListPolicies returns all retention policies, the default policy first
+func (s *retentionService) ListPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error) {
+	var policies []*entities.RetentionPolicy
+	if err := s.db.WithContext(ctx).Order("sensor_type").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SetPolicy creates or replaces the policy of a sensor type
+func (s *retentionService) SetPolicy(ctx context.Context, sensorType string, request *dtos.PolicyRequest) (*entities.RetentionPolicy, error) {
+	if sensorType == "" || len(sensorType) > maxSensorTypeLength {
+		return nil, fmt.Errorf("%w: sensor_type must be 1 to %d characters", ErrInvalidPolicy, maxSensorTypeLength)
+	}
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+
+	var policy entities.RetentionPolicy
+	err := s.db.WithContext(ctx).Where("sensor_type = ?", sensorType).First(&policy).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	policy.SensorType = sensorType
+	policy.RawRetentionDays = request.RawRetentionDays
+	policy.RollupRetentionDays = request.RollupRetentionDays
+	if err := s.db.WithContext(ctx).Save(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// DeletePolicy removes the policy of a sensor type, which then falls back to the default policy
+func (s *retentionService) DeletePolicy(ctx context.Context, sensorType string) error {
+	result := s.db.WithContext(ctx).Where("sensor_type = ?", sensorType).Delete(&entities.RetentionPolicy{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+// Purge removes the data past its retention in chunks. A dry run only counts the rows that would be removed.
+func (s *retentionService) Purge(ctx context.Context, dryRun bool) (*dtos.PurgeReport, error) {
+	if !dryRun {
+		if !s.running.CompareAndSwap(false, true) {
+			return nil, ErrPurgeRunning
+		}
+		defer s.running.Store(false)
+	}
+
+	report := &dtos.PurgeReport{DryRun: dryRun, StartedAt: time.Now()}
+	targets, err := s.plan(ctx, report.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Targets = make([]dtos.PurgeTarget, 0, len(targets))
+	for _, target := range targets {
+		if dryRun {
+			target.Rows, err = s.count(ctx, target)
+		} else {
+			target.Rows, err = s.delete(ctx, target)
+		}
+		report.Rows += target.Rows
+		if err != nil {
+			s.invalidateCache(ctx, report.Rows)
+			return nil, fmt.Errorf("purge of %s stopped after %d rows: %w", target.Table, report.Rows, err)
+		}
+		report.Targets = append(report.Targets, target.PurgeTarget)
+	}
+	if !dryRun {
+		s.invalidateCache(ctx, report.Rows)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// invalidateCache drops the cached sensor data reads once rows have been purged
+func (s *retentionService) invalidateCache(ctx context.Context, rows int64) {
+	if s.cache != nil && rows > 0 {
+		s.cache.InvalidateAll(ctx)
+	}
+}
+
+// purgeTarget is a report target along with the condition selecting its rows
+type purgeTarget struct {
+	dtos.PurgeTarget
+	where string
+	args  []interface{}
+}
+
+// plan turns the policies into the set of rows to remove as of now
+func (s *retentionService) plan(ctx context.Context, now time.Time) ([]purgeTarget, error) {
+	policies, err := s.ListPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ownTypes []string
+	for _, policy := range policies {
+		if !policy.IsDefault() {
+			ownTypes = append(ownTypes, policy.SensorType)
+		}
+	}
+
+	// Readings changed after the rollup watermark are kept until the rollup job has folded them in,
+	// otherwise removing them would leave their rollup buckets stale
+	settled := ""
+	var settledArgs []interface{}
+	if s.cfg.WaitForRollups {
+		watermark, err := rollups.Watermark(ctx, s.db)
+		if err != nil {
+			return nil, err
+		}
+		settled = " AND updated_at <= ? AND (deleted_at IS NULL OR deleted_at <= ?)"
+		settledArgs = []interface{}{watermark, watermark}
+	}
+
+	var targets []purgeTarget
+	for _, policy := range policies {
+		sensorWhere, sensorArgs := "sensor_type = ?", []interface{}{policy.SensorType}
+		if policy.IsDefault() {
+			sensorWhere, sensorArgs = "1 = 1", nil
+			if len(ownTypes) > 0 {
+				sensorWhere, sensorArgs = "sensor_type NOT IN ?", []interface{}{ownTypes}
+			}
+		}
+
+		if policy.RawRetentionDays > 0 {
+			cutoff := now.AddDate(0, 0, -policy.RawRetentionDays)
+			targets = append(targets, purgeTarget{
+				PurgeTarget: dtos.PurgeTarget{Kind: dtos.PurgeKindRaw, SensorType: policy.SensorType, Table: rawTable, Cutoff: cutoff},
+				where:       sensorWhere + " AND timestamp < ?" + settled,
+				args:        append(append(append([]interface{}{}, sensorArgs...), cutoff), settledArgs...),
+			})
+		}
+
+		if policy.RollupRetentionDays > 0 {
+			cutoff := now.AddDate(0, 0, -policy.RollupRetentionDays)
+			for _, resolution := range rollups.Resolutions {
+				// Only buckets that end by the cutoff are removed
+				targets = append(targets, purgeTarget{
+					PurgeTarget: dtos.PurgeTarget{Kind: dtos.PurgeKindRollup, SensorType: policy.SensorType, Table: resolution.Table, Cutoff: cutoff},
+					where:       sensorWhere + " AND bucket_start <= ?",
+					args:        append(append([]interface{}{}, sensorArgs...), cutoff.Add(-resolution.Size)),
+				})
+			}
+		}
+	}
+
+	if s.cfg.DeletedGrace > 0 {
+		cutoff := now.Add(-s.cfg.DeletedGrace)
+		targets = append(targets, purgeTarget{
+			PurgeTarget: dtos.PurgeTarget{Kind: dtos.PurgeKindDeleted, Table: rawTable, Cutoff: cutoff},
+			where:       "deleted_at IS NOT NULL AND deleted_at < ?" + settled,
+			args:        append([]interface{}{cutoff}, settledArgs...),
+		})
+	}
+
+	return targets, nil
+}
+
+// count returns the number of rows a target currently selects
+func (s *retentionService) count(ctx context.Context, target purgeTarget) (int64, error) {
+	var rows int64
+	err := s.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM "+target.Table+" WHERE "+target.where, target.args...).Scan(&rows).Error
+	return rows, err
+}
+
+// delete removes the rows of a target ChunkSize at a time so no statement holds locks for long
+func (s *retentionService) delete(ctx context.Context, target purgeTarget) (int64, error) {
+	query := database.DialectOf(s.db).DeleteLimit(target.Table, target.where)
+	args := append(append([]interface{}{}, target.args...), s.cfg.ChunkSize)
+
+	var total int64
+	for {
+		result := s.db.WithContext(ctx).Exec(query, args...)
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < int64(s.cfg.ChunkSize) {
+			return total, nil
+		}
+
+		if s.cfg.ChunkPause > 0 {
+			timer := time.NewTimer(s.cfg.ChunkPause)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return total, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/retention/interfaces"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// Scheduler enforces the retention policies by purging periodically
+type Scheduler struct {
+	service  interfaces.RetentionServiceInterface
+	interval time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewScheduler creates a purge scheduler
+func NewScheduler(service interfaces.RetentionServiceInterface, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Scheduler{service: service, interval: interval}
+}
+
+// Start purges every interval in the background until Close is called
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go s.loop(ctx)
+}
+
+// Close stops the scheduler, interrupting a running purge between chunks
+func (s *Scheduler) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		report, err := s.service.Purge(ctx, false)
+		switch {
+		case errors.Is(err, ErrPurgeRunning):
+			utils.Info("Skipping scheduled purge, a purge is already running")
+		case err != nil:
+			if ctx.Err() == nil {
+				utils.Error(fmt.Sprintf("Retention purge failed: %v", err))
+			}
+		case report.Rows > 0:
+			utils.Info(fmt.Sprintf("Retention purge removed %d rows in %s", report.Rows, report.FinishedAt.Sub(report.StartedAt).Round(time.Millisecond)))
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+const (
+	keyPrefix = "sensor:cache"
+
+	// generationKey holds the cache generation. Entry and index keys include it, so incrementing it
+	// invalidates the whole cache at once; the orphaned keys expire with their TTL.
+	generationKey = keyPrefix + ":gen"
+
+	allTypes = "*"
+	allDays  = "all"
+
+	// maxIndexDays is the longest range indexed day by day, longer ranges are indexed under allDays
+	maxIndexDays = 31
+)
+
+// entryKey returns the key of a cached read
+func entryKey(generation int64, name string) string {
+	return fmt.Sprintf("%s:%d:%s", keyPrefix, generation, name)
+}
+
+// indexKey returns the key of the set listing the cached reads of a scope
+func indexKey(generation int64, scope string) string {
+	return fmt.Sprintf("%s:%d:idx:%s", keyPrefix, generation, scope)
+}
+
+// versionKey returns the key of the counter incremented on each invalidation of a scope
+func versionKey(scope string) string {
+	return keyPrefix + ":ver:" + scope
+}
+
+// readingName returns the name of the cached read of one reading
+func readingName(id uint) string {
+	return fmt.Sprintf("get:%d", id)
+}
+
+// queryName identifies a read by its operation and normalized parameters
+func queryName(operation string, params ...interface{}) (string, error) {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return operation + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeFilter returns a copy of filter with times in UTC and value lists sorted and deduplicated,
+// so that equivalent filters share cache entries
+func normalizeFilter(filter *dtos.SensorDataFilter) dtos.SensorDataFilter {
+	if filter == nil {
+		return dtos.SensorDataFilter{}
+	}
+	normalized := *filter
+	if normalized.FromTime != nil {
+		from := normalized.FromTime.UTC()
+		normalized.FromTime = &from
+	}
+	if normalized.ToTime != nil {
+		to := normalized.ToTime.UTC()
+		normalized.ToTime = &to
+	}
+	normalized.SensorTypes = sortedSet(normalized.SensorTypes)
+	normalized.ID1s = sortedSet(normalized.ID1s)
+	return normalized
+}
+
+// normalizePagination returns pagination with the defaults ListSensorData applies
+func normalizePagination(pagination *dtos.PaginationParams) dtos.PaginationParams {
+	if pagination == nil {
+		return dtos.PaginationParams{Page: 1, PageSize: constants.DefaultPageSize, Sort: "timestamp", Order: "desc"}
+	}
+	normalized := *pagination
+	if normalized.Page < 1 {
+		normalized.Page = 1
+	}
+	if normalized.PageSize < 1 || normalized.PageSize > constants.MaxPageSize {
+		normalized.PageSize = constants.DefaultPageSize
+	}
+	return normalized
+}
+
+// sortedSet returns the distinct values sorted, nil for none
+func sortedSet(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	set := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			set = append(set, value)
+		}
+	}
+	sort.Strings(set)
+	return set
+}
+
+// readingScope names the cached read of one reading
+func readingScope(id uint) string {
+	return fmt.Sprintf("id:%d", id)
+}
+
+// scope names the cached reads of a sensor type over a day
+func scope(sensorType, day string) string {
+	return sensorType + ":" + day
+}
+
+// dayOf returns the UTC day of t
+func dayOf(t time.Time) string {
+	return t.UTC().Format("20060102")
+}
+
+// readScopes returns the scopes a read of filter is indexed under: the sensor types it may return
+// crossed with the days it covers. Reads over unbounded or long ranges are indexed under allDays.
+func readScopes(filter *dtos.SensorDataFilter) []string {
+	if filter == nil {
+		return []string{scope(allTypes, allDays)}
+	}
+
+	types := []string{allTypes}
+	days := []string{allDays}
+
+	if filter.SensorType != nil || len(filter.SensorTypes) > 0 {
+		types = append([]string(nil), filter.SensorTypes...)
+		if filter.SensorType != nil {
+			types = append(types, *filter.SensorType)
+		}
+		types = sortedSet(types)
+	}
+
+	if filter.FromTime != nil && filter.ToTime != nil && !filter.ToTime.Before(*filter.FromTime) {
+		first := filter.FromTime.UTC().Truncate(24 * time.Hour)
+		last := filter.ToTime.UTC().Truncate(24 * time.Hour)
+		if last.Sub(first) < maxIndexDays*24*time.Hour {
+			days = days[:0]
+			for day := first; !day.After(last); day = day.Add(24 * time.Hour) {
+				days = append(days, dayOf(day))
+			}
+		}
+	}
+
+	scopes := make([]string, 0, len(types)*len(days))
+	for _, sensorType := range types {
+		for _, day := range days {
+			scopes = append(scopes, scope(sensorType, day))
+		}
+	}
+	return scopes
+}
+
+// writeScopes returns the scopes whose cached reads may include any of the readings
+func writeScopes(readings []*entities.SensorData) []string {
+	seen := map[string]bool{scope(allTypes, allDays): true}
+	for _, reading := range readings {
+		seen[scope(reading.SensorType, allDays)] = true
+		// The database stores whole seconds, which may round the timestamp into the next day
+		for _, t := range []time.Time{reading.Timestamp, reading.Timestamp.Round(time.Second)} {
+			seen[scope(reading.SensorType, dayOf(t))] = true
+			seen[scope(allTypes, dayOf(t))] = true
+		}
+	}
+
+	scopes := make([]string, 0, len(seen))
+	for s := range seen {
+		scopes = append(scopes, s)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sensor_cache_requests_total",
+		Help: "Cached sensor data reads by operation and result (hit, miss, or error when Redis failed and the database was read directly).",
+	}, []string{"operation", "result"})
+	invalidationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sensor_cache_invalidations_total",
+		Help: "Cache invalidations by scope (readings for the ranges of written readings, all for the whole cache) and result.",
+	}, []string{"scope", "result"})
+)
+
+// recordRequest counts a cached read
+func recordRequest(operation, result string) {
+	requestsTotal.WithLabelValues(operation, result).Inc()
+}
+
+// recordInvalidation counts an invalidation as succeeded or failed
+func recordInvalidation(scope string, err error) {
+	result := "succeeded"
+	if err != nil {
+		result = "failed"
+	}
+	invalidationsTotal.WithLabelValues(scope, result).Inc()
+}
@@ -0,0 +1,453 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// Cached operations, as labelled in metrics
+const (
+	operationGet       = "get"
+	operationList      = "list"
+	operationAggregate = "aggregate"
+)
+
+// errInvalidated aborts storing a read whose scopes were invalidated while it loaded
+var errInvalidated = errors.New("cache invalidated during read")
+
+// Config holds query cache settings
+type Config struct {
+	TTL     time.Duration // Lifetime of cached reads
+	Timeout time.Duration // Timeout of each Redis round trip before falling back to the database
+}
+
+// Service is a sensor service decorator that caches GetSensorData, ListSensorData and
+// AggregateSensorData in Redis, keyed on their normalized parameters. Lists and aggregations are
+// indexed by the sensor types and days they cover, so a write invalidates only the cached reads
+// that may include the readings it changes; deletes and restores by filter invalidate the whole
+// cache. Every invalidation bumps the versions of the scopes it covers, and a read only stores
+// what it loaded if the versions of its scopes are unchanged, so a value loaded before a concurrent
+// write is not cached. When Redis fails, reads go to the database directly.
+type Service struct {
+	interfaces.SensorServiceInterface
+	client *redis.Client
+	cfg    Config
+}
+
+// NewService wraps next with a Redis query cache
+func NewService(next interfaces.SensorServiceInterface, client *redis.Client, cfg Config) *Service {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 100 * time.Millisecond
+	}
+	return &Service{
+		SensorServiceInterface: next,
+		client:                 client,
+		cfg:                    cfg,
+	}
+}
+
+// cachedPage is the cached form of a page of sensor data
+type cachedPage struct {
+	Data       []*entities.SensorData `json:"data"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	Total      int64                  `json:"total"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+// GetSensorData returns a reading, from the cache if possible
+func (s *Service) GetSensorData(ctx context.Context, id uint) (*entities.SensorData, error) {
+	data := &entities.SensorData{}
+	err := s.read(ctx, operationGet, readingName(id), []string{readingScope(id)}, data, func() error {
+		loaded, err := s.SensorServiceInterface.GetSensorData(ctx, id)
+		if err != nil {
+			return err
+		}
+		*data = *loaded
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ListSensorData returns a page of sensor data, from the cache if possible
+func (s *Service) ListSensorData(ctx context.Context, filter *dtos.SensorDataFilter, pagination *dtos.PaginationParams) (*dtos.PaginatedResponse, error) {
+	name, err := queryName(operationList, normalizeFilter(filter), normalizePagination(pagination))
+	if err != nil {
+		return s.SensorServiceInterface.ListSensorData(ctx, filter, pagination)
+	}
+
+	var page cachedPage
+	err = s.read(ctx, operationList, name, readScopes(filter), &page, func() error {
+		result, err := s.SensorServiceInterface.ListSensorData(ctx, filter, pagination)
+		if err != nil {
+			return err
+		}
+		data, _ := result.Data.([]*entities.SensorData)
+		page = cachedPage{
+			Data:       data,
+			Page:       result.Page,
+			PageSize:   result.PageSize,
+			Total:      result.Total,
+			TotalPages: result.TotalPages,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.PaginatedResponse{
+		Data:       page.Data,
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		Total:      page.Total,
+		TotalPages: page.TotalPages,
+	}, nil
+}
+
+// AggregateSensorData returns time-bucketed statistics, from the cache if possible
+func (s *Service) AggregateSensorData(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.AggregateParams) ([]*dtos.AggregateBucket, error) {
+	name, err := queryName(operationAggregate, normalizeFilter(filter), params)
+	if err != nil {
+		return s.SensorServiceInterface.AggregateSensorData(ctx, filter, params)
+	}
+
+	var buckets []*dtos.AggregateBucket
+	err = s.read(ctx, operationAggregate, name, readScopes(filter), &buckets, func() error {
+		var err error
+		buckets, err = s.SensorServiceInterface.AggregateSensorData(ctx, filter, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// CreateSensorData stores a reading and invalidates the cached reads covering it
+func (s *Service) CreateSensorData(ctx context.Context, data *entities.SensorData) error {
+	if err := s.SensorServiceInterface.CreateSensorData(ctx, data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, nil, []*entities.SensorData{data})
+	return nil
+}
+
+// CreateSensorDataBatch stores readings and invalidates the cached reads covering them
+func (s *Service) CreateSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*dtos.BatchResult, error) {
+	result, err := s.SensorServiceInterface.CreateSensorDataBatch(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, nil, data)
+	return result, nil
+}
+
+// UpdateSensorData updates a reading and invalidates the cached reads covering its old and new values
+func (s *Service) UpdateSensorData(ctx context.Context, id uint, data *entities.SensorData) error {
+	readings := []*entities.SensorData{data}
+	if old, err := s.SensorServiceInterface.GetSensorData(ctx, id); err == nil {
+		readings = append(readings, old)
+	}
+	if err := s.SensorServiceInterface.UpdateSensorData(ctx, id, data); err != nil {
+		return err
+	}
+	s.invalidate(ctx, []uint{id}, readings)
+	return nil
+}
+
+// DeleteSensorDataByID deletes a reading and invalidates the cached reads covering it
+func (s *Service) DeleteSensorDataByID(ctx context.Context, id uint) error {
+	old, _ := s.SensorServiceInterface.GetSensorData(ctx, id)
+	if err := s.SensorServiceInterface.DeleteSensorDataByID(ctx, id); err != nil {
+		return err
+	}
+	if old == nil {
+		s.InvalidateAll(ctx)
+		return nil
+	}
+	s.invalidate(ctx, []uint{id}, []*entities.SensorData{old})
+	return nil
+}
+
+// RevertSensorData reverts a reading and invalidates the cached reads covering its old and new values
+func (s *Service) RevertSensorData(ctx context.Context, id, historyID uint) (*entities.SensorData, error) {
+	old, _ := s.SensorServiceInterface.GetSensorData(ctx, id)
+	data, err := s.SensorServiceInterface.RevertSensorData(ctx, id, historyID)
+	if err != nil {
+		return nil, err
+	}
+	readings := []*entities.SensorData{data}
+	if old != nil {
+		readings = append(readings, old)
+	}
+	s.invalidate(ctx, []uint{id}, readings)
+	return data, nil
+}
+
+// RestoreSensorData restores a reading and invalidates the cached reads covering it
+func (s *Service) RestoreSensorData(ctx context.Context, id uint) error {
+	if err := s.SensorServiceInterface.RestoreSensorData(ctx, id); err != nil {
+		return err
+	}
+	restored, err := s.SensorServiceInterface.GetSensorData(ctx, id)
+	if err != nil {
+		s.InvalidateAll(ctx)
+		return nil
+	}
+	s.invalidate(ctx, []uint{id}, []*entities.SensorData{restored})
+	return nil
+}
+
+// DeleteSensorData deletes the matching readings and invalidates the whole cache
+func (s *Service) DeleteSensorData(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error) {
+	deleted, err := s.SensorServiceInterface.DeleteSensorData(ctx, filter)
+	if deleted > 0 {
+		s.InvalidateAll(ctx)
+	}
+	return deleted, err
+}
+
+// DeleteSensorDataInChunks deletes the matching readings and invalidates the whole cache
+func (s *Service) DeleteSensorDataInChunks(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int, progress func(deleted int64)) (int64, error) {
+	deleted, err := s.SensorServiceInterface.DeleteSensorDataInChunks(ctx, filter, chunkSize, progress)
+	if deleted > 0 {
+		s.InvalidateAll(ctx)
+	}
+	return deleted, err
+}
+
+// RestoreSensorDataByFilter restores the matching readings and invalidates the whole cache
+func (s *Service) RestoreSensorDataByFilter(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int) (int64, error) {
+	restored, err := s.SensorServiceInterface.RestoreSensorDataByFilter(ctx, filter, chunkSize)
+	if restored > 0 {
+		s.InvalidateAll(ctx)
+	}
+	return restored, err
+}
+
+// read answers a read from the cache into dest, or calls load to fill dest and caches the result
+// indexed under scopes
+func (s *Service) read(ctx context.Context, operation, name string, scopes []string, dest interface{}, load func() error) error {
+	state, hit := s.lookup(ctx, operation, name, scopes, dest)
+	if hit {
+		return nil
+	}
+	if err := load(); err != nil {
+		return err
+	}
+	if state != nil {
+		s.store(ctx, state, name, dest, scopes)
+	}
+	return nil
+}
+
+// readState is the cache generation and the versions of a read's scopes when the read started
+type readState struct {
+	generation int64
+	keys       []string      // The generation key followed by the version keys of the scopes
+	values     []interface{} // Their values, nil for keys not set
+}
+
+// unchanged reports whether values, read from the keys of the state, are the ones it started with
+func (r *readState) unchanged(values []interface{}) bool {
+	if len(values) != len(r.values) {
+		return false
+	}
+	for i := range values {
+		if values[i] != r.values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup reads the cached value of name into dest. It returns the state to store a loaded value
+// with, nil if Redis failed.
+func (s *Service) lookup(ctx context.Context, operation, name string, scopes []string, dest interface{}) (*readState, bool) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	state, err := s.readState(ctx, scopes)
+	if err != nil {
+		recordRequest(operation, "error")
+		return nil, false
+	}
+
+	payload, err := s.client.Get(ctx, entryKey(state.generation, name)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		recordRequest(operation, "miss")
+		return state, false
+	}
+	if err != nil {
+		recordRequest(operation, "error")
+		return nil, false
+	}
+	if err := json.Unmarshal(payload, dest); err != nil {
+		recordRequest(operation, "error")
+		return state, false
+	}
+
+	recordRequest(operation, "hit")
+	return state, true
+}
+
+// readState reads the cache generation and the versions of scopes
+func (s *Service) readState(ctx context.Context, scopes []string) (*readState, error) {
+	keys := make([]string, 0, len(scopes)+1)
+	keys = append(keys, generationKey)
+	for _, scope := range scopes {
+		keys = append(keys, versionKey(scope))
+	}
+
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	generation, err := parseGeneration(values[0])
+	if err != nil {
+		return nil, err
+	}
+	return &readState{generation: generation, keys: keys, values: values}, nil
+}
+
+// store caches value under name and adds it to the index of each scope. Nothing is stored if the
+// cache or any of the scopes was invalidated since state was read, as value may predate the write
+// behind the invalidation. Keys of the state are watched, so an invalidation racing with the
+// store aborts it.
+func (s *Service) store(ctx context.Context, state *readState, name string, value interface{}, scopes []string) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := utils.DetachedContext(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	key := entryKey(state.generation, name)
+	err = s.client.Watch(ctx, func(tx *redis.Tx) error {
+		values, err := tx.MGet(ctx, state.keys...).Result()
+		if err != nil {
+			return err
+		}
+		if !state.unchanged(values) {
+			return errInvalidated
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, payload, s.cfg.TTL)
+			for _, scope := range scopes {
+				index := indexKey(state.generation, scope)
+				pipe.SAdd(ctx, index, key)
+				pipe.Expire(ctx, index, s.cfg.TTL)
+			}
+			return nil
+		})
+		return err
+	}, state.keys...)
+	if errors.Is(err, errInvalidated) || errors.Is(err, redis.TxFailedErr) {
+		return
+	}
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Failed to cache sensor data %s: %v", name, err))
+	}
+}
+
+// invalidate removes the cached reads of the readings ids and the lists and aggregations that may
+// include any of readings
+func (s *Service) invalidate(ctx context.Context, ids []uint, readings []*entities.SensorData) {
+	ctx, cancel := utils.DetachedContext(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	err := s.invalidateReadings(ctx, ids, readings)
+	recordInvalidation("readings", err)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Failed to invalidate cached sensor data: %v", err))
+	}
+}
+
+func (s *Service) invalidateReadings(ctx context.Context, ids []uint, readings []*entities.SensorData) error {
+	generation, err := s.generation(ctx)
+	if err != nil {
+		return err
+	}
+
+	scopes := writeScopes(readings)
+	for _, id := range ids {
+		scopes = append(scopes, readingScope(id))
+	}
+
+	// Bump the versions before removing the entries: a read storing afterwards sees the new versions,
+	// and one that stored before is found in the indexes
+	pipe := s.client.Pipeline()
+	for _, scope := range scopes {
+		pipe.Incr(ctx, versionKey(scope))
+		pipe.Expire(ctx, versionKey(scope), s.cfg.TTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	indexes := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		indexes = append(indexes, indexKey(generation, scope))
+	}
+	keys, err := s.client.SUnion(ctx, indexes...).Result()
+	if err != nil {
+		return err
+	}
+
+	keys = append(keys, indexes...)
+	for _, id := range ids {
+		keys = append(keys, entryKey(generation, readingName(id)))
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+// InvalidateAll starts a new cache generation, so no cached read is found any more. It is called
+// for changes made without the sensor service, such as retention purges and rollups.
+func (s *Service) InvalidateAll(ctx context.Context) {
+	ctx, cancel := utils.DetachedContext(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	err := s.client.Incr(ctx, generationKey).Err()
+	recordInvalidation("all", err)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Failed to invalidate the sensor data cache: %v", err))
+	}
+}
+
+// generation returns the current cache generation
+func (s *Service) generation(ctx context.Context) (int64, error) {
+	generation, err := s.client.Get(ctx, generationKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return generation, err
+}
+
+// parseGeneration converts the value of generationKey read with MGET, nil before the first invalidation
+func parseGeneration(value interface{}) (int64, error) {
+	if value == nil {
+		return 0, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected cache generation %v", value)
+	}
+	return strconv.ParseInt(text, 10, 64)
+}
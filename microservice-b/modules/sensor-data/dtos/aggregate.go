@@ -0,0 +1,93 @@
+package dtos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregation group by fields
+const (
+	GroupBySensorType = "sensor_type"
+	GroupByID1        = "id1"
+	GroupByID2        = "id2"
+)
+
+// Aggregation limits
+const (
+	MinAggregateBucket  = time.Second
+	MaxAggregateBuckets = 10000 // Maximum rows returned by one aggregation
+)
+
+// AggregateParams represents time bucketing and grouping for an aggregation
+type AggregateParams struct {
+	Bucket  time.Duration
+	GroupBy []string
+}
+
+// AggregateBucket represents the statistics of one time bucket and group
+type AggregateBucket struct {
+	BucketStart time.Time `json:"bucket_start"`
+	SensorType  *string   `json:"sensor_type,omitempty"`
+	ID1         *string   `json:"id1,omitempty"`
+	ID2         *int32    `json:"id2,omitempty"`
+	Count       int64     `json:"count"`
+	Min         float64   `json:"min"`
+	Max         float64   `json:"max"`
+	Avg         float64   `json:"avg"`
+	Sum         float64   `json:"sum"`
+	First       float64   `json:"first"`
+	Last        float64   `json:"last"`
+}
+
+// AggregateResponse represents the result of an aggregation
+type AggregateResponse struct {
+	Bucket  string             `json:"bucket"`
+	GroupBy []string           `json:"group_by"`
+	Buckets []*AggregateBucket `json:"buckets"`
+}
+
+// ParseBucket parses a bucket size such as 1m, 5m, 1h, 1d or any Go duration of whole seconds
+func ParseBucket(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var bucket time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bucket %q", value)
+		}
+		bucket = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bucket %q", value)
+		}
+		bucket = parsed
+	}
+
+	if bucket < MinAggregateBucket || bucket%time.Second != 0 {
+		return 0, fmt.Errorf("bucket must be a whole number of seconds and at least %s", MinAggregateBucket)
+	}
+	return bucket, nil
+}
+
+// ParseGroupBy parses a comma separated list of group by fields
+func ParseGroupBy(value string) ([]string, error) {
+	var groupBy []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		switch field {
+		case GroupBySensorType, GroupByID1, GroupByID2:
+		default:
+			return nil, fmt.Errorf("cannot group by %q, expected sensor_type, id1 or id2", field)
+		}
+		seen[field] = true
+		groupBy = append(groupBy, field)
+	}
+	return groupBy, nil
+}
@@ -0,0 +1,66 @@
+package dtos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Cursor list orders
+const (
+	CursorOrderAsc  = "asc"
+	CursorOrderDesc = "desc"
+)
+
+// ErrInvalidCursor is returned for cursors that were not issued by the server
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a list ordered by (timestamp, id). Pages continue after the position,
+// or end right before it when Before is set, in the order the cursor was issued for.
+type Cursor struct {
+	Timestamp time.Time `json:"t"`
+	ID        uint      `json:"id"`
+	Order     string    `json:"o"`
+	Before    bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor
+func (c Cursor) Encode() string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor parses a cursor returned by Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Order != CursorOrderAsc && cursor.Order != CursorOrderDesc {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// CursorParams represents cursor pagination parameters. Order is only used for the first page,
+// later pages keep the order of their cursor.
+type CursorParams struct {
+	Cursor    *Cursor
+	PageSize  int
+	Order     string
+	WithTotal bool
+}
+
+// CursorPage represents a page of a cursor paginated list
+type CursorPage struct {
+	Data       interface{} `json:"data"`
+	PageSize   int         `json:"page_size"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+	Total      *int64      `json:"total,omitempty"`
+}
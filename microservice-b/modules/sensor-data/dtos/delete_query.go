@@ -0,0 +1,31 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+)
+
+// DeleteQuerySampleSize is the number of matching readings shown in a delete preview
+const DeleteQuerySampleSize = 10
+
+// DeleteQueryRequest selects the readings to delete. Without a confirmation token the request is a
+// dry run; the token returned by it executes the delete for the same filter.
+type DeleteQueryRequest struct {
+	Filter            SensorDataFilter `json:"filter"`
+	ConfirmationToken string           `json:"confirmation_token,omitempty"`
+}
+
+// DeleteQueryPreview represents the dry run of a delete query
+type DeleteQueryPreview struct {
+	Count             int64                  `json:"count"`
+	Sample            []*entities.SensorData `json:"sample"`
+	ConfirmationToken string                 `json:"confirmation_token"`
+	ExpiresAt         time.Time              `json:"expires_at"`
+}
+
+// DeleteQueryProgress represents the progress and result of a delete query
+type DeleteQueryProgress struct {
+	Deleted int64 `json:"deleted"`
+	Total   int64 `json:"total"`
+}
@@ -0,0 +1,4 @@
+package dtos
+
+// ExportBatchSize is the number of readings read from the database per export batch
+const ExportBatchSize = 1000
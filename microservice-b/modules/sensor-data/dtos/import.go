@@ -0,0 +1,47 @@
+package dtos
+
+// MaxImportIssues is the number of rejected and duplicate rows listed in an import report
+const MaxImportIssues = 1000
+
+// ImportIssue is a row of an import that was not stored
+type ImportIssue struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// ImportCounts represents the progress of an import
+type ImportCounts struct {
+	BytesRead  int64 `json:"bytes_read"`
+	TotalBytes int64 `json:"total_bytes,omitempty"`
+	Rows       int   `json:"rows"`
+	Accepted   int   `json:"accepted"`
+	Duplicates int   `json:"duplicates"`
+	Rejected   int   `json:"rejected"`
+}
+
+// ImportReport represents the outcome of an import. For a dry run, accepted rows are the ones that would be stored.
+type ImportReport struct {
+	DryRun bool   `json:"dry_run"`
+	Format string `json:"format"`
+	ImportCounts
+	Issues          []ImportIssue `json:"issues"`
+	IssuesTruncated bool          `json:"issues_truncated,omitempty"`
+}
+
+// AddIssue records a row that was not stored, listing at most MaxImportIssues of them
+func (r *ImportReport) AddIssue(issue ImportIssue) {
+	r.Rows++
+	switch issue.Status {
+	case BatchItemDuplicate:
+		r.Duplicates++
+	default:
+		r.Rejected++
+	}
+
+	if len(r.Issues) >= MaxImportIssues {
+		r.IssuesTruncated = true
+		return
+	}
+	r.Issues = append(r.Issues, issue)
+}
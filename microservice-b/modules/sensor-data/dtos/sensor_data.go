@@ -1,16 +1,49 @@
 package dtos
 
-import "time"
+import (
+	"fmt"
+	"time"
 
-// SensorDataFilter represents filter criteria for sensor data queries
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/query"
+)
+
+// SensorDataFilter represents filter criteria for sensor data queries.
+// All criteria must match; Expression is a filter expression as accepted by query.Compile.
 type SensorDataFilter struct {
-	SensorType *string    `json:"sensor_type,omitempty"`
-	ID1        *string    `json:"id1,omitempty"`
-	ID2        *int32     `json:"id2,omitempty"`
-	FromTime   *time.Time `json:"from_time,omitempty"`
-	ToTime     *time.Time `json:"to_time,omitempty"`
-	MinValue   *float64   `json:"min_value,omitempty"`
-	MaxValue   *float64   `json:"max_value,omitempty"`
+	SensorType  *string    `json:"sensor_type,omitempty"`
+	SensorTypes []string   `json:"sensor_types,omitempty"`
+	ID1         *string    `json:"id1,omitempty"`
+	ID1s        []string   `json:"id1s,omitempty"`
+	ID2         *int32     `json:"id2,omitempty"`
+	FromTime    *time.Time `json:"from_time,omitempty"`
+	ToTime      *time.Time `json:"to_time,omitempty"`
+	MinValue    *float64   `json:"min_value,omitempty"`
+	MaxValue    *float64   `json:"max_value,omitempty"`
+	Expression  string     `json:"q,omitempty" example:"sensor_type in (temperature,humidity) and value > 30"`
+}
+
+// Validate checks the value lists and the filter expression
+func (f *SensorDataFilter) Validate() error {
+	if len(f.SensorTypes) > query.MaxInValues || len(f.ID1s) > query.MaxInValues {
+		return fmt.Errorf("%w: filters are limited to %d values per field", query.ErrInvalidQuery, query.MaxInValues)
+	}
+	if f.Expression != "" {
+		if _, err := query.Compile(f.Expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEmpty reports whether the filter matches every reading
+func (f *SensorDataFilter) IsEmpty() bool {
+	return f.SensorType == nil && len(f.SensorTypes) == 0 && f.ID1 == nil && len(f.ID1s) == 0 && f.ID2 == nil &&
+		f.FromTime == nil && f.ToTime == nil && f.MinValue == nil && f.MaxValue == nil && f.Expression == ""
+}
+
+// HasValueCriteria reports whether the filter needs individual readings rather than rollups
+func (f *SensorDataFilter) HasValueCriteria() bool {
+	return f.MinValue != nil || f.MaxValue != nil || f.Expression != ""
 }
 
 // PaginationParams represents pagination parameters
@@ -29,3 +62,53 @@ type PaginatedResponse struct {
 	Total      int64       `json:"total"`
 	TotalPages int         `json:"total_pages"`
 }
+
+// Batch item outcomes
+const (
+	BatchItemAccepted  = "accepted"
+	BatchItemDuplicate = "duplicate"
+	BatchItemRejected  = "rejected"
+)
+
+// BatchItemResult represents the outcome of a single item in a batch
+type BatchItemResult struct {
+	Index     int    `json:"index"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	Retryable bool   `json:"retryable,omitempty"`
+}
+
+// BatchResult represents per-item outcomes of a batch ingestion
+type BatchResult struct {
+	Items      []BatchItemResult `json:"items"`
+	Accepted   int               `json:"accepted"`
+	Duplicates int               `json:"duplicates"`
+	Rejected   int               `json:"rejected"`
+}
+
+// Add records an item outcome and updates the counters
+func (r *BatchResult) Add(item BatchItemResult) {
+	r.Items = append(r.Items, item)
+	switch item.Status {
+	case BatchItemAccepted:
+		r.Accepted++
+	case BatchItemDuplicate:
+		r.Duplicates++
+	case BatchItemRejected:
+		r.Rejected++
+	}
+}
+
+// IngestSensorDataRequest documents the JSON form of a SensorService.SendSensorData message
+type IngestSensorDataRequest struct {
+	SensorValue float64   `json:"sensor_value" example:"23.5"`
+	SensorType  string    `json:"sensor_type" example:"temperature"`
+	ID1         string    `json:"id1" example:"A1B2C3D4"`
+	ID2         int32     `json:"id2" example:"42"`
+	Timestamp   time.Time `json:"timestamp" example:"2024-01-01T00:00:00Z"`
+}
+
+// IngestBatchRequest documents the JSON form of a SensorService.SendSensorDataBatch message
+type IngestBatchRequest struct {
+	Data []IngestSensorDataRequest `json:"data"`
+}
@@ -0,0 +1,23 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+)
+
+// DeletedSensorData represents a soft-deleted reading in the trash
+type DeletedSensorData struct {
+	entities.SensorData
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashRequest selects readings in the trash; an empty filter selects the whole trash
+type TrashRequest struct {
+	Filter SensorDataFilter `json:"filter"`
+}
+
+// TrashResult represents the number of readings restored or purged
+type TrashResult struct {
+	Count int64 `json:"count"`
+}
@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+// Sensor data history actions
+const (
+	HistoryActionUpdate = "update"
+	HistoryActionDelete = "delete"
+	HistoryActionRevert = "revert"
+)
+
+// SensorDataValues holds the editable values of a reading
+type SensorDataValues struct {
+	SensorValue float64   `json:"sensor_value" gorm:"type:decimal(10,4)"`
+	SensorType  string    `json:"sensor_type" gorm:"type:varchar(50)"`
+	Timestamp   time.Time `json:"timestamp" gorm:"type:timestamp"`
+}
+
+// ValuesOf returns the editable values of data
+func ValuesOf(data *SensorData) *SensorDataValues {
+	return &SensorDataValues{
+		SensorValue: data.SensorValue,
+		SensorType:  data.SensorType,
+		Timestamp:   data.Timestamp,
+	}
+}
+
+// ChangeActor identifies who made a change and in which request
+type ChangeActor struct {
+	ActorID    uint   `json:"actor_id" gorm:"index"`
+	ActorEmail string `json:"actor_email,omitempty" gorm:"type:varchar(255)"`
+	RequestID  string `json:"request_id,omitempty" gorm:"type:varchar(64)"`
+}
+
+// SensorDataHistory records one update, delete or revert of a reading with its values before and
+// after the change. New is empty for deletes.
+type SensorDataHistory struct {
+	ID           uint              `json:"id" gorm:"primaryKey;autoIncrement"`
+	SensorDataID uint              `json:"sensor_data_id" gorm:"not null;index"`
+	Action       string            `json:"action" gorm:"type:varchar(20);not null"`
+	Old          SensorDataValues  `json:"old" gorm:"embedded;embeddedPrefix:old_"`
+	New          *SensorDataValues `json:"new,omitempty" gorm:"embedded;embeddedPrefix:new_"`
+	ChangeActor
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// TableName sets the table name for GORM
+func (SensorDataHistory) TableName() string {
+	return "sensor_data_history"
+}
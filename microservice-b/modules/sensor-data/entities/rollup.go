@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// SensorRollup holds pre-aggregated statistics of one bucket, sensor type and ID combination.
+// The same structure is stored in one table per resolution.
+type SensorRollup struct {
+	BucketStart time.Time `json:"bucket_start" gorm:"type:timestamp;primaryKey;autoIncrement:false"`
+	SensorType  string    `json:"sensor_type" gorm:"type:varchar(50);primaryKey"`
+	ID1         string    `json:"id1" gorm:"type:varchar(50);primaryKey"`
+	ID2         int32     `json:"id2" gorm:"primaryKey;autoIncrement:false"`
+	SampleCount int64     `json:"sample_count" gorm:"not null"`
+	MinValue    float64   `json:"min_value" gorm:"not null"`
+	MaxValue    float64   `json:"max_value" gorm:"not null"`
+	SumValue    float64   `json:"sum_value" gorm:"not null"`
+	FirstValue  float64   `json:"first_value" gorm:"not null"`
+	FirstAt     time.Time `json:"first_at" gorm:"type:timestamp;not null"`
+	LastValue   float64   `json:"last_value" gorm:"not null"`
+	LastAt      time.Time `json:"last_at" gorm:"type:timestamp;not null"`
+}
+
+// RollupWatermark records up to which modification time raw sensor data has been rolled up
+type RollupWatermark struct {
+	Name      string    `json:"name" gorm:"type:varchar(50);primaryKey"`
+	Watermark time.Time `json:"watermark" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
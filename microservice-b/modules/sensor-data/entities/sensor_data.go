@@ -1,9 +1,14 @@
 package entities
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/shared/constants"
 )
 
 // SensorData represents the sensor data entity
@@ -15,7 +20,7 @@ type SensorData struct {
 	ID2         int32          `json:"id2" gorm:"not null;index:idx_id_combination"`
 	Timestamp   time.Time      `json:"timestamp" gorm:"type:timestamp;not null;index"`
 	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime;index"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
@@ -23,3 +28,66 @@ type SensorData struct {
 func (SensorData) TableName() string {
 	return "sensor_data"
 }
+
+const (
+	maxIdentifierLength = 50
+	maxAbsSensorValue   = 1e6 // decimal(10,4)
+	maxClockSkew        = 5 * time.Minute
+)
+
+// valueRange is the physically possible range of a sensor type's values
+type valueRange struct {
+	Min, Max float64
+	Binary   bool // Only Min or Max
+}
+
+// sensorTypeRanges holds the value rules of the known sensor types, other sensor types accept any value
+var sensorTypeRanges = map[string]valueRange{
+	constants.SensorTypeTemperature: {Min: -273.15, Max: maxAbsSensorValue}, // Degrees Celsius
+	constants.SensorTypeHumidity:    {Min: 0, Max: 100},                     // Percent
+	constants.SensorTypePressure:    {Min: 0, Max: maxAbsSensorValue},       // hPa
+	constants.SensorTypeLight:       {Min: 0, Max: maxAbsSensorValue},       // Lux
+	constants.SensorTypeMotion:      {Min: 0, Max: 1, Binary: true},
+}
+
+// Validate checks that the reading can be stored, now bounds how far in the future timestamps may be
+func (d *SensorData) Validate(now time.Time) error {
+	if d.SensorType == "" {
+		return errors.New("sensor_type is required")
+	}
+	if len(d.SensorType) > maxIdentifierLength {
+		return fmt.Errorf("sensor_type must be at most %d characters", maxIdentifierLength)
+	}
+	if d.ID1 == "" {
+		return errors.New("id1 is required")
+	}
+	if len(d.ID1) > maxIdentifierLength {
+		return fmt.Errorf("id1 must be at most %d characters", maxIdentifierLength)
+	}
+	if math.IsNaN(d.SensorValue) || math.IsInf(d.SensorValue, 0) {
+		return errors.New("sensor_value must be a finite number")
+	}
+	if math.Abs(d.SensorValue) >= maxAbsSensorValue {
+		return fmt.Errorf("sensor_value must be between %g and %g", -maxAbsSensorValue, maxAbsSensorValue)
+	}
+	if rule, ok := sensorTypeRanges[d.SensorType]; ok {
+		switch {
+		case rule.Binary && d.SensorValue != rule.Min && d.SensorValue != rule.Max:
+			return fmt.Errorf("%s sensor_value must be %g or %g", d.SensorType, rule.Min, rule.Max)
+		case d.SensorValue < rule.Min || d.SensorValue > rule.Max:
+			return fmt.Errorf("%s sensor_value must be between %g and %g", d.SensorType, rule.Min, rule.Max)
+		}
+	}
+	if d.Timestamp.Unix() <= 0 {
+		return errors.New("timestamp is required")
+	}
+	if d.Timestamp.After(now.Add(maxClockSkew)) {
+		return errors.New("timestamp is in the future")
+	}
+	return nil
+}
+
+// DedupKey identifies a reading by sensor type, IDs and timestamp at the stored (second) precision
+func (d *SensorData) DedupKey() string {
+	return fmt.Sprintf("%s|%s|%d|%d", d.SensorType, d.ID1, d.ID2, d.Timestamp.Round(time.Second).Unix())
+}
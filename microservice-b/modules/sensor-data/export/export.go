@@ -0,0 +1,197 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+)
+
+// Format is an export file format
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+}
+
+// Supported export formats, the first one is the default
+var Formats = []Format{
+	{Name: "csv", ContentType: "text/csv", Extension: "csv"},
+	{Name: "ndjson", ContentType: "application/x-ndjson", Extension: "ndjson"},
+	{Name: "parquet", ContentType: "application/vnd.apache.parquet", Extension: "parquet"},
+}
+
+// rowsPerRowGroup bounds the rows a Parquet writer buffers before writing them out
+const rowsPerRowGroup = 10000
+
+// ParseFormat returns the format with the given name
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(format.Name, name) {
+			return format, nil
+		}
+	}
+	return Format{}, fmt.Errorf("unsupported format %q, use csv, ndjson or parquet", name)
+}
+
+// NegotiateFormat picks the first format listed in an Accept header, the default format if none is
+func NegotiateFormat(accept string) Format {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		for _, format := range Formats {
+			if mediaType == format.ContentType {
+				return format
+			}
+		}
+	}
+	return Formats[0]
+}
+
+// Writer encodes readings to an export file
+type Writer interface {
+	// Write encodes a batch of readings
+	Write(data []*entities.SensorData) error
+	// Close writes any buffered data and trailer, it does not close the underlying writer
+	Close() error
+}
+
+// NewWriter creates a writer encoding format to w
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format.Name {
+	case "csv":
+		return newCSVWriter(w)
+	case "ndjson":
+		return newNDJSONWriter(w), nil
+	case "parquet":
+		schema := parquet.NewSchema("sensor_data", parquet.SchemaOf(parquetRow{}))
+		return &parquetWriter{writer: parquet.NewGenericWriter[parquetRow](w, schema, parquet.MaxRowsPerRowGroup(rowsPerRowGroup))}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format.Name)
+	}
+}
+
+// columns are the exported fields, in CSV column order
+var columns = []string{"id", "sensor_type", "id1", "id2", "sensor_value", "timestamp"}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (w *csvWriter) Write(data []*entities.SensorData) error {
+	for _, item := range data {
+		record := []string{
+			strconv.FormatUint(uint64(item.ID), 10),
+			item.SensorType,
+			item.ID1,
+			strconv.FormatInt(int64(item.ID2), 10),
+			strconv.FormatFloat(item.SensorValue, 'f', -1, 64),
+			item.Timestamp.UTC().Format(time.RFC3339),
+		}
+		if err := w.writer.Write(record); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// ndjsonRow is an exported reading, one JSON object per line
+type ndjsonRow struct {
+	ID          uint      `json:"id"`
+	SensorType  string    `json:"sensor_type"`
+	ID1         string    `json:"id1"`
+	ID2         int32     `json:"id2"`
+	SensorValue float64   `json:"sensor_value"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buffer := bufio.NewWriter(w)
+	return &ndjsonWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (w *ndjsonWriter) Write(data []*entities.SensorData) error {
+	for _, item := range data {
+		row := ndjsonRow{
+			ID:          item.ID,
+			SensorType:  item.SensorType,
+			ID1:         item.ID1,
+			ID2:         item.ID2,
+			SensorValue: item.SensorValue,
+			Timestamp:   item.Timestamp.UTC(),
+		}
+		if err := w.encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return w.buffer.Flush()
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.buffer.Flush()
+}
+
+// parquetRow is the Parquet schema of an exported reading
+type parquetRow struct {
+	ID          uint64    `parquet:"id"`
+	SensorType  string    `parquet:"sensor_type,dict"`
+	ID1         string    `parquet:"id1,dict"`
+	ID2         int32     `parquet:"id2"`
+	SensorValue float64   `parquet:"sensor_value"`
+	Timestamp   time.Time `parquet:"timestamp,timestamp(millisecond)"`
+}
+
+type parquetWriter struct {
+	writer *parquet.GenericWriter[parquetRow]
+	rows   []parquetRow
+}
+
+func (w *parquetWriter) Write(data []*entities.SensorData) error {
+	w.rows = w.rows[:0]
+	for _, item := range data {
+		w.rows = append(w.rows, parquetRow{
+			ID:          uint64(item.ID),
+			SensorType:  item.SensorType,
+			ID1:         item.ID1,
+			ID2:         item.ID2,
+			SensorValue: item.SensorValue,
+			Timestamp:   item.Timestamp.UTC(),
+		})
+	}
+	_, err := w.writer.Write(w.rows)
+	return err
+}
+
+func (w *parquetWriter) Close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/query"
+	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+)
+
+// GetSensorData returns a single stored sensor data record
+func (s *sensorServer) GetSensorData(ctx context.Context, req *pb.GetSensorDataRequest) (*pb.SensorDataRecord, error) {
+	if req.Id == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	data, err := s.sensorService.GetSensorData(ctx, uint(req.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "sensor data not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := authorizeSensorRead(ctx, data.SensorType, data.ID1); err != nil {
+		return nil, err
+	}
+
+	return toSensorDataRecord(data), nil
+}
+
+// ListSensorData returns a page of sensor data matching the filter
+func (s *sensorServer) ListSensorData(ctx context.Context, req *pb.ListSensorDataRequest) (*pb.ListSensorDataResponse, error) {
+	pagination := &dtos.PaginationParams{
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+		Sort:     req.Sort,
+		Order:    req.Order,
+	}
+
+	filter := toSensorDataFilter(req.Filter)
+	if err := scopeSensorDataFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	result, err := s.sensorService.ListSensorData(ctx, filter, pagination)
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidQuery) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	data, _ := result.Data.([]*entities.SensorData)
+	records := make([]*pb.SensorDataRecord, 0, len(data))
+	for _, item := range data {
+		records = append(records, toSensorDataRecord(item))
+	}
+
+	return &pb.ListSensorDataResponse{
+		Data:       records,
+		Page:       int32(result.Page),
+		PageSize:   int32(result.PageSize),
+		Total:      result.Total,
+		TotalPages: int32(result.TotalPages),
+	}, nil
+}
+
+// GetByIDCombination streams sensor data matching an ID1 and ID2 combination
+func (s *sensorServer) GetByIDCombination(req *pb.IDCombinationRequest, stream pb.SensorService_GetByIDCombinationServer) error {
+	if req.Id1 == "" {
+		return status.Error(codes.InvalidArgument, "id1 is required")
+	}
+
+	return s.streamSensorData(stream, &dtos.SensorDataFilter{ID1: &req.Id1, ID2: &req.Id2})
+}
+
+// GetByDuration streams sensor data recorded within a time range
+func (s *sensorServer) GetByDuration(req *pb.DurationRequest, stream pb.SensorService_GetByDurationServer) error {
+	if req.FromTime == nil || req.ToTime == nil {
+		return status.Error(codes.InvalidArgument, "both from_time and to_time are required")
+	}
+
+	fromTime, toTime := req.FromTime.AsTime(), req.ToTime.AsTime()
+	return s.streamSensorData(stream, &dtos.SensorDataFilter{FromTime: &fromTime, ToTime: &toTime})
+}
+
+// recordSender is implemented by server streams returning sensor data records
+type recordSender interface {
+	Send(*pb.SensorDataRecord) error
+	Context() context.Context
+}
+
+// streamSensorData sends the readings matching filter within the caller's API key scope in keyset
+// batches, ordered by timestamp, so only one batch is held in memory at a time
+func (s *sensorServer) streamSensorData(stream recordSender, filter *dtos.SensorDataFilter) error {
+	if err := scopeSensorDataFilter(stream.Context(), filter); err != nil {
+		return err
+	}
+
+	var sendErr error
+	err := s.sensorService.ExportSensorData(stream.Context(), filter, func(batch []*entities.SensorData) error {
+		sendErr = sendSensorDataRecords(stream, batch)
+		return sendErr
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// sendSensorDataRecords writes each record to the stream, stopping early if the client goes away
+func sendSensorDataRecords(stream recordSender, data []*entities.SensorData) error {
+	for _, item := range data {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(toSensorDataRecord(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toSensorDataRecord converts a domain entity to its protobuf representation
+func toSensorDataRecord(data *entities.SensorData) *pb.SensorDataRecord {
+	return &pb.SensorDataRecord{
+		Id:          uint64(data.ID),
+		SensorValue: data.SensorValue,
+		SensorType:  data.SensorType,
+		Id1:         data.ID1,
+		Id2:         data.ID2,
+		Timestamp:   timeToTimestamp(data.Timestamp),
+		CreatedAt:   timeToTimestamp(data.CreatedAt),
+		UpdatedAt:   timeToTimestamp(data.UpdatedAt),
+	}
+}
+
+// toSensorDataFilter converts a protobuf filter to the domain filter
+func toSensorDataFilter(filter *pb.SensorDataFilter) *dtos.SensorDataFilter {
+	result := &dtos.SensorDataFilter{}
+	if filter == nil {
+		return result
+	}
+
+	if filter.SensorType != nil {
+		result.SensorType = &filter.SensorType.Value
+	}
+	if filter.Id1 != nil {
+		result.ID1 = &filter.Id1.Value
+	}
+	if filter.Id2 != nil {
+		result.ID2 = &filter.Id2.Value
+	}
+	if filter.FromTime != nil {
+		fromTime := filter.FromTime.AsTime()
+		result.FromTime = &fromTime
+	}
+	if filter.ToTime != nil {
+		toTime := filter.ToTime.AsTime()
+		result.ToTime = &toTime
+	}
+	if filter.MinValue != nil {
+		result.MinValue = &filter.MinValue.Value
+	}
+	if filter.MaxValue != nil {
+		result.MaxValue = &filter.MaxValue.Value
+	}
+
+	return result
+}
@@ -2,35 +2,69 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	authEntities "github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+	authGrpc "github.com/worlder-team/microservice-server/microservice-b/modules/auth/grpc"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pipeline"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pubsub"
 	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+	"github.com/worlder-team/microservice-server/shared/utils"
 )
 
+// MethodScopes maps each SensorService method to the API key scope it requires
+var MethodScopes = map[string]string{
+	pb.SensorService_SendSensorData_FullMethodName:      authEntities.APIKeyScopeSensorWrite,
+	pb.SensorService_SendSensorDataBatch_FullMethodName: authEntities.APIKeyScopeSensorWrite,
+	pb.SensorService_GetSensorData_FullMethodName:       authEntities.APIKeyScopeSensorRead,
+	pb.SensorService_ListSensorData_FullMethodName:      authEntities.APIKeyScopeSensorRead,
+	pb.SensorService_GetByIDCombination_FullMethodName:  authEntities.APIKeyScopeSensorRead,
+	pb.SensorService_GetByDuration_FullMethodName:       authEntities.APIKeyScopeSensorRead,
+	pb.SensorService_WatchSensorData_FullMethodName:     authEntities.APIKeyScopeSensorRead,
+}
+
+// PublicMethods lists SensorService methods that do not require an API key
+var PublicMethods = []string{
+	pb.SensorService_HealthCheck_FullMethodName,
+}
+
 type sensorServer struct {
 	pb.UnimplementedSensorServiceServer
 	sensorService interfaces.SensorServiceInterface
+	hub           *pubsub.Hub
 }
 
 // NewSensorServer creates a new gRPC sensor server
-func NewSensorServer(sensorService interfaces.SensorServiceInterface) *sensorServer {
+func NewSensorServer(sensorService interfaces.SensorServiceInterface, hub *pubsub.Hub) *sensorServer {
 	return &sensorServer{
 		sensorService: sensorService,
+		hub:           hub,
 	}
 }
 
 // RegisterSensorServer registers the sensor server with gRPC
-func RegisterSensorServer(s *grpc.Server, sensorService interfaces.SensorServiceInterface) {
-	pb.RegisterSensorServiceServer(s, NewSensorServer(sensorService))
+func RegisterSensorServer(s *grpc.Server, sensorService interfaces.SensorServiceInterface, hub *pubsub.Hub) {
+	pb.RegisterSensorServiceServer(s, NewSensorServer(sensorService, hub))
 }
 
 // SendSensorData handles single sensor data reception
 func (s *sensorServer) SendSensorData(ctx context.Context, req *pb.SensorData) (*pb.SensorResponse, error) {
+	if err := authorizeSensorData(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// Convert protobuf to domain entity
 	sensorData := &entities.SensorData{
 		SensorValue: req.SensorValue,
@@ -43,6 +77,9 @@ func (s *sensorServer) SendSensorData(ctx context.Context, req *pb.SensorData) (
 	// Save to database
 	err := s.sensorService.CreateSensorData(ctx, sensorData)
 	if err != nil {
+		if statusErr := backpressureStatus(err); statusErr != nil {
+			return nil, statusErr
+		}
 		return &pb.SensorResponse{
 			Success: false,
 			Message: "Failed to save sensor data",
@@ -56,11 +93,23 @@ func (s *sensorServer) SendSensorData(ctx context.Context, req *pb.SensorData) (
 	}, nil
 }
 
-// SendSensorDataBatch handles batch sensor data reception
-func (s *sensorServer) SendSensorDataBatch(ctx context.Context, req *pb.SensorDataBatch) (*pb.SensorResponse, error) {
-	// Convert protobuf batch to domain entities
+// SendSensorDataBatch handles batch sensor data reception and reports the outcome of every item
+func (s *sensorServer) SendSensorDataBatch(ctx context.Context, req *pb.SensorDataBatch) (*pb.SensorBatchResponse, error) {
+	results := make([]*pb.SensorItemResult, len(req.Data))
+
+	// Convert protobuf batch to domain entities, rejecting items outside the API key's scope
 	var sensorDataBatch []*entities.SensorData
-	for _, data := range req.Data {
+	var batchIndexes []int
+	for i, data := range req.Data {
+		if err := authorizeSensorData(ctx, data); err != nil {
+			results[i] = &pb.SensorItemResult{
+				Index:  int32(i),
+				Status: pb.SensorItemResult_REJECTED,
+				Reason: status.Convert(err).Message(),
+			}
+			continue
+		}
+
 		sensorData := &entities.SensorData{
 			SensorValue: data.SensorValue,
 			SensorType:  data.SensorType,
@@ -69,22 +118,72 @@ func (s *sensorServer) SendSensorDataBatch(ctx context.Context, req *pb.SensorDa
 			Timestamp:   data.Timestamp.AsTime(),
 		}
 		sensorDataBatch = append(sensorDataBatch, sensorData)
+		batchIndexes = append(batchIndexes, i)
 	}
 
 	// Save batch to database
-	err := s.sensorService.CreateSensorDataBatch(ctx, sensorDataBatch)
+	batchResult, err := s.sensorService.CreateSensorDataBatch(ctx, sensorDataBatch)
+	if statusErr := backpressureStatus(err); statusErr != nil {
+		return nil, statusErr
+	}
 	if err != nil {
-		return &pb.SensorResponse{
-			Success: false,
-			Message: "Failed to save sensor data batch",
-			Error:   err.Error(),
-		}, nil
+		for _, index := range batchIndexes {
+			results[index] = &pb.SensorItemResult{
+				Index:     int32(index),
+				Status:    pb.SensorItemResult_REJECTED,
+				Reason:    err.Error(),
+				Retryable: true,
+			}
+		}
+	} else {
+		for _, item := range batchResult.Items {
+			index := batchIndexes[item.Index]
+			results[index] = &pb.SensorItemResult{
+				Index:     int32(index),
+				Status:    toItemStatus(item.Status),
+				Reason:    item.Reason,
+				Retryable: item.Retryable,
+			}
+		}
 	}
 
-	return &pb.SensorResponse{
-		Success: true,
-		Message: "Sensor data batch saved successfully",
-	}, nil
+	response := &pb.SensorBatchResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case pb.SensorItemResult_ACCEPTED:
+			response.Accepted++
+		case pb.SensorItemResult_DUPLICATE:
+			response.Duplicates++
+		default:
+			response.Rejected++
+		}
+	}
+
+	response.Success = response.Rejected == 0
+	if response.Success {
+		response.Message = "Sensor data batch saved successfully"
+	} else {
+		response.Message = fmt.Sprintf("%d of %d items rejected", response.Rejected, len(results))
+		if err != nil {
+			response.Error = err.Error()
+		}
+	}
+
+	return response, nil
+}
+
+// toItemStatus converts a batch item outcome to its protobuf status
+func toItemStatus(itemStatus string) pb.SensorItemResult_Status {
+	switch itemStatus {
+	case dtos.BatchItemAccepted:
+		return pb.SensorItemResult_ACCEPTED
+	case dtos.BatchItemDuplicate:
+		return pb.SensorItemResult_DUPLICATE
+	case dtos.BatchItemRejected:
+		return pb.SensorItemResult_REJECTED
+	default:
+		return pb.SensorItemResult_UNKNOWN
+	}
 }
 
 // HealthCheck handles health check requests
@@ -94,6 +193,132 @@ func (s *sensorServer) HealthCheck(ctx context.Context, req *pb.HealthCheckReque
 	}, nil
 }
 
+// authorizeSensorData checks the sensor type and device against the caller's API key scope.
+// Calls without an API key in the context are allowed, which is the case when gRPC auth is disabled.
+func authorizeSensorData(ctx context.Context, data *pb.SensorData) error {
+	apiKey, ok := authGrpc.APIKeyFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !apiKey.AllowsSensorType(data.SensorType) {
+		return status.Errorf(codes.PermissionDenied, "api key is not allowed to send %s data", data.SensorType)
+	}
+	if !apiKey.AllowsDevice(data.Id1) {
+		return status.Errorf(codes.PermissionDenied, "api key is not allowed to send data for device %s", data.Id1)
+	}
+
+	return nil
+}
+
+// authorizeSensorRead checks a stored reading against the caller's API key scope
+func authorizeSensorRead(ctx context.Context, sensorType, id1 string) error {
+	apiKey, ok := authGrpc.APIKeyFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !apiKey.AllowsSensorType(sensorType) {
+		return status.Errorf(codes.PermissionDenied, "api key is not allowed to read %s data", sensorType)
+	}
+	if !apiKey.AllowsDevice(id1) {
+		return status.Errorf(codes.PermissionDenied, "api key is not allowed to read data for device %s", id1)
+	}
+
+	return nil
+}
+
+// scopeSensorDataFilter narrows filter to the sensor types and devices the caller's API key may read.
+// Asking only for sensor types or devices outside the key's scope is denied.
+func scopeSensorDataFilter(ctx context.Context, filter *dtos.SensorDataFilter) error {
+	apiKey, ok := authGrpc.APIKeyFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if filter.SensorType != nil && !apiKey.AllowsSensorType(*filter.SensorType) {
+		return status.Errorf(codes.PermissionDenied, "api key is not allowed to read %s data", *filter.SensorType)
+	}
+	if filter.ID1 != nil && !apiKey.AllowsDevice(*filter.ID1) {
+		return status.Errorf(codes.PermissionDenied, "api key is not allowed to read data for device %s", *filter.ID1)
+	}
+
+	sensorTypes, devices, err := scopeValues(apiKey, filter.SensorTypes, filter.ID1s)
+	if err != nil {
+		return err
+	}
+	filter.SensorTypes = sensorTypes
+	filter.ID1s = devices
+	return nil
+}
+
+// scopeWatchFilter narrows a hub filter to the sensor types and devices the caller's API key may read
+func scopeWatchFilter(ctx context.Context, filter *pubsub.Filter) error {
+	apiKey, ok := authGrpc.APIKeyFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	sensorTypes, devices, err := scopeValues(apiKey, filter.SensorTypes, filter.ID1s)
+	if err != nil {
+		return err
+	}
+	filter.SensorTypes = sensorTypes
+	filter.ID1s = devices
+	return nil
+}
+
+// scopeValues intersects requested sensor types and devices with the lists allowed by apiKey.
+// An empty requested list stands for everything the key allows.
+func scopeValues(apiKey *authEntities.APIKey, sensorTypes, devices []string) ([]string, []string, error) {
+	sensorTypes, ok := intersectAllowed(sensorTypes, authEntities.SplitList(apiKey.AllowedSensorTypes))
+	if !ok {
+		return nil, nil, status.Error(codes.PermissionDenied, "api key is not allowed to read the requested sensor types")
+	}
+	devices, ok = intersectAllowed(devices, authEntities.SplitList(apiKey.AllowedDevices))
+	if !ok {
+		return nil, nil, status.Error(codes.PermissionDenied, "api key is not allowed to read the requested devices")
+	}
+	return sensorTypes, devices, nil
+}
+
+// intersectAllowed returns the requested values that are allowed, reporting false when none are.
+// An empty allowed list allows every value.
+func intersectAllowed(requested, allowed []string) ([]string, bool) {
+	if len(allowed) == 0 {
+		return requested, true
+	}
+	if len(requested) == 0 {
+		return allowed, true
+	}
+
+	var result []string
+	for _, value := range requested {
+		if utils.Contains(allowed, value) {
+			result = append(result, value)
+		}
+	}
+	return result, len(result) > 0
+}
+
+// backpressureStatus converts a full ingestion queue into ResourceExhausted with a retry hint,
+// it returns nil for any other error
+func backpressureStatus(err error) error {
+	var queueFull *pipeline.QueueFullError
+	if !errors.As(err, &queueFull) {
+		return nil
+	}
+
+	st := status.New(codes.ResourceExhausted, queueFull.Error())
+	detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(queueFull.RetryAfter),
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
 // Helper function to convert time to protobuf timestamp
 func timeToTimestamp(t time.Time) *timestamppb.Timestamp {
 	return timestamppb.New(t)
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pubsub"
+	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+)
+
+// WatchSensorData streams newly ingested sensor data matching the filter until the client disconnects
+func (s *sensorServer) WatchSensorData(req *pb.WatchRequest, stream pb.SensorService_WatchSensorDataServer) error {
+	if s.hub == nil {
+		return status.Error(codes.Unimplemented, "live subscriptions are not enabled")
+	}
+	if req.MinValue != nil && req.MaxValue != nil && req.MinValue.Value > req.MaxValue.Value {
+		return status.Error(codes.InvalidArgument, "min_value must not be greater than max_value")
+	}
+
+	filter := toWatchFilter(req)
+	if err := scopeWatchFilter(stream.Context(), &filter); err != nil {
+		return err
+	}
+
+	sub := s.hub.Subscribe(filter, pubsub.SubscribeOptions{
+		BufferSize: int(req.BufferSize),
+		Policy:     toSlowConsumerPolicy(req.SlowConsumerPolicy),
+	})
+	defer s.hub.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-sub.Done():
+			if errors.Is(sub.Err(), pubsub.ErrSlowConsumer) {
+				return status.Error(codes.ResourceExhausted, sub.Err().Error())
+			}
+			return status.Error(codes.Unavailable, "subscription closed")
+		case data := <-sub.C():
+			if err := stream.Send(toSensorDataRecord(data)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toWatchFilter converts a watch request to the hub filter
+func toWatchFilter(req *pb.WatchRequest) pubsub.Filter {
+	filter := pubsub.Filter{
+		SensorTypes: req.SensorTypes,
+		ID1s:        req.Id1,
+		ID2s:        req.Id2,
+	}
+	if req.MinValue != nil {
+		filter.MinValue = &req.MinValue.Value
+	}
+	if req.MaxValue != nil {
+		filter.MaxValue = &req.MaxValue.Value
+	}
+	return filter
+}
+
+// toSlowConsumerPolicy converts the requested policy, leaving the hub default in place when unset
+func toSlowConsumerPolicy(policy pb.WatchRequest_SlowConsumerPolicy) pubsub.SlowConsumerPolicy {
+	switch policy {
+	case pb.WatchRequest_DROP_OLDEST:
+		return pubsub.PolicyDropOldest
+	case pb.WatchRequest_DISCONNECT:
+		return pubsub.PolicyDisconnect
+	default:
+		return ""
+	}
+}
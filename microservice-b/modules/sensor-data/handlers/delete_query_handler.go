@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/actor"
+	auditInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/audit/interfaces"
+	jobInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/jobs/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/query"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/services"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// deleteJobType identifies delete query jobs
+const deleteJobType = "sensor_delete"
+
+// DeleteQueryHandler deletes sensor data by filter in two steps: a dry run returning the number of
+// matching readings, a sample and a confirmation token, then the confirmed delete. Deletes of more
+// than syncMaxRows readings run chunked in a background job. Executed deletes are recorded in the audit trail.
+type DeleteQueryHandler struct {
+	sensorService interfaces.SensorServiceInterface
+	jobService    jobInterfaces.JobServiceInterface
+	auditService  auditInterfaces.AuditServiceInterface
+	confirmer     *services.DeleteConfirmer
+	chunkSize     int
+	syncMaxRows   int64
+}
+
+// NewDeleteQueryHandler creates a new delete query handler
+func NewDeleteQueryHandler(sensorService interfaces.SensorServiceInterface, jobService jobInterfaces.JobServiceInterface, auditService auditInterfaces.AuditServiceInterface, confirmer *services.DeleteConfirmer, chunkSize int, syncMaxRows int64) *DeleteQueryHandler {
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+	return &DeleteQueryHandler{
+		sensorService: sensorService,
+		jobService:    jobService,
+		auditService:  auditService,
+		confirmer:     confirmer,
+		chunkSize:     chunkSize,
+		syncMaxRows:   syncMaxRows,
+	}
+}
+
+// DeleteQuery godoc
+// @Summary Delete sensor data by filter
+// @Description Without a confirmation_token, returns how many readings match the filter, a sample of them and a confirmation token (dry run). Sending the same filter with the token deletes the matching readings. Large deletes run chunked in a background job: the response is 202 with the job to poll at /jobs/{id}. The filter must have at least one criterion.
+// @Tags sensors
+// @Accept json
+// @Produce json
+// @Param request body dtos.DeleteQueryRequest true "Filter and, to execute, the confirmation token"
+// @Success 200 {object} shared.APIResponse{data=dtos.DeleteQueryPreview} "Dry run"
+// @Success 202 {object} shared.APIResponse "Delete job started"
+// @Failure 400 {object} shared.APIResponse "Invalid filter or confirmation token"
+// @Failure 403 {object} shared.APIResponse "Admin role required"
+// @Security Bearer
+// @Router /sensors/delete-query [post]
+func (h *DeleteQueryHandler) DeleteQuery(c echo.Context) error {
+	var req dtos.DeleteQueryRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidDeleteQuery(c, "Invalid request body")
+	}
+	if err := req.Filter.Validate(); err != nil {
+		return invalidDeleteQuery(c, err.Error())
+	}
+	if req.Filter.IsEmpty() {
+		return invalidDeleteQuery(c, "The filter must have at least one criterion")
+	}
+	userID, _ := c.Get("user_id").(uint)
+
+	if req.ConfirmationToken == "" {
+		return h.preview(c, userID, &req.Filter)
+	}
+	if err := h.confirmer.Verify(req.ConfirmationToken, userID, &req.Filter); err != nil {
+		return invalidDeleteQuery(c, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	total, err := h.sensorService.CountSensorData(ctx, &req.Filter)
+	if err != nil {
+		return deleteQueryFailed(c, err)
+	}
+
+	if total <= h.syncMaxRows {
+		deleted, err := h.sensorService.DeleteSensorDataInChunks(ctx, &req.Filter, h.chunkSize, nil)
+		if err != nil {
+			return deleteQueryFailed(c, err)
+		}
+		h.audit(ctx, &req.Filter, map[string]interface{}{"deleted": deleted})
+		return c.JSON(http.StatusOK, shared.APIResponse{
+			Status:  constants.StatusSuccess,
+			Message: "Sensor data deleted successfully",
+			Data:    dtos.DeleteQueryProgress{Deleted: deleted, Total: total},
+		})
+	}
+
+	filter := req.Filter
+	// The job outlives the request, so carry the actor over for the history records
+	by, _ := actor.FromContext(ctx)
+	job, err := h.jobService.Start(ctx, deleteJobType, userID, func(ctx context.Context, progress func(value interface{})) (interface{}, error) {
+		ctx = actor.NewContext(ctx, by)
+		deleted, err := h.sensorService.DeleteSensorDataInChunks(ctx, &filter, h.chunkSize, func(deleted int64) {
+			progress(dtos.DeleteQueryProgress{Deleted: deleted, Total: total})
+		})
+		if err != nil {
+			return nil, err
+		}
+		return dtos.DeleteQueryProgress{Deleted: deleted, Total: total}, nil
+	})
+	if err != nil {
+		return deleteQueryFailed(c, err)
+	}
+
+	utils.Info(fmt.Sprintf("Started delete job %s for %d sensor data records", job.ID, total))
+	h.audit(ctx, &req.Filter, map[string]interface{}{"total": total, "job_id": job.ID})
+	c.Response().Header().Set(echo.HeaderLocation, "/api/v1/jobs/"+job.ID)
+	return c.JSON(http.StatusAccepted, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Delete job started",
+		Data:    job,
+	})
+}
+
+// preview counts and samples the readings matching filter and issues the confirmation token
+func (h *DeleteQueryHandler) preview(c echo.Context, userID uint, filter *dtos.SensorDataFilter) error {
+	result, err := h.sensorService.ListSensorData(c.Request().Context(), filter, &dtos.PaginationParams{
+		Page:     1,
+		PageSize: dtos.DeleteQuerySampleSize,
+		Sort:     "timestamp",
+		Order:    "desc",
+	})
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidQuery) {
+			return invalidDeleteQuery(c, err.Error())
+		}
+		return deleteQueryFailed(c, err)
+	}
+
+	token, expiresAt, err := h.confirmer.Issue(userID, filter)
+	if err != nil {
+		return deleteQueryFailed(c, err)
+	}
+
+	sample, _ := result.Data.([]*entities.SensorData)
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Dry run, send the confirmation token to delete the matching sensor data",
+		Data: dtos.DeleteQueryPreview{
+			Count:             result.Total,
+			Sample:            sample,
+			ConfirmationToken: token,
+			ExpiresAt:         expiresAt,
+		},
+	})
+}
+
+// audit records an executed delete query
+func (h *DeleteQueryHandler) audit(ctx context.Context, filter *dtos.SensorDataFilter, details map[string]interface{}) {
+	details["filter"] = filter
+	if err := h.auditService.Record(ctx, auditActionDeleteByFilter, auditResourceSensorData, "", details); err != nil {
+		utils.Error(fmt.Sprintf("Failed to record %s: %v", auditActionDeleteByFilter, err))
+	}
+}
+
+func invalidDeleteQuery(c echo.Context, message string) error {
+	return c.JSON(http.StatusBadRequest, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInvalidRequest,
+		Error:   message,
+	})
+}
+
+func deleteQueryFailed(c echo.Context, err error) error {
+	return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInternalServer,
+		Error:   err.Error(),
+	})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/export"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// Export godoc
+// @Summary Export sensor data
+// @Description Stream all matching readings ordered by timestamp as CSV, NDJSON or Parquet. The format is taken from the format parameter, else from the Accept header, defaulting to CSV. compression=gzip returns a gzip file; otherwise the response is gzip encoded when the client accepts it. A failure after streaming started aborts the connection, so a truncated download is never mistaken for a complete one.
+// @Tags sensors
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.apache.parquet
+// @Param format query string false "Export format (csv, ndjson, parquet)"
+// @Param compression query string false "gzip to download a gzip compressed file"
+// @Param sensor_type query string false "Sensor type filter, comma separated for several"
+// @Param id1 query string false "ID1 filter, comma separated for several"
+// @Param id2 query int false "ID2 filter"
+// @Param from_time query string false "From time filter (RFC3339)"
+// @Param to_time query string false "To time filter (RFC3339)"
+// @Param min_value query number false "Minimum value filter"
+// @Param max_value query number false "Maximum value filter"
+// @Param q query string false "Filter expression, e.g. sensor_type in (temperature,humidity) and value > 30"
+// @Success 200 {file} file
+// @Failure 400 {object} shared.APIResponse "Invalid parameters"
+// @Security Bearer
+// @Router /sensors/export [get]
+func (h *SensorHandler) Export(c echo.Context) error {
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	format := export.NegotiateFormat(c.Request().Header.Get(echo.HeaderAccept))
+	if name := c.QueryParam("format"); name != "" {
+		if format, err = export.ParseFormat(name); err != nil {
+			return c.JSON(http.StatusBadRequest, shared.APIResponse{
+				Status:  constants.StatusError,
+				Message: constants.ErrInvalidRequest,
+				Error:   err.Error(),
+			})
+		}
+	}
+
+	compression := c.QueryParam("compression")
+	if compression != "" && compression != "gzip" {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   fmt.Sprintf("unsupported compression %q, use gzip", compression),
+		})
+	}
+
+	stream := &exportStream{
+		c:            c,
+		format:       format,
+		gzipFile:     compression == "gzip",
+		gzipEncoding: compression == "" && strings.Contains(c.Request().Header.Get(echo.HeaderAcceptEncoding), "gzip"),
+	}
+
+	err = h.sensorService.ExportSensorData(c.Request().Context(), filter, stream.write)
+	if err == nil {
+		err = stream.close()
+	}
+	if err != nil {
+		if !stream.started() {
+			return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+				Status:  constants.StatusError,
+				Message: constants.ErrInternalServer,
+				Error:   err.Error(),
+			})
+		}
+		// The status line is sent, abort the response so the client sees an incomplete transfer
+		utils.Error(fmt.Sprintf("Sensor data export aborted: %v", err))
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
+
+// exportStream writes export batches to the response, sending the headers with the first batch
+// so that errors before any data is read can still be reported as JSON
+type exportStream struct {
+	c            echo.Context
+	format       export.Format
+	gzipFile     bool
+	gzipEncoding bool
+	gzip         *gzip.Writer
+	writer       export.Writer
+}
+
+func (s *exportStream) started() bool {
+	return s.c.Response().Committed
+}
+
+func (s *exportStream) start() error {
+	header := s.c.Response().Header()
+	filename := "sensor-data." + s.format.Extension
+	header.Set(echo.HeaderContentType, s.format.ContentType)
+	if s.gzipFile {
+		filename += ".gz"
+		header.Set(echo.HeaderContentType, "application/gzip")
+	}
+	if s.gzipEncoding {
+		header.Set(echo.HeaderContentEncoding, "gzip")
+	}
+	header.Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	s.c.Response().WriteHeader(http.StatusOK)
+
+	var out io.Writer = s.c.Response()
+	if s.gzipFile || s.gzipEncoding {
+		s.gzip = gzip.NewWriter(out)
+		out = s.gzip
+	}
+
+	writer, err := export.NewWriter(s.format, out)
+	if err != nil {
+		return err
+	}
+	s.writer = writer
+	return nil
+}
+
+func (s *exportStream) write(batch []*entities.SensorData) error {
+	if s.writer == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	if err := s.writer.Write(batch); err != nil {
+		return err
+	}
+	if s.gzip != nil {
+		if err := s.gzip.Flush(); err != nil {
+			return err
+		}
+	}
+	s.c.Response().Flush()
+	return nil
+}
+
+// close finishes the file, sending an empty one if nothing matched
+func (s *exportStream) close() error {
+	if s.writer == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	if err := s.writer.Close(); err != nil {
+		return err
+	}
+	if s.gzip != nil {
+		return s.gzip.Close()
+	}
+	return nil
+}
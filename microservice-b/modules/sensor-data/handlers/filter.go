@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+)
+
+// parseFilter reads SensorDataFilter query parameters, rejecting malformed values.
+// sensor_type and id1 accept several values, comma separated or repeated.
+func parseFilter(c echo.Context) (*dtos.SensorDataFilter, error) {
+	filter := &dtos.SensorDataFilter{}
+
+	if sensorTypes := queryList(c, "sensor_type"); len(sensorTypes) == 1 {
+		filter.SensorType = &sensorTypes[0]
+	} else if len(sensorTypes) > 1 {
+		filter.SensorTypes = sensorTypes
+	}
+
+	if id1s := queryList(c, "id1"); len(id1s) == 1 {
+		filter.ID1 = &id1s[0]
+	} else if len(id1s) > 1 {
+		filter.ID1s = id1s
+	}
+
+	if id2Str := c.QueryParam("id2"); id2Str != "" {
+		id2, err := strconv.ParseInt(id2Str, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id2 %q", id2Str)
+		}
+		id2Int32 := int32(id2)
+		filter.ID2 = &id2Int32
+	}
+
+	if fromTimeStr := c.QueryParam("from_time"); fromTimeStr != "" {
+		fromTime, err := time.Parse(time.RFC3339, fromTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from_time %q, use RFC3339", fromTimeStr)
+		}
+		filter.FromTime = &fromTime
+	}
+
+	if toTimeStr := c.QueryParam("to_time"); toTimeStr != "" {
+		toTime, err := time.Parse(time.RFC3339, toTimeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to_time %q, use RFC3339", toTimeStr)
+		}
+		filter.ToTime = &toTime
+	}
+
+	if minValueStr := c.QueryParam("min_value"); minValueStr != "" {
+		minValue, err := strconv.ParseFloat(minValueStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_value %q", minValueStr)
+		}
+		filter.MinValue = &minValue
+	}
+
+	if maxValueStr := c.QueryParam("max_value"); maxValueStr != "" {
+		maxValue, err := strconv.ParseFloat(maxValueStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_value %q", maxValueStr)
+		}
+		filter.MaxValue = &maxValue
+	}
+
+	filter.Expression = c.QueryParam("q")
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// queryList returns the non-empty values of a repeated or comma separated query parameter
+func queryList(c echo.Context, name string) []string {
+	var values []string
+	for _, param := range c.QueryParams()[name] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
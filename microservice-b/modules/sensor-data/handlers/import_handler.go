@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	jobInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/jobs/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/importer"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// importJobType identifies import jobs
+const importJobType = "sensor_import"
+
+// ImportHandler imports historical sensor data from CSV or NDJSON uploads. Small uploads are imported
+// within the request, larger ones are spooled to a temporary file and imported by a background job.
+type ImportHandler struct {
+	importer    *importer.Importer
+	jobService  jobInterfaces.JobServiceInterface
+	syncMaxSize int64
+}
+
+// NewImportHandler creates a new import handler; uploads above syncMaxSize bytes run as jobs by default
+func NewImportHandler(importer *importer.Importer, jobService jobInterfaces.JobServiceInterface, syncMaxSize int64) *ImportHandler {
+	return &ImportHandler{
+		importer:    importer,
+		jobService:  jobService,
+		syncMaxSize: syncMaxSize,
+	}
+}
+
+// Import godoc
+// @Summary Import sensor data
+// @Description Import historical readings from a CSV (header row with sensor_type, id1, id2, sensor_value, timestamp) or NDJSON upload, sent as the request body or as the multipart field "file". Rows are validated like live data and stored in chunks; duplicates of stored readings are skipped. The report lists rejected and duplicate rows with their line numbers. Uploads larger than the synchronous limit, or with async=true, are imported by a background job: the response is 202 with the job to poll at /jobs/{id}.
+// @Tags sensors
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Accept multipart/form-data
+// @Produce json
+// @Param format query string false "Upload format (csv, ndjson), detected from the content type or file name if omitted"
+// @Param dry_run query bool false "Only validate the rows, store nothing"
+// @Param async query bool false "Force (true) or prevent (false) running the import as a background job"
+// @Param file formData file false "Upload as multipart form data"
+// @Success 200 {object} shared.APIResponse{data=dtos.ImportReport}
+// @Success 202 {object} shared.APIResponse "Import job started"
+// @Failure 400 {object} shared.APIResponse "Invalid parameters or unreadable file"
+// @Failure 413 {object} shared.APIResponse "Upload too large"
+// @Security Bearer
+// @Router /sensors/import [post]
+func (h *ImportHandler) Import(c echo.Context) error {
+	dryRun, err := parseOptionalBool(c.QueryParam("dry_run"))
+	if err != nil {
+		return invalidImport(c, fmt.Errorf("invalid dry_run: %v", err))
+	}
+	async, err := parseOptionalBool(c.QueryParam("async"))
+	if err != nil {
+		return invalidImport(c, fmt.Errorf("invalid async: %v", err))
+	}
+
+	upload, size, contentType, filename, err := openUpload(c)
+	if err != nil {
+		return invalidImport(c, err)
+	}
+	defer upload.Close()
+
+	format, err := importer.DetectFormat(c.QueryParam("format"), contentType, filename)
+	if err != nil {
+		return invalidImport(c, err)
+	}
+
+	runAsync := size < 0 || size > h.syncMaxSize
+	if async != nil {
+		runAsync = *async
+	}
+	dry := dryRun != nil && *dryRun
+
+	if !runAsync {
+		report, err := h.importer.Import(c.Request().Context(), format, upload, size, dry, nil)
+		if err != nil {
+			statusCode, message := http.StatusInternalServerError, constants.ErrInternalServer
+			if errors.Is(err, importer.ErrInvalidFile) {
+				statusCode, message = http.StatusBadRequest, constants.ErrInvalidRequest
+			}
+			return c.JSON(statusCode, shared.APIResponse{
+				Status:  constants.StatusError,
+				Message: message,
+				Data:    report,
+				Error:   err.Error(),
+			})
+		}
+
+		return c.JSON(http.StatusOK, shared.APIResponse{
+			Status:  constants.StatusSuccess,
+			Message: "Sensor data imported successfully",
+			Data:    report,
+		})
+	}
+
+	// The upload has to outlive the request, keep it in a temporary file until the job is done
+	spool, err := os.CreateTemp("", "sensor-import-*")
+	if err != nil {
+		return importFailed(c, err)
+	}
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+	written, err := io.Copy(spool, upload)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return importFailed(c, err)
+	}
+
+	userID, _ := c.Get("user_id").(uint)
+	job, err := h.jobService.Start(c.Request().Context(), importJobType, userID, func(ctx context.Context, progress func(value interface{})) (interface{}, error) {
+		defer cleanup()
+		return h.importer.Import(ctx, format, spool, written, dry, func(counts dtos.ImportCounts) {
+			progress(counts)
+		})
+	})
+	if err != nil {
+		cleanup()
+		return importFailed(c, err)
+	}
+
+	utils.Info(fmt.Sprintf("Started import job %s for %d bytes of %s", job.ID, written, format))
+	c.Response().Header().Set(echo.HeaderLocation, "/api/v1/jobs/"+job.ID)
+	return c.JSON(http.StatusAccepted, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Import job started",
+		Data:    job,
+	})
+}
+
+// openUpload returns the uploaded file with its size (-1 if unknown), content type and file name,
+// from the multipart field "file" or else the raw request body
+func openUpload(c echo.Context) (io.ReadCloser, int64, string, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != echo.MIMEMultipartForm {
+		return c.Request().Body, c.Request().ContentLength, mediaType, "", nil
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, 0, "", "", fmt.Errorf("missing file: %v", err)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, 0, "", "", err
+	}
+	return file, header.Size, header.Header.Get(echo.HeaderContentType), header.Filename, nil
+}
+
+// parseOptionalBool parses a boolean query parameter, nil when it is not set
+func parseOptionalBool(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func invalidImport(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInvalidRequest,
+		Error:   err.Error(),
+	})
+}
+
+func importFailed(c echo.Context, err error) error {
+	return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInternalServer,
+		Error:   err.Error(),
+	})
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	authEntities "github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+	authGrpc "github.com/worlder-team/microservice-server/microservice-b/modules/auth/grpc"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+)
+
+var responseMarshaler = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// IngestHandler exposes the SensorService ingestion RPCs over HTTP/JSON.
+// Requests are decoded with the protobuf JSON mapping and handed to the same server
+// implementation as gRPC, so validation, API key scoping and backpressure are shared.
+type IngestHandler struct {
+	sensorServer pb.SensorServiceServer
+}
+
+// NewIngestHandler creates a new HTTP ingestion handler
+func NewIngestHandler(sensorServer pb.SensorServiceServer) *IngestHandler {
+	return &IngestHandler{
+		sensorServer: sensorServer,
+	}
+}
+
+// Ingest godoc
+// @Summary Ingest sensor data
+// @Description Store a single sensor reading sent by a device (HTTP/JSON form of SendSensorData). Field names may be snake_case or camelCase.
+// @Tags ingest
+// @Accept json
+// @Produce json
+// @Param request body dtos.IngestSensorDataRequest true "Sensor reading"
+// @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid request"
+// @Failure 401 {object} shared.APIResponse "Missing or invalid API key"
+// @Failure 403 {object} shared.APIResponse "API key not allowed for this sensor type or device"
+// @Failure 429 {object} shared.APIResponse "Ingestion queue full, see Retry-After"
+// @Security APIKey
+// @Router /ingest [post]
+func (h *IngestHandler) Ingest(c echo.Context) error {
+	var request pb.SensorData
+	if err := decodeProtoJSON(c, &request); err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	response, err := h.sensorServer.SendSensorData(ingestContext(c), &request)
+	if err != nil {
+		return grpcErrorResponse(c, err)
+	}
+
+	if !response.Success {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: response.Message,
+			Error:   response.Error,
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: response.Message,
+	})
+}
+
+// IngestBatch godoc
+// @Summary Ingest a batch of sensor data
+// @Description Store a batch of sensor readings (HTTP/JSON form of SendSensorDataBatch). Returns 207 with per-item results when some items were rejected.
+// @Tags ingest
+// @Accept json
+// @Produce json
+// @Param request body dtos.IngestBatchRequest true "Sensor readings"
+// @Success 200 {object} shared.APIResponse
+// @Success 207 {object} shared.APIResponse "Some items were rejected"
+// @Failure 400 {object} shared.APIResponse "Invalid request"
+// @Failure 401 {object} shared.APIResponse "Missing or invalid API key"
+// @Failure 429 {object} shared.APIResponse "Ingestion queue full, see Retry-After"
+// @Security APIKey
+// @Router /ingest/batch [post]
+func (h *IngestHandler) IngestBatch(c echo.Context) error {
+	var request pb.SensorDataBatch
+	if err := decodeProtoJSON(c, &request); err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	response, err := h.sensorServer.SendSensorDataBatch(ingestContext(c), &request)
+	if err != nil {
+		return grpcErrorResponse(c, err)
+	}
+
+	data, err := responseMarshaler.Marshal(response)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	statusCode, responseStatus := http.StatusOK, constants.StatusSuccess
+	if !response.Success {
+		statusCode, responseStatus = http.StatusMultiStatus, constants.StatusFailed
+	}
+
+	return c.JSON(statusCode, shared.APIResponse{
+		Status:  responseStatus,
+		Message: response.Message,
+		Data:    json.RawMessage(data),
+		Error:   response.Error,
+	})
+}
+
+// decodeProtoJSON reads the request body into a protobuf message using the protobuf JSON mapping
+func decodeProtoJSON(c echo.Context, message proto.Message) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, message)
+}
+
+// ingestContext carries the device API key into the request context the way the gRPC interceptor does
+func ingestContext(c echo.Context) context.Context {
+	ctx := c.Request().Context()
+	if apiKey, ok := c.Get("api_key").(*authEntities.APIKey); ok {
+		ctx = authGrpc.NewContextWithAPIKey(ctx, apiKey)
+	}
+	return ctx
+}
+
+// grpcErrorResponse converts a gRPC status error into the matching HTTP response
+func grpcErrorResponse(c echo.Context, err error) error {
+	st := status.Convert(err)
+
+	statusCode := http.StatusInternalServerError
+	message := constants.ErrInternalServer
+	switch st.Code() {
+	case codes.InvalidArgument:
+		statusCode, message = http.StatusBadRequest, constants.ErrInvalidRequest
+	case codes.Unauthenticated:
+		statusCode, message = http.StatusUnauthorized, constants.ErrUnauthorized
+	case codes.PermissionDenied:
+		statusCode, message = http.StatusForbidden, constants.ErrForbidden
+	case codes.NotFound:
+		statusCode, message = http.StatusNotFound, constants.ErrNotFound
+	case codes.ResourceExhausted:
+		statusCode, message = http.StatusTooManyRequests, constants.ErrRateLimitExceeded
+	case codes.Unavailable:
+		statusCode = http.StatusServiceUnavailable
+	case codes.DeadlineExceeded, codes.Canceled:
+		statusCode = http.StatusGatewayTimeout
+	}
+
+	for _, detail := range st.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok && retryInfo.RetryDelay != nil {
+			seconds := int(math.Ceil(retryInfo.RetryDelay.AsDuration().Seconds()))
+			c.Response().Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+		}
+	}
+
+	return c.JSON(statusCode, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: message,
+		Error:   st.Message(),
+	})
+}
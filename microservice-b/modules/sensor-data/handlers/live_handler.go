@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pubsub"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+)
+
+const (
+	transportSSE       = "sse"
+	transportWebSocket = "websocket"
+
+	eventReading = "reading"
+	eventError   = "error"
+)
+
+var (
+	liveConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sensor_live_connections",
+		Help: "Open live sensor data connections by transport (sse, websocket).",
+	}, []string{"transport"})
+	liveDisconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sensor_live_disconnects_total",
+		Help: "Closed live sensor data connections by transport and reason (client, slow_consumer, write_failed, shutdown).",
+	}, []string{"transport", "reason"})
+)
+
+// liveMessage is a WebSocket message, SSE sends Event as the event name and Data as the data
+type liveMessage struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// LiveHandler pushes newly ingested readings to dashboards over Server-Sent Events and WebSocket
+type LiveHandler struct {
+	hub            *pubsub.Hub
+	heartbeat      time.Duration
+	writeTimeout   time.Duration
+	maxConnections int64
+	connections    atomic.Int64
+	upgrader       websocket.Upgrader
+}
+
+// NewLiveHandler creates a live feed handler. Connections are sent a heartbeat every heartbeat, closed
+// when a write blocks for writeTimeout and limited to maxConnections per replica (0 for no limit).
+func NewLiveHandler(hub *pubsub.Hub, heartbeat, writeTimeout time.Duration, maxConnections int) *LiveHandler {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	if writeTimeout <= 0 {
+		writeTimeout = 10 * time.Second
+	}
+	return &LiveHandler{
+		hub:            hub,
+		heartbeat:      heartbeat,
+		writeTimeout:   writeTimeout,
+		maxConnections: int64(maxConnections),
+		upgrader: websocket.Upgrader{
+			// Clients authenticate with a bearer token rather than cookies, so any origin may connect
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+	}
+}
+
+// SSE godoc
+// @Summary Live sensor data (Server-Sent Events)
+// @Description Stream newly ingested readings matching the filter as Server-Sent Events until the client disconnects. Each reading is a "reading" event with the reading as JSON data and its ID as the event ID; a comment line is sent as a heartbeat. A subscriber whose buffer overflows either loses its oldest buffered readings (drop_oldest) or gets an "error" event and is disconnected (disconnect). EventSource cannot send headers, so the JWT may be passed as the access_token parameter.
+// @Tags sensors
+// @Produce text/event-stream
+// @Param sensor_type query string false "Sensor type filter, comma separated for several"
+// @Param id1 query string false "ID1 filter, comma separated for several"
+// @Param id2 query string false "ID2 filter, comma separated for several"
+// @Param min_value query number false "Minimum value filter"
+// @Param max_value query number false "Maximum value filter"
+// @Param buffer_size query int false "Readings buffered for this connection, at most the server default"
+// @Param slow_consumer_policy query string false "What happens when the buffer is full (drop_oldest, disconnect)"
+// @Param access_token query string false "JWT, for clients that cannot send the Authorization header"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} shared.APIResponse "Invalid filter parameters"
+// @Failure 503 {object} shared.APIResponse "Too many live connections"
+// @Security Bearer
+// @Router /sensors/live [get]
+func (h *LiveHandler) SSE(c echo.Context) error {
+	filter, opts, err := parseLiveFilter(c)
+	if err != nil {
+		return invalidLiveRequest(c, err)
+	}
+	if !h.acquire(transportSSE) {
+		return tooManyLiveConnections(c)
+	}
+	defer h.release(transportSSE)
+
+	sub := h.hub.Subscribe(filter, opts)
+	defer h.hub.Unsubscribe(sub)
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, "text/event-stream")
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	response.Header().Set(echo.HeaderConnection, "keep-alive")
+	// Keep proxies such as nginx from buffering the stream
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(response)
+	write := func(format string, args ...interface{}) error {
+		// Not every writer supports deadlines, the stream then relies on the server timeouts
+		_ = controller.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+		if _, err := fmt.Fprintf(response, format, args...); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	reason := "client"
+	defer func() { liveDisconnects.WithLabelValues(transportSSE, reason).Inc() }()
+
+	if err := write(": connected\n\n"); err != nil {
+		reason = "write_failed"
+		return nil
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-sub.Done():
+			reason = closeReason(sub.Err())
+			payload, _ := json.Marshal(map[string]string{"error": sub.Err().Error()})
+			_ = write("event: %s\ndata: %s\n\n", eventError, payload)
+			return nil
+		case data := <-sub.C():
+			payload, err := json.Marshal(data)
+			if err != nil {
+				continue
+			}
+			if err := write("id: %d\nevent: %s\ndata: %s\n\n", data.ID, eventReading, payload); err != nil {
+				reason = "write_failed"
+				return nil
+			}
+		case <-ticker.C:
+			if err := write(": heartbeat\n\n"); err != nil {
+				reason = "write_failed"
+				return nil
+			}
+		}
+	}
+}
+
+// WebSocket godoc
+// @Summary Live sensor data (WebSocket)
+// @Description Upgrade to a WebSocket streaming newly ingested readings matching the filter. Each reading is a text message {"event": "reading", "data": <reading>}; the server sends pings as heartbeats and closes connections that stop answering them. A subscriber whose buffer overflows either loses its oldest buffered readings (drop_oldest) or is closed with status 1013 (disconnect). Messages from the client are ignored. Browsers cannot send headers with a WebSocket handshake, so the JWT may be passed as the access_token parameter.
+// @Tags sensors
+// @Param sensor_type query string false "Sensor type filter, comma separated for several"
+// @Param id1 query string false "ID1 filter, comma separated for several"
+// @Param id2 query string false "ID2 filter, comma separated for several"
+// @Param min_value query number false "Minimum value filter"
+// @Param max_value query number false "Maximum value filter"
+// @Param buffer_size query int false "Readings buffered for this connection, at most the server default"
+// @Param slow_consumer_policy query string false "What happens when the buffer is full (drop_oldest, disconnect)"
+// @Param access_token query string false "JWT, for clients that cannot send the Authorization header"
+// @Success 101 {string} string "Switching protocols"
+// @Failure 400 {object} shared.APIResponse "Invalid filter parameters"
+// @Failure 503 {object} shared.APIResponse "Too many live connections"
+// @Security Bearer
+// @Router /sensors/live/ws [get]
+func (h *LiveHandler) WebSocket(c echo.Context) error {
+	filter, opts, err := parseLiveFilter(c)
+	if err != nil {
+		return invalidLiveRequest(c, err)
+	}
+	if !h.acquire(transportWebSocket) {
+		return tooManyLiveConnections(c)
+	}
+	defer h.release(transportWebSocket)
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// The upgrader has replied with an error
+		return nil
+	}
+	defer conn.Close()
+
+	sub := h.hub.Subscribe(filter, opts)
+	defer h.hub.Unsubscribe(sub)
+
+	reason := "client"
+	defer func() { liveDisconnects.WithLabelValues(transportWebSocket, reason).Inc() }()
+
+	// Reading processes pongs and the close handshake; a client missing two heartbeats is gone
+	pongWait := 2 * h.heartbeat
+	conn.SetReadLimit(4096)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	clientGone := make(chan struct{})
+	go func() {
+		defer close(clientGone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-clientGone:
+			return nil
+		case <-sub.Done():
+			reason = closeReason(sub.Err())
+			code := websocket.CloseTryAgainLater
+			if errors.Is(sub.Err(), pubsub.ErrHubClosed) {
+				code = websocket.CloseGoingAway
+			}
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, sub.Err().Error()), time.Now().Add(h.writeTimeout))
+			return nil
+		case data := <-sub.C():
+			_ = conn.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+			if err := conn.WriteJSON(liveMessage{Event: eventReading, Data: data}); err != nil {
+				reason = "write_failed"
+				return nil
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(h.writeTimeout)); err != nil {
+				reason = "write_failed"
+				return nil
+			}
+		}
+	}
+}
+
+// acquire takes a connection slot, returning false if the limit is reached
+func (h *LiveHandler) acquire(transport string) bool {
+	if count := h.connections.Add(1); h.maxConnections > 0 && count > h.maxConnections {
+		h.connections.Add(-1)
+		return false
+	}
+	liveConnections.WithLabelValues(transport).Inc()
+	return true
+}
+
+// release frees a connection slot taken by acquire
+func (h *LiveHandler) release(transport string) {
+	h.connections.Add(-1)
+	liveConnections.WithLabelValues(transport).Dec()
+}
+
+// parseLiveFilter reads the hub filter and subscription options from the query parameters
+func parseLiveFilter(c echo.Context) (pubsub.Filter, pubsub.SubscribeOptions, error) {
+	filter := pubsub.Filter{
+		SensorTypes: queryList(c, "sensor_type"),
+		ID1s:        queryList(c, "id1"),
+	}
+	var opts pubsub.SubscribeOptions
+
+	for _, value := range queryList(c, "id2") {
+		id2, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return filter, opts, fmt.Errorf("invalid id2 %q", value)
+		}
+		filter.ID2s = append(filter.ID2s, int32(id2))
+	}
+	if value := c.QueryParam("min_value"); value != "" {
+		minValue, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return filter, opts, fmt.Errorf("invalid min_value %q", value)
+		}
+		filter.MinValue = &minValue
+	}
+	if value := c.QueryParam("max_value"); value != "" {
+		maxValue, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return filter, opts, fmt.Errorf("invalid max_value %q", value)
+		}
+		filter.MaxValue = &maxValue
+	}
+	if filter.MinValue != nil && filter.MaxValue != nil && *filter.MinValue > *filter.MaxValue {
+		return filter, opts, errors.New("min_value must not be greater than max_value")
+	}
+
+	if value := c.QueryParam("buffer_size"); value != "" {
+		bufferSize, err := strconv.Atoi(value)
+		if err != nil || bufferSize < 1 {
+			return filter, opts, fmt.Errorf("invalid buffer_size %q", value)
+		}
+		opts.BufferSize = bufferSize
+	}
+	switch policy := pubsub.SlowConsumerPolicy(c.QueryParam("slow_consumer_policy")); policy {
+	case "", pubsub.PolicyDropOldest, pubsub.PolicyDisconnect:
+		opts.Policy = policy
+	default:
+		return filter, opts, fmt.Errorf("invalid slow_consumer_policy %q, use %s or %s", policy, pubsub.PolicyDropOldest, pubsub.PolicyDisconnect)
+	}
+	return filter, opts, nil
+}
+
+// closeReason names why the hub closed a subscription, for the disconnect metric
+func closeReason(err error) string {
+	if errors.Is(err, pubsub.ErrSlowConsumer) {
+		return "slow_consumer"
+	}
+	return "shutdown"
+}
+
+func invalidLiveRequest(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInvalidRequest,
+		Error:   err.Error(),
+	})
+}
+
+func tooManyLiveConnections(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: "Too many live connections",
+		Error:   "the live connection limit of this server is reached, retry later",
+	})
+}
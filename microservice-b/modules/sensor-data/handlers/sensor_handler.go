@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,8 +10,11 @@ import (
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/query"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/services"
 	"github.com/worlder-team/microservice-server/microservice-b/shared"
 	"github.com/worlder-team/microservice-server/shared/constants"
+	"gorm.io/gorm"
 )
 
 type SensorHandler struct {
@@ -26,20 +30,27 @@ func NewSensorHandler(sensorService interfaces.SensorServiceInterface) *SensorHa
 
 // List godoc
 // @Summary List sensor data
-// @Description List sensor data with pagination and filtering
+// @Description List sensor data with pagination and filtering. Pages are numbered by default; with pagination=cursor or a cursor parameter the list is ordered by timestamp and ID and paged with the opaque next_cursor and prev_cursor of the response, which stays fast on deep pages. Cursor pages only count the total when with_total=true.
 // @Tags sensors
 // @Accept json
 // @Produce json
+// @Param pagination query string false "Pagination mode (page, cursor)"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
-// @Param sensor_type query string false "Sensor type filter"
-// @Param id1 query string false "ID1 filter"
+// @Param cursor query string false "Cursor of the page to fetch (next_cursor or prev_cursor of a previous page)"
+// @Param with_total query bool false "Count the matching records in cursor mode"
+// @Param sensor_type query string false "Sensor type filter, comma separated for several"
+// @Param id1 query string false "ID1 filter, comma separated for several"
 // @Param id2 query int false "ID2 filter"
 // @Param from_time query string false "From time filter (RFC3339)"
 // @Param to_time query string false "To time filter (RFC3339)"
-// @Param sort query string false "Sort field"
-// @Param order query string false "Sort order (asc, desc)"
-// @Success 200 {object} shared.APIResponse
+// @Param min_value query number false "Minimum value filter"
+// @Param max_value query number false "Maximum value filter"
+// @Param q query string false "Filter expression, e.g. sensor_type in (temperature,humidity) and value > 30"
+// @Param sort query string false "Comma separated sort fields (id, sensor_type, id1, id2, value, timestamp, created_at, updated_at), each optionally with :asc, :desc or a - prefix"
+// @Param order query string false "Default sort order (asc, desc); by timestamp in cursor mode"
+// @Success 200 {object} shared.APIResponse "dtos.PaginatedResponse, or dtos.CursorPage in cursor mode"
+// @Failure 400 {object} shared.APIResponse "Invalid filter, sort or cursor parameters"
 // @Security Bearer
 // @Router /sensors [get]
 func (h *SensorHandler) List(c echo.Context) error {
@@ -56,38 +67,68 @@ func (h *SensorHandler) List(c echo.Context) error {
 		Order:    order,
 	}
 
-	// Parse filters
-	filter := &dtos.SensorDataFilter{}
-
-	if sensorType := c.QueryParam("sensor_type"); sensorType != "" {
-		filter.SensorType = &sensorType
+	filter, err := parseFilter(c)
+	if err != nil {
+		return invalidList(c, err.Error())
 	}
 
-	if id1 := c.QueryParam("id1"); id1 != "" {
-		filter.ID1 = &id1
+	if c.QueryParam("pagination") == "cursor" || c.QueryParams().Has("cursor") {
+		return h.listByCursor(c, filter)
 	}
 
-	if id2Str := c.QueryParam("id2"); id2Str != "" {
-		if id2, err := strconv.ParseInt(id2Str, 10, 32); err == nil {
-			id2Int32 := int32(id2)
-			filter.ID2 = &id2Int32
+	result, err := h.sensorService.ListSensorData(c.Request().Context(), filter, pagination)
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidQuery) {
+			return invalidList(c, err.Error())
 		}
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
 	}
 
-	if fromTimeStr := c.QueryParam("from_time"); fromTimeStr != "" {
-		if fromTime, err := time.Parse(time.RFC3339, fromTimeStr); err == nil {
-			filter.FromTime = &fromTime
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Sensor data retrieved successfully",
+		Data:    result,
+	})
+}
+
+// listByCursor serves List in cursor pagination mode
+func (h *SensorHandler) listByCursor(c echo.Context, filter *dtos.SensorDataFilter) error {
+	params := &dtos.CursorParams{Order: c.QueryParam("order")}
+	if c.QueryParam("page") != "" || c.QueryParam("sort") != "" {
+		return invalidList(c, "The 'page' and 'sort' parameters cannot be combined with cursor pagination")
+	}
+	if params.Order != "" && params.Order != dtos.CursorOrderAsc && params.Order != dtos.CursorOrderDesc {
+		return invalidList(c, "Invalid order, expected asc or desc")
+	}
+	if value := c.QueryParam("cursor"); value != "" {
+		cursor, err := dtos.DecodeCursor(value)
+		if err != nil {
+			return invalidList(c, err.Error())
 		}
+		params.Cursor = cursor
 	}
-
-	if toTimeStr := c.QueryParam("to_time"); toTimeStr != "" {
-		if toTime, err := time.Parse(time.RFC3339, toTimeStr); err == nil {
-			filter.ToTime = &toTime
+	if value := c.QueryParam("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil {
+			return invalidList(c, "Invalid page_size")
 		}
+		params.PageSize = pageSize
+	}
+	withTotal, err := parseOptionalBool(c.QueryParam("with_total"))
+	if err != nil {
+		return invalidList(c, "Invalid with_total")
 	}
+	params.WithTotal = withTotal != nil && *withTotal
 
-	result, err := h.sensorService.ListSensorData(c.Request().Context(), filter, pagination)
+	result, err := h.sensorService.ListSensorDataByCursor(c.Request().Context(), filter, params)
 	if err != nil {
+		if errors.Is(err, query.ErrInvalidQuery) {
+			return invalidList(c, err.Error())
+		}
 		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
 			Status:  constants.StatusError,
 			Message: constants.ErrInternalServer,
@@ -102,6 +143,102 @@ func (h *SensorHandler) List(c echo.Context) error {
 	})
 }
 
+func invalidList(c echo.Context, message string) error {
+	return c.JSON(http.StatusBadRequest, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInvalidRequest,
+		Error:   message,
+	})
+}
+
+// Aggregate godoc
+// @Summary Aggregate sensor data
+// @Description Compute min, max, avg, sum, count, first and last value per time bucket, optionally grouped by sensor_type, id1 and/or id2. Buckets are aligned to the Unix epoch (UTC).
+// @Tags sensors
+// @Accept json
+// @Produce json
+// @Param bucket query string true "Bucket size (e.g. 1m, 5m, 1h, 1d or any duration of whole seconds)"
+// @Param group_by query string false "Comma separated group by fields (sensor_type, id1, id2)"
+// @Param sensor_type query string false "Sensor type filter, comma separated for several"
+// @Param id1 query string false "ID1 filter, comma separated for several"
+// @Param id2 query int false "ID2 filter"
+// @Param from_time query string false "From time filter (RFC3339)"
+// @Param to_time query string false "To time filter (RFC3339)"
+// @Param min_value query number false "Minimum value filter"
+// @Param max_value query number false "Maximum value filter"
+// @Param q query string false "Filter expression, e.g. sensor_type in (temperature,humidity) and value > 30"
+// @Success 200 {object} shared.APIResponse{data=dtos.AggregateResponse}
+// @Failure 400 {object} shared.APIResponse "Invalid parameters or too many buckets"
+// @Security Bearer
+// @Router /sensors/aggregate [get]
+func (h *SensorHandler) Aggregate(c echo.Context) error {
+	bucketStr := c.QueryParam("bucket")
+	if bucketStr == "" {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   "The 'bucket' parameter is required",
+		})
+	}
+
+	bucket, err := dtos.ParseBucket(bucketStr)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	groupBy, err := dtos.ParseGroupBy(c.QueryParam("group_by"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+
+	params := &dtos.AggregateParams{Bucket: bucket, GroupBy: groupBy}
+	buckets, err := h.sensorService.AggregateSensorData(c.Request().Context(), filter, params)
+	if errors.Is(err, services.ErrTooManyBuckets) {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	if groupBy == nil {
+		groupBy = []string{}
+	}
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Sensor data aggregated successfully",
+		Data: dtos.AggregateResponse{
+			Bucket:  bucketStr,
+			GroupBy: groupBy,
+			Buckets: buckets,
+		},
+	})
+}
+
 // GetByIDCombination godoc
 // @Summary Get sensor data by ID combination
 // @Description Get sensor data by ID1 and ID2 combination
@@ -320,6 +457,8 @@ func (h *SensorHandler) Update(c echo.Context) error {
 // @Produce json
 // @Param id path int true "Sensor data ID"
 // @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 404 {object} shared.APIResponse "Sensor data not found"
 // @Security Bearer
 // @Router /sensors/{id} [delete]
 func (h *SensorHandler) Delete(c echo.Context) error {
@@ -335,6 +474,13 @@ func (h *SensorHandler) Delete(c echo.Context) error {
 
 	err = h.sensorService.DeleteSensorDataByID(c.Request().Context(), uint(id))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, shared.APIResponse{
+				Status:  constants.StatusError,
+				Message: constants.ErrNotFound,
+				Error:   "Sensor data not found",
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
 			Status:  constants.StatusError,
 			Message: constants.ErrInternalServer,
@@ -349,6 +495,95 @@ func (h *SensorHandler) Delete(c echo.Context) error {
 	})
 }
 
+// History godoc
+// @Summary Get sensor data history
+// @Description List the updates, deletes and reverts of a reading with the values before and after each change, most recent first. The history stays available after the reading is deleted or purged.
+// @Tags sensors
+// @Produce json
+// @Param id path int true "Sensor data ID"
+// @Success 200 {object} shared.APIResponse{data=[]entities.SensorDataHistory}
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Security Bearer
+// @Router /sensors/{id}/history [get]
+func (h *SensorHandler) History(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   "Invalid ID format",
+		})
+	}
+
+	history, err := h.sensorService.GetSensorDataHistory(c.Request().Context(), uint(id))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Sensor data history retrieved successfully",
+		Data:    history,
+	})
+}
+
+// Revert godoc
+// @Summary Revert sensor data to a previous version
+// @Description Set a reading back to the values it had before a change of its history, undeleting it if it was deleted. The revert is recorded in the history.
+// @Tags sensors
+// @Produce json
+// @Param id path int true "Sensor data ID"
+// @Param history_id path int true "History entry ID"
+// @Success 200 {object} shared.APIResponse{data=entities.SensorData}
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 404 {object} shared.APIResponse "History entry or reading not found"
+// @Security Bearer
+// @Router /sensors/{id}/history/{history_id}/revert [post]
+func (h *SensorHandler) Revert(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   "Invalid ID format",
+		})
+	}
+	historyID, err := strconv.ParseUint(c.Param("history_id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInvalidRequest,
+			Error:   "Invalid history ID format",
+		})
+	}
+
+	data, err := h.sensorService.RevertSensorData(c.Request().Context(), uint(id), uint(historyID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, shared.APIResponse{
+				Status:  constants.StatusError,
+				Message: constants.ErrNotFound,
+				Error:   "History entry or sensor data not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+			Status:  constants.StatusError,
+			Message: constants.ErrInternalServer,
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Sensor data reverted successfully",
+		Data:    data,
+	})
+}
+
 // Request/Response structures
 type UpdateRequest struct {
 	SensorValue *float64   `json:"sensor_value,omitempty"`
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	auditInterfaces "github.com/worlder-team/microservice-server/microservice-b/modules/audit/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/query"
+	"github.com/worlder-team/microservice-server/microservice-b/shared"
+	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// Audited sensor data actions
+const (
+	auditResourceSensorData   = "sensor_data"
+	auditActionDeleteByFilter = "sensor_data.delete_by_filter"
+	auditActionRestore        = "sensor_data.restore"
+	auditActionRestoreFilter  = "sensor_data.restore_by_filter"
+	auditActionPurge          = "sensor_data.purge"
+	auditActionPurgeFilter    = "sensor_data.purge_by_filter"
+)
+
+// TrashHandler lists, restores and permanently purges soft-deleted sensor data.
+// Restores and purges are recorded in the audit trail.
+type TrashHandler struct {
+	sensorService interfaces.SensorServiceInterface
+	auditService  auditInterfaces.AuditServiceInterface
+	chunkSize     int
+}
+
+// NewTrashHandler creates a new trash handler; readings are restored and purged chunkSize rows at a time
+func NewTrashHandler(sensorService interfaces.SensorServiceInterface, auditService auditInterfaces.AuditServiceInterface, chunkSize int) *TrashHandler {
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+	return &TrashHandler{
+		sensorService: sensorService,
+		auditService:  auditService,
+		chunkSize:     chunkSize,
+	}
+}
+
+// List godoc
+// @Summary List deleted sensor data
+// @Description List soft-deleted sensor data, most recently deleted first, with the filters of GET /sensors (admin only)
+// @Tags trash
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param sensor_type query string false "Sensor type filter, comma separated for several"
+// @Param id1 query string false "ID1 filter, comma separated for several"
+// @Param id2 query int false "ID2 filter"
+// @Param from_time query string false "From time filter (RFC3339)"
+// @Param to_time query string false "To time filter (RFC3339)"
+// @Param min_value query number false "Minimum value filter"
+// @Param max_value query number false "Maximum value filter"
+// @Param q query string false "Filter expression, e.g. sensor_type in (temperature,humidity) and value > 30"
+// @Success 200 {object} shared.APIResponse{data=dtos.PaginatedResponse{data=[]dtos.DeletedSensorData}}
+// @Failure 400 {object} shared.APIResponse "Invalid filter"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /sensors/trash [get]
+func (h *TrashHandler) List(c echo.Context) error {
+	filter, err := parseFilter(c)
+	if err != nil {
+		return invalidTrashRequest(c, err.Error())
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+
+	result, err := h.sensorService.ListDeletedSensorData(c.Request().Context(), filter, &dtos.PaginationParams{Page: page, PageSize: pageSize})
+	if err != nil {
+		return trashFailed(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: "Deleted sensor data retrieved successfully",
+		Data:    result,
+	})
+}
+
+// Restore godoc
+// @Summary Restore deleted sensor data
+// @Description Restore a soft-deleted reading (admin only)
+// @Tags trash
+// @Produce json
+// @Param id path int true "Sensor data ID"
+// @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 404 {object} shared.APIResponse "Not in the trash"
+// @Security Bearer
+// @Router /sensors/trash/{id}/restore [post]
+func (h *TrashHandler) Restore(c echo.Context) error {
+	return h.byID(c, auditActionRestore, h.sensorService.RestoreSensorData, "Sensor data restored successfully")
+}
+
+// Purge godoc
+// @Summary Purge deleted sensor data
+// @Description Permanently remove a soft-deleted reading (admin only)
+// @Tags trash
+// @Produce json
+// @Param id path int true "Sensor data ID"
+// @Success 200 {object} shared.APIResponse
+// @Failure 400 {object} shared.APIResponse "Invalid ID"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Failure 404 {object} shared.APIResponse "Not in the trash"
+// @Security Bearer
+// @Router /sensors/trash/{id} [delete]
+func (h *TrashHandler) Purge(c echo.Context) error {
+	return h.byID(c, auditActionPurge, h.sensorService.PurgeSensorData, "Sensor data purged successfully")
+}
+
+// RestoreByFilter godoc
+// @Summary Restore deleted sensor data by filter
+// @Description Restore the soft-deleted readings matching a filter; an empty filter restores the whole trash (admin only)
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param request body dtos.TrashRequest true "Filter"
+// @Success 200 {object} shared.APIResponse{data=dtos.TrashResult}
+// @Failure 400 {object} shared.APIResponse "Invalid filter"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /sensors/trash/restore [post]
+func (h *TrashHandler) RestoreByFilter(c echo.Context) error {
+	return h.byFilter(c, auditActionRestoreFilter, h.sensorService.RestoreSensorDataByFilter, "Sensor data restored successfully")
+}
+
+// PurgeByFilter godoc
+// @Summary Purge deleted sensor data by filter
+// @Description Permanently remove the soft-deleted readings matching a filter; an empty filter empties the trash (admin only)
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param request body dtos.TrashRequest true "Filter"
+// @Success 200 {object} shared.APIResponse{data=dtos.TrashResult}
+// @Failure 400 {object} shared.APIResponse "Invalid filter"
+// @Failure 403 {object} shared.APIResponse "Forbidden"
+// @Security Bearer
+// @Router /sensors/trash/purge [post]
+func (h *TrashHandler) PurgeByFilter(c echo.Context) error {
+	return h.byFilter(c, auditActionPurgeFilter, h.sensorService.PurgeSensorDataByFilter, "Sensor data purged successfully")
+}
+
+// byID applies a trash action to the reading of the id path parameter and audits it
+func (h *TrashHandler) byID(c echo.Context, action string, apply func(ctx context.Context, id uint) error, message string) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return invalidTrashRequest(c, "Invalid ID format")
+	}
+
+	ctx := c.Request().Context()
+	if err := apply(ctx, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, shared.APIResponse{
+				Status:  constants.StatusError,
+				Message: constants.ErrNotFound,
+				Error:   "Sensor data not found in the trash",
+			})
+		}
+		return trashFailed(c, err)
+	}
+
+	if err := h.auditService.Record(ctx, action, auditResourceSensorData, strconv.FormatUint(id, 10), nil); err != nil {
+		utils.Error(fmt.Sprintf("Failed to record %s of sensor data %d: %v", action, id, err))
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: message,
+		Data:    map[string]uint{"id": uint(id)},
+	})
+}
+
+// byFilter applies a trash action to the readings matching the filter of the request body and audits it
+func (h *TrashHandler) byFilter(c echo.Context, action string, apply func(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int) (int64, error), message string) error {
+	var req dtos.TrashRequest
+	if err := c.Bind(&req); err != nil {
+		return invalidTrashRequest(c, "Invalid request body")
+	}
+	if err := req.Filter.Validate(); err != nil {
+		return invalidTrashRequest(c, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	count, err := apply(ctx, &req.Filter, h.chunkSize)
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidQuery) {
+			return invalidTrashRequest(c, err.Error())
+		}
+		return trashFailed(c, err)
+	}
+
+	details := map[string]interface{}{"filter": req.Filter, "count": count}
+	if err := h.auditService.Record(ctx, action, auditResourceSensorData, "", details); err != nil {
+		utils.Error(fmt.Sprintf("Failed to record %s of %d sensor data records: %v", action, count, err))
+	}
+
+	return c.JSON(http.StatusOK, shared.APIResponse{
+		Status:  constants.StatusSuccess,
+		Message: message,
+		Data:    dtos.TrashResult{Count: count},
+	})
+}
+
+func invalidTrashRequest(c echo.Context, message string) error {
+	return c.JSON(http.StatusBadRequest, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInvalidRequest,
+		Error:   message,
+	})
+}
+
+func trashFailed(c echo.Context, err error) error {
+	return c.JSON(http.StatusInternalServerError, shared.APIResponse{
+		Status:  constants.StatusError,
+		Message: constants.ErrInternalServer,
+		Error:   err.Error(),
+	})
+}
@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pipeline"
+)
+
+// Import formats
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// ErrInvalidFile is returned when an upload cannot be read at all, as opposed to single invalid rows
+var ErrInvalidFile = errors.New("invalid import file")
+
+// DetectFormat picks the import format from an explicit format name, else the content type, else the file name
+func DetectFormat(name, contentType, filename string) (string, error) {
+	switch strings.ToLower(name) {
+	case FormatCSV, FormatNDJSON:
+		return strings.ToLower(name), nil
+	case "":
+	default:
+		return "", errors.New("unsupported format, use csv or ndjson")
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "text/csv", "application/csv":
+			return FormatCSV, nil
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			return FormatNDJSON, nil
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", errors.New("cannot tell the file format, set format to csv or ndjson")
+}
+
+// Importer stores uploaded readings in chunks through the sensor service, so imported rows go through
+// the same validation, duplicate detection and ingestion pipeline as live data
+type Importer struct {
+	sensorService interfaces.SensorServiceInterface
+	chunkSize     int
+}
+
+// NewImporter creates an importer writing chunkSize rows per batch
+func NewImporter(sensorService interfaces.SensorServiceInterface, chunkSize int) *Importer {
+	if chunkSize < 1 {
+		chunkSize = 500
+	}
+	return &Importer{
+		sensorService: sensorService,
+		chunkSize:     chunkSize,
+	}
+}
+
+// Import reads every row of r and stores the valid ones; a dry run only checks them. size is the upload
+// size if known. progress, if set, is called after every chunk. The report is returned even if the import
+// stops on an error, it then covers the rows handled so far.
+func (i *Importer) Import(ctx context.Context, format string, r io.Reader, size int64, dryRun bool, progress func(dtos.ImportCounts)) (*dtos.ImportReport, error) {
+	report := &dtos.ImportReport{DryRun: dryRun, Format: format, Issues: []dtos.ImportIssue{}}
+	report.TotalBytes = size
+
+	counter := &countingReader{reader: r}
+	reader, err := newRowReader(format, counter)
+	if err != nil {
+		return report, err
+	}
+
+	chunk := make([]*entities.SensorData, 0, i.chunkSize)
+	lines := make([]int, 0, i.chunkSize)
+	flush := func() error {
+		if len(chunk) > 0 {
+			if err := i.store(ctx, chunk, lines, dryRun, report); err != nil {
+				return err
+			}
+			chunk, lines = chunk[:0], lines[:0]
+		}
+		report.BytesRead = counter.count
+		if progress != nil {
+			progress(report.ImportCounts)
+		}
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		if row.Err != nil {
+			report.AddIssue(dtos.ImportIssue{Line: row.Line, Status: dtos.BatchItemRejected, Reason: row.Err.Error()})
+			continue
+		}
+
+		chunk = append(chunk, row.Data)
+		lines = append(lines, row.Line)
+		if len(chunk) == i.chunkSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, flush()
+}
+
+// store writes (or checks) a chunk and records the outcome of its rows, waiting while the ingestion queue is full
+func (i *Importer) store(ctx context.Context, chunk []*entities.SensorData, lines []int, dryRun bool, report *dtos.ImportReport) error {
+	for {
+		var result *dtos.BatchResult
+		var err error
+		if dryRun {
+			result, err = i.sensorService.CheckSensorDataBatch(ctx, chunk)
+		} else {
+			result, err = i.sensorService.CreateSensorDataBatch(ctx, chunk)
+		}
+
+		var queueFull *pipeline.QueueFullError
+		if errors.As(err, &queueFull) {
+			timer := time.NewTimer(queueFull.RetryAfter)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, item := range result.Items {
+			if item.Status == dtos.BatchItemAccepted {
+				report.Rows++
+				report.Accepted++
+				continue
+			}
+			report.AddIssue(dtos.ImportIssue{Line: lines[item.Index], Status: item.Status, Reason: item.Reason})
+		}
+		return nil
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
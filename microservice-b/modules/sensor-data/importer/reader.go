@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+)
+
+// maxLineSize bounds the length of an NDJSON line
+const maxLineSize = 1 << 20
+
+// timestampLayouts are the accepted timestamp formats, times without a zone are taken as UTC
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05"}
+
+// row is a parsed import row, Err is set when the row cannot be read
+type row struct {
+	Line int
+	Data *entities.SensorData
+	Err  error
+}
+
+// rowReader reads rows from an upload until io.EOF. Any other error means the file cannot be read further.
+type rowReader interface {
+	Next() (row, error)
+}
+
+func newRowReader(format string, r io.Reader) (rowReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q, use csv or ndjson", ErrInvalidFile, format)
+	}
+}
+
+// csvReader reads CSV files with a header row naming the columns. Columns are matched by name,
+// so files produced by the export can be imported as they are; unknown columns are ignored.
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// csvColumns are the columns read from a CSV file, id2 is optional
+var csvColumns = []string{"sensor_type", "id1", "id2", "sensor_value", "timestamp"}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok && name != "id2" {
+			return nil, fmt.Errorf("%w: missing column %s in the header", ErrInvalidFile, name)
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Next() (row, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return row{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return row{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return row{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	value := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	data := &entities.SensorData{SensorType: value("sensor_type"), ID1: value("id1")}
+	if data.SensorValue, err = strconv.ParseFloat(value("sensor_value"), 64); err != nil {
+		return row{Line: line, Err: fmt.Errorf("invalid sensor_value %q", value("sensor_value"))}, nil
+	}
+	if id2 := value("id2"); id2 != "" {
+		parsed, err := strconv.ParseInt(id2, 10, 32)
+		if err != nil {
+			return row{Line: line, Err: fmt.Errorf("invalid id2 %q", id2)}, nil
+		}
+		data.ID2 = int32(parsed)
+	}
+	if data.Timestamp, err = parseTimestamp(value("timestamp")); err != nil {
+		return row{Line: line, Err: err}, nil
+	}
+
+	return row{Line: line, Data: data}, nil
+}
+
+// ndjsonReader reads one JSON object per line, blank lines are skipped
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// ndjsonRow is an imported JSON object, unknown fields such as id are ignored
+type ndjsonRow struct {
+	SensorValue *float64 `json:"sensor_value"`
+	SensorType  string   `json:"sensor_type"`
+	ID1         string   `json:"id1"`
+	ID2         int32    `json:"id2"`
+	Timestamp   string   `json:"timestamp"`
+}
+
+func (r *ndjsonReader) Next() (row, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var item ndjsonRow
+		if err := json.Unmarshal([]byte(text), &item); err != nil {
+			return row{Line: r.line, Err: fmt.Errorf("invalid JSON: %v", err)}, nil
+		}
+		if item.SensorValue == nil {
+			return row{Line: r.line, Err: errors.New("sensor_value is required")}, nil
+		}
+		timestamp, err := parseTimestamp(item.Timestamp)
+		if err != nil {
+			return row{Line: r.line, Err: err}, nil
+		}
+
+		return row{Line: r.line, Data: &entities.SensorData{
+			SensorValue: *item.SensorValue,
+			SensorType:  item.SensorType,
+			ID1:         item.ID1,
+			ID2:         item.ID2,
+			Timestamp:   timestamp,
+		}}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return row{}, fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalidFile, r.line+1, maxLineSize)
+		}
+		return row{}, err
+	}
+	return row{}, io.EOF
+}
+
+// parseTimestamp reads an RFC3339 timestamp, or a date and time without zone in UTC
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("timestamp is required")
+	}
+	for _, layout := range timestampLayouts {
+		if timestamp, err := time.Parse(layout, value); err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q, use RFC3339", value)
+}
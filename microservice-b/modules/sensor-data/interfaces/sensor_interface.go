@@ -12,24 +12,58 @@ import (
 type SensorRepositoryInterface interface {
 	Create(ctx context.Context, data *entities.SensorData) error
 	CreateBatch(ctx context.Context, data []*entities.SensorData) error
+	FindExisting(ctx context.Context, data []*entities.SensorData) ([]*entities.SensorData, error)
 	GetByID(ctx context.Context, id uint) (*entities.SensorData, error)
 	GetByIDCombination(ctx context.Context, id1 string, id2 int32) ([]*entities.SensorData, error)
 	GetByDuration(ctx context.Context, from, to time.Time) ([]*entities.SensorData, error)
 	List(ctx context.Context, filter *dtos.SensorDataFilter, pagination *dtos.PaginationParams) ([]*entities.SensorData, int64, error)
-	Update(ctx context.Context, id uint, data *entities.SensorData) error
-	Delete(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error)
-	DeleteByID(ctx context.Context, id uint) error
+	ListByCursor(ctx context.Context, filter *dtos.SensorDataFilter, cursor *dtos.Cursor, order string, limit int) ([]*entities.SensorData, error)
+	Count(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error)
+	Aggregate(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.AggregateParams, limit int) ([]*dtos.AggregateBucket, error)
+	Export(ctx context.Context, filter *dtos.SensorDataFilter, batchSize int, fn func(batch []*entities.SensorData) error) error
+	Update(ctx context.Context, id uint, data *entities.SensorData, by entities.ChangeActor) error
+	DeleteChunk(ctx context.Context, filter *dtos.SensorDataFilter, limit int, by entities.ChangeActor) (int64, error)
+	DeleteByID(ctx context.Context, id uint, by entities.ChangeActor) error
+	ListHistory(ctx context.Context, id uint) ([]*entities.SensorDataHistory, error)
+	Revert(ctx context.Context, id, historyID uint, by entities.ChangeActor) (*entities.SensorData, error)
+	ListDeleted(ctx context.Context, filter *dtos.SensorDataFilter, page, pageSize int) ([]*entities.SensorData, int64, error)
+	FindDeletedIDs(ctx context.Context, filter *dtos.SensorDataFilter, limit int) ([]uint, error)
+	Restore(ctx context.Context, ids []uint) (int64, error)
+	Purge(ctx context.Context, ids []uint) (int64, error)
 }
 
 // SensorServiceInterface defines the interface for sensor service
 type SensorServiceInterface interface {
 	CreateSensorData(ctx context.Context, data *entities.SensorData) error
-	CreateSensorDataBatch(ctx context.Context, data []*entities.SensorData) error
+	CreateSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*dtos.BatchResult, error)
+	CheckSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*dtos.BatchResult, error)
 	GetSensorData(ctx context.Context, id uint) (*entities.SensorData, error)
 	GetSensorDataByIDCombination(ctx context.Context, id1 string, id2 int32) ([]*entities.SensorData, error)
 	GetSensorDataByDuration(ctx context.Context, from, to time.Time) ([]*entities.SensorData, error)
 	ListSensorData(ctx context.Context, filter *dtos.SensorDataFilter, pagination *dtos.PaginationParams) (*dtos.PaginatedResponse, error)
+	ListSensorDataByCursor(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.CursorParams) (*dtos.CursorPage, error)
+	AggregateSensorData(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.AggregateParams) ([]*dtos.AggregateBucket, error)
+	ExportSensorData(ctx context.Context, filter *dtos.SensorDataFilter, fn func(batch []*entities.SensorData) error) error
 	UpdateSensorData(ctx context.Context, id uint, data *entities.SensorData) error
+	CountSensorData(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error)
 	DeleteSensorData(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error)
+	DeleteSensorDataInChunks(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int, progress func(deleted int64)) (int64, error)
 	DeleteSensorDataByID(ctx context.Context, id uint) error
+	GetSensorDataHistory(ctx context.Context, id uint) ([]*entities.SensorDataHistory, error)
+	RevertSensorData(ctx context.Context, id, historyID uint) (*entities.SensorData, error)
+	ListDeletedSensorData(ctx context.Context, filter *dtos.SensorDataFilter, pagination *dtos.PaginationParams) (*dtos.PaginatedResponse, error)
+	RestoreSensorData(ctx context.Context, id uint) error
+	RestoreSensorDataByFilter(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int) (int64, error)
+	PurgeSensorData(ctx context.Context, id uint) error
+	PurgeSensorDataByFilter(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int) (int64, error)
+}
+
+// CacheInvalidator drops cached sensor data reads after changes made without the sensor service
+type CacheInvalidator interface {
+	InvalidateAll(ctx context.Context)
+}
+
+// SensorDataPublisher is notified with sensor data after it has been stored
+type SensorDataPublisher interface {
+	Publish(ctx context.Context, data []*entities.SensorData)
 }
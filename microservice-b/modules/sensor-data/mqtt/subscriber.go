@@ -0,0 +1,297 @@
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// Payload formats
+const (
+	PayloadAuto     = "auto"
+	PayloadJSON     = "json"
+	PayloadProtobuf = "protobuf"
+)
+
+// Config holds MQTT subscriber settings
+type Config struct {
+	BrokerURL     string
+	ClientID      string
+	Username      string
+	Password      string
+	Topics        []string // Topic patterns, e.g. sensors/{sensor_type}/{id1}/{id2}
+	SharedGroup   string   // Subscribe as $share/<group>/<filter> so replicas split the load
+	QoS           byte
+	PayloadFormat string
+	IngestTimeout time.Duration
+}
+
+// Subscriber consumes sensor readings from MQTT topics and ingests them through the sensor service.
+// With QoS 1 or 2 a message is only acknowledged once it has been stored or found invalid, so
+// readings that hit a transient failure are redelivered by the broker after a reconnect.
+type Subscriber struct {
+	cfg           Config
+	sensorService interfaces.SensorServiceInterface
+	patterns      []*topicPattern
+	client        paho.Client
+}
+
+// NewSubscriber validates the configuration and prepares a subscriber
+func NewSubscriber(sensorService interfaces.SensorServiceInterface, cfg Config) (*Subscriber, error) {
+	if cfg.BrokerURL == "" {
+		return nil, errors.New("mqtt broker url is required")
+	}
+	if cfg.QoS > 2 {
+		return nil, fmt.Errorf("invalid mqtt qos %d", cfg.QoS)
+	}
+	switch cfg.PayloadFormat {
+	case "":
+		cfg.PayloadFormat = PayloadAuto
+	case PayloadAuto, PayloadJSON, PayloadProtobuf:
+	default:
+		return nil, fmt.Errorf("unknown mqtt payload format %q", cfg.PayloadFormat)
+	}
+	if cfg.IngestTimeout <= 0 {
+		cfg.IngestTimeout = 10 * time.Second
+	}
+
+	var patterns []*topicPattern
+	for _, topic := range cfg.Topics {
+		pattern, err := parseTopicPattern(topic)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	if len(patterns) == 0 {
+		return nil, errors.New("at least one mqtt topic pattern is required")
+	}
+
+	return &Subscriber{
+		cfg:           cfg,
+		sensorService: sensorService,
+		patterns:      patterns,
+	}, nil
+}
+
+// Start connects to the broker in the background; subscriptions are (re)established on every connect
+func (s *Subscriber) Start() {
+	opts := paho.NewClientOptions().
+		AddBroker(s.cfg.BrokerURL).
+		SetClientID(s.cfg.ClientID).
+		SetUsername(s.cfg.Username).
+		SetPassword(s.cfg.Password).
+		// Keep the session so unacknowledged QoS 1/2 messages are redelivered after a reconnect
+		SetCleanSession(s.cfg.QoS == 0).
+		SetAutoAckDisabled(true).
+		SetOrderMatters(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetMaxReconnectInterval(time.Minute).
+		SetOnConnectHandler(s.onConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			utils.Warn(fmt.Sprintf("MQTT connection lost: %v", err))
+		}).
+		SetReconnectingHandler(func(_ paho.Client, _ *paho.ClientOptions) {
+			utils.Info("Reconnecting to MQTT broker")
+		})
+
+	s.client = paho.NewClient(opts)
+	s.client.Connect()
+	utils.Info(fmt.Sprintf("MQTT subscriber connecting to %s", s.cfg.BrokerURL))
+}
+
+// Close disconnects from the broker, giving in-flight handlers a moment to finish
+func (s *Subscriber) Close() {
+	if s.client != nil {
+		s.client.Disconnect(250)
+	}
+}
+
+// onConnect subscribes to every configured topic pattern
+func (s *Subscriber) onConnect(client paho.Client) {
+	for _, pattern := range s.patterns {
+		filter := pattern.Filter()
+		if s.cfg.SharedGroup != "" {
+			filter = "$share/" + s.cfg.SharedGroup + "/" + filter
+		}
+
+		token := client.Subscribe(filter, s.cfg.QoS, s.messageHandler(pattern))
+		if token.Wait() && token.Error() != nil {
+			utils.Error(fmt.Sprintf("MQTT subscribe to %s failed: %v", filter, token.Error()))
+			continue
+		}
+		utils.Info(fmt.Sprintf("MQTT subscribed to %s (QoS %d)", filter, s.cfg.QoS))
+	}
+}
+
+// messageHandler ingests messages received for a topic pattern
+func (s *Subscriber) messageHandler(pattern *topicPattern) paho.MessageHandler {
+	return func(_ paho.Client, message paho.Message) {
+		readings, err := s.decode(pattern, message)
+		if err != nil {
+			// Invalid messages are acknowledged so the broker does not redeliver them
+			utils.Warn(fmt.Sprintf("Discarding MQTT message on %s: %v", message.Topic(), err))
+			message.Ack()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.cfg.IngestTimeout)
+		defer cancel()
+
+		if err := s.ingest(ctx, message.Topic(), readings); err != nil {
+			utils.Error(fmt.Sprintf("Failed to store MQTT message on %s, leaving it unacknowledged: %v", message.Topic(), err))
+			return
+		}
+
+		message.Ack()
+	}
+}
+
+// ingest stores the readings of a message through the batch path, which skips readings already
+// stored so broker redeliveries are idempotent. Invalid items are logged and dropped; a retryable
+// rejection fails the whole message so the broker redelivers it.
+func (s *Subscriber) ingest(ctx context.Context, topic string, readings []*entities.SensorData) error {
+	result, err := s.sensorService.CreateSensorDataBatch(ctx, readings)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range result.Items {
+		if item.Retryable {
+			return fmt.Errorf("item %d: %s", item.Index, item.Reason)
+		}
+	}
+	if result.Rejected > 0 {
+		utils.Warn(fmt.Sprintf("MQTT message on %s partially stored: %d accepted, %d duplicates, %d rejected",
+			topic, result.Accepted, result.Duplicates, result.Rejected))
+	}
+	return nil
+}
+
+// decode converts a message into readings, filling sensor type and IDs from the topic
+func (s *Subscriber) decode(pattern *topicPattern, message paho.Message) ([]*entities.SensorData, error) {
+	fields, ok, err := pattern.Match(message.Topic())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("topic does not match %s", pattern.pattern)
+	}
+
+	messages, err := s.decodePayload(message.Payload())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	readings := make([]*entities.SensorData, 0, len(messages))
+	for _, data := range messages {
+		reading, err := toSensorData(data, fields, now)
+		if err != nil {
+			return nil, err
+		}
+		if err := reading.Validate(now); err != nil {
+			return nil, err
+		}
+		readings = append(readings, reading)
+	}
+	return readings, nil
+}
+
+// decodePayload parses a JSON object, JSON array or protobuf encoded SensorData payload
+func (s *Subscriber) decodePayload(payload []byte) ([]*pb.SensorData, error) {
+	trimmed := bytes.TrimSpace(payload)
+	if len(trimmed) == 0 {
+		return nil, errors.New("empty payload")
+	}
+
+	format := s.cfg.PayloadFormat
+	if format == PayloadAuto {
+		format = PayloadProtobuf
+		if trimmed[0] == '{' || trimmed[0] == '[' {
+			format = PayloadJSON
+		}
+	}
+
+	if format == PayloadProtobuf {
+		var data pb.SensorData
+		if err := proto.Unmarshal(payload, &data); err != nil {
+			return nil, fmt.Errorf("invalid protobuf payload: %v", err)
+		}
+		return []*pb.SensorData{&data}, nil
+	}
+
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if trimmed[0] != '[' {
+		var data pb.SensorData
+		if err := unmarshaler.Unmarshal(trimmed, &data); err != nil {
+			return nil, fmt.Errorf("invalid json payload: %v", err)
+		}
+		return []*pb.SensorData{&data}, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, fmt.Errorf("invalid json payload: %v", err)
+	}
+	messages := make([]*pb.SensorData, 0, len(items))
+	for i, item := range items {
+		var data pb.SensorData
+		if err := unmarshaler.Unmarshal(item, &data); err != nil {
+			return nil, fmt.Errorf("invalid json payload item %d: %v", i, err)
+		}
+		messages = append(messages, &data)
+	}
+	return messages, nil
+}
+
+// toSensorData merges a decoded payload with the topic fields. Topic values fill missing payload
+// fields and must agree with the payload when both are set; a missing timestamp means "now".
+func toSensorData(data *pb.SensorData, fields topicFields, now time.Time) (*entities.SensorData, error) {
+	reading := &entities.SensorData{
+		SensorValue: data.SensorValue,
+		SensorType:  data.SensorType,
+		ID1:         data.Id1,
+		ID2:         data.Id2,
+		Timestamp:   now,
+	}
+	if data.Timestamp != nil {
+		reading.Timestamp = data.Timestamp.AsTime()
+	}
+
+	if fields.SensorType != "" {
+		if reading.SensorType != "" && !strings.EqualFold(reading.SensorType, fields.SensorType) {
+			return nil, fmt.Errorf("payload sensor_type %q does not match topic %q", reading.SensorType, fields.SensorType)
+		}
+		reading.SensorType = fields.SensorType
+	}
+	if fields.ID1 != "" {
+		if reading.ID1 != "" && reading.ID1 != fields.ID1 {
+			return nil, fmt.Errorf("payload id1 %q does not match topic %q", reading.ID1, fields.ID1)
+		}
+		reading.ID1 = fields.ID1
+	}
+	if fields.ID2 != nil {
+		if reading.ID2 != 0 && reading.ID2 != *fields.ID2 {
+			return nil, fmt.Errorf("payload id2 %d does not match topic %d", reading.ID2, *fields.ID2)
+		}
+		reading.ID2 = *fields.ID2
+	}
+
+	return reading, nil
+}
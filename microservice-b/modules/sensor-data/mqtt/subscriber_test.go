@@ -0,0 +1,326 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	pb "github.com/worlder-team/microservice-server/shared/proto/sensor"
+)
+
+const (
+	testClientID = "sensor-subscriber-test"
+	testPattern  = "sensors/{sensor_type}/{id1}/{id2}"
+	testTopic    = "sensors/temperature/device-1/1"
+)
+
+// fakeSensorService records stored batches; failures and block let tests control the outcome of a store
+type fakeSensorService struct {
+	interfaces.SensorServiceInterface
+
+	mu       sync.Mutex
+	calls    int
+	failures int
+	block    chan struct{}
+	stored   []*entities.SensorData
+}
+
+func (f *fakeSensorService) CreateSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*dtos.BatchResult, error) {
+	f.mu.Lock()
+	f.calls++
+	block := f.block
+	f.mu.Unlock()
+
+	if block != nil {
+		select {
+		case <-block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("database unavailable")
+	}
+
+	result := &dtos.BatchResult{}
+	for i := range data {
+		result.Add(dtos.BatchItemResult{Index: i, Status: dtos.BatchItemAccepted})
+	}
+	f.stored = append(f.stored, data...)
+	return result, nil
+}
+
+func (f *fakeSensorService) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *fakeSensorService) Stored() []*entities.SensorData {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*entities.SensorData(nil), f.stored...)
+}
+
+// startBroker runs an in-process broker on a random local port and returns it with its URL
+func startBroker(t *testing.T) (*mochi.Server, string) {
+	t.Helper()
+
+	server := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatalf("add auth hook: %v", err)
+	}
+
+	listener := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	if err := server.AddListener(listener); err != nil {
+		t.Fatalf("add listener: %v", err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	return server, "tcp://" + listener.Address()
+}
+
+// startSubscriber connects a subscriber for testPattern and waits until it has subscribed
+func startSubscriber(t *testing.T, server *mochi.Server, brokerURL string, service *fakeSensorService, qos byte, format string) *Subscriber {
+	t.Helper()
+
+	subscriber, err := NewSubscriber(service, Config{
+		BrokerURL:     brokerURL,
+		ClientID:      testClientID,
+		Topics:        []string{testPattern},
+		QoS:           qos,
+		PayloadFormat: format,
+		IngestTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("new subscriber: %v", err)
+	}
+	subscriber.Start()
+	t.Cleanup(subscriber.Close)
+
+	waitSubscribed(t, server)
+	return subscriber
+}
+
+// waitSubscribed waits until the test client is subscribed to testTopic on the broker
+func waitSubscribed(t *testing.T, server *mochi.Server) {
+	t.Helper()
+	eventually(t, "subscription", func() bool {
+		return len(server.Topics.Subscribers(testTopic).Subscriptions) > 0
+	})
+}
+
+// eventually polls condition until it holds or the test times out
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// inflight returns the number of messages the broker is waiting to have acknowledged by the test client
+func inflight(server *mochi.Server) int {
+	client, ok := server.Clients.Get(testClientID)
+	if !ok {
+		return 0
+	}
+	return client.State.Inflight.Len()
+}
+
+// disconnect drops the test client's connection from the broker side
+func disconnect(t *testing.T, server *mochi.Server) {
+	t.Helper()
+
+	client, ok := server.Clients.Get(testClientID)
+	if !ok {
+		t.Fatal("test client is not connected")
+	}
+	client.Stop(errors.New("test disconnect"))
+	eventually(t, "client disconnect", func() bool { return client.Closed() })
+}
+
+func publish(t *testing.T, server *mochi.Server, topic string, payload []byte, qos byte) {
+	t.Helper()
+	if err := server.Publish(topic, payload, false, qos); err != nil {
+		t.Fatalf("publish to %s: %v", topic, err)
+	}
+}
+
+func TestSubscriberStoresJSONPayloadWithTopicFields(t *testing.T) {
+	server, url := startBroker(t)
+	service := &fakeSensorService{}
+	startSubscriber(t, server, url, service, 1, PayloadAuto)
+
+	timestamp := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	payload := `[{"sensor_value": 21.5, "timestamp": "` + timestamp.Format(time.RFC3339) + `"}, {"sensor_value": 22}]`
+	publish(t, server, "sensors/temperature/device-1/7", []byte(payload), 1)
+
+	eventually(t, "stored readings", func() bool { return len(service.Stored()) == 2 })
+
+	stored := service.Stored()
+	for _, reading := range stored {
+		if reading.SensorType != "temperature" || reading.ID1 != "device-1" || reading.ID2 != 7 {
+			t.Errorf("reading fields = %s/%s/%d, want temperature/device-1/7", reading.SensorType, reading.ID1, reading.ID2)
+		}
+	}
+	if stored[0].SensorValue != 21.5 || !stored[0].Timestamp.Equal(timestamp) {
+		t.Errorf("first reading = %g at %s, want 21.5 at %s", stored[0].SensorValue, stored[0].Timestamp, timestamp)
+	}
+	if stored[1].SensorValue != 22 || stored[1].Timestamp.IsZero() {
+		t.Errorf("second reading = %g at %s, want 22 at the receive time", stored[1].SensorValue, stored[1].Timestamp)
+	}
+}
+
+func TestSubscriberStoresProtobufPayload(t *testing.T) {
+	server, url := startBroker(t)
+	service := &fakeSensorService{}
+	startSubscriber(t, server, url, service, 1, PayloadAuto)
+
+	timestamp := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	payload, err := proto.Marshal(&pb.SensorData{
+		SensorValue: 48.25,
+		SensorType:  "humidity",
+		Timestamp:   timestamppb.New(timestamp),
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	publish(t, server, "sensors/humidity/device-2/3", payload, 1)
+
+	eventually(t, "stored reading", func() bool { return len(service.Stored()) == 1 })
+
+	reading := service.Stored()[0]
+	if reading.SensorType != "humidity" || reading.ID1 != "device-2" || reading.ID2 != 3 {
+		t.Errorf("reading fields = %s/%s/%d, want humidity/device-2/3", reading.SensorType, reading.ID1, reading.ID2)
+	}
+	if reading.SensorValue != 48.25 || !reading.Timestamp.Equal(timestamp) {
+		t.Errorf("reading = %g at %s, want 48.25 at %s", reading.SensorValue, reading.Timestamp, timestamp)
+	}
+}
+
+func TestSubscriberDiscardsPayloadConflictingWithTopic(t *testing.T) {
+	server, url := startBroker(t)
+	service := &fakeSensorService{}
+	startSubscriber(t, server, url, service, 1, PayloadJSON)
+
+	publish(t, server, testTopic, []byte(`{"sensor_value": 20, "id1": "device-9"}`), 1)
+	publish(t, server, "sensors/temperature/device-1/not-a-number", []byte(`{"sensor_value": 20}`), 1)
+
+	// Invalid messages are acknowledged without being stored
+	eventually(t, "acknowledged messages", func() bool { return inflight(server) == 0 })
+	if calls := service.Calls(); calls != 0 {
+		t.Errorf("store calls = %d, want 0", calls)
+	}
+}
+
+func TestSubscriberAcknowledgesOnlyAfterStore(t *testing.T) {
+	server, url := startBroker(t)
+	service := &fakeSensorService{block: make(chan struct{})}
+	startSubscriber(t, server, url, service, 1, PayloadJSON)
+
+	publish(t, server, testTopic, []byte(`{"sensor_value": 20}`), 1)
+
+	eventually(t, "store call", func() bool { return service.Calls() == 1 })
+	if n := inflight(server); n != 1 {
+		t.Fatalf("inflight while storing = %d, want 1", n)
+	}
+
+	close(service.block)
+	eventually(t, "acknowledgement", func() bool { return inflight(server) == 0 })
+	if n := len(service.Stored()); n != 1 {
+		t.Errorf("stored readings = %d, want 1", n)
+	}
+}
+
+func TestSubscriberRedeliversUnstoredMessageAfterReconnect(t *testing.T) {
+	server, url := startBroker(t)
+	service := &fakeSensorService{failures: 1}
+	startSubscriber(t, server, url, service, 1, PayloadJSON)
+
+	publish(t, server, testTopic, []byte(`{"sensor_value": 20}`), 1)
+
+	eventually(t, "failed store", func() bool { return service.Calls() == 1 })
+	if n := inflight(server); n != 1 {
+		t.Fatalf("inflight after failed store = %d, want 1", n)
+	}
+
+	// The persistent session makes the broker resend the unacknowledged message on reconnect
+	disconnect(t, server)
+	eventually(t, "redelivered reading", func() bool { return len(service.Stored()) == 1 })
+	eventually(t, "acknowledgement", func() bool { return inflight(server) == 0 })
+}
+
+func TestSubscriberResubscribesAfterReconnect(t *testing.T) {
+	server, url := startBroker(t)
+	subscribes := &subscribeCounter{}
+	if err := server.AddHook(subscribes, nil); err != nil {
+		t.Fatalf("add subscribe hook: %v", err)
+	}
+	service := &fakeSensorService{}
+	// QoS 0 uses a clean session, so the broker forgets the subscription when the client goes away
+	startSubscriber(t, server, url, service, 0, PayloadJSON)
+
+	disconnect(t, server)
+	eventually(t, "resubscription", func() bool { return subscribes.Count() == 2 })
+	waitSubscribed(t, server)
+
+	publish(t, server, testTopic, []byte(`{"sensor_value": 20}`), 0)
+	eventually(t, "stored reading", func() bool { return len(service.Stored()) == 1 })
+}
+
+// subscribeCounter counts the subscribe packets handled by the broker
+type subscribeCounter struct {
+	mochi.HookBase
+
+	mu    sync.Mutex
+	count int
+}
+
+func (h *subscribeCounter) ID() string {
+	return "subscribe-counter"
+}
+
+func (h *subscribeCounter) Provides(b byte) bool {
+	return b == mochi.OnSubscribed
+}
+
+func (h *subscribeCounter) OnSubscribed(*mochi.Client, packets.Packet, []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.count++
+}
+
+func (h *subscribeCounter) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
@@ -0,0 +1,98 @@
+package mqtt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Placeholders that may appear as whole levels in a topic pattern
+const (
+	placeholderSensorType = "{sensor_type}"
+	placeholderID1        = "{id1}"
+	placeholderID2        = "{id2}"
+)
+
+// topicFields holds the reading fields extracted from a topic
+type topicFields struct {
+	SensorType string
+	ID1        string
+	ID2        *int32
+}
+
+// topicPattern matches topics such as sensors/{sensor_type}/{id1}/{id2}
+type topicPattern struct {
+	pattern string
+	levels  []string
+}
+
+// parseTopicPattern validates a pattern; placeholders must span a whole topic level
+func parseTopicPattern(pattern string) (*topicPattern, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil, fmt.Errorf("empty topic pattern")
+	}
+
+	levels := strings.Split(pattern, "/")
+	for i, level := range levels {
+		if level == "#" && i != len(levels)-1 {
+			return nil, fmt.Errorf("topic pattern %q: # must be the last level", pattern)
+		}
+		if strings.ContainsAny(level, "{}") && !isPlaceholder(level) {
+			return nil, fmt.Errorf("topic pattern %q: unknown placeholder %q", pattern, level)
+		}
+	}
+
+	return &topicPattern{pattern: pattern, levels: levels}, nil
+}
+
+// Filter returns the MQTT subscription filter with placeholders replaced by single-level wildcards
+func (p *topicPattern) Filter() string {
+	levels := make([]string, len(p.levels))
+	for i, level := range p.levels {
+		if isPlaceholder(level) {
+			level = "+"
+		}
+		levels[i] = level
+	}
+	return strings.Join(levels, "/")
+}
+
+// Match extracts the placeholder values from topic, ok is false when the topic does not fit the pattern
+func (p *topicPattern) Match(topic string) (fields topicFields, ok bool, err error) {
+	levels := strings.Split(topic, "/")
+	for i, level := range p.levels {
+		if level == "#" {
+			return fields, true, nil
+		}
+		if i >= len(levels) {
+			return fields, false, nil
+		}
+
+		switch level {
+		case placeholderSensorType:
+			fields.SensorType = levels[i]
+		case placeholderID1:
+			fields.ID1 = levels[i]
+		case placeholderID2:
+			id2, err := strconv.ParseInt(levels[i], 10, 32)
+			if err != nil {
+				return fields, true, fmt.Errorf("invalid id2 %q in topic %s", levels[i], topic)
+			}
+			value := int32(id2)
+			fields.ID2 = &value
+		case "+":
+		default:
+			if level != levels[i] {
+				return fields, false, nil
+			}
+		}
+	}
+
+	return fields, len(levels) == len(p.levels), nil
+}
+
+// isPlaceholder reports whether a topic level is a supported placeholder
+func isPlaceholder(level string) bool {
+	return level == placeholderSensorType || level == placeholderID1 || level == placeholderID2
+}
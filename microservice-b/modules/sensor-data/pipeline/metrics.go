@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	queueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sensor_ingest_queue_depth",
+		Help: "Write requests waiting in the ingestion queue.",
+	})
+	queueCapacity = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sensor_ingest_queue_capacity",
+		Help: "Maximum write requests the ingestion queue holds.",
+	})
+	rejectedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sensor_ingest_rejected_total",
+		Help: "Write requests rejected because the ingestion queue was full.",
+	})
+	flushDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "sensor_ingest_flush_duration_seconds",
+		Help:    "Time taken to write a coalesced batch to the database.",
+		Buckets: prometheus.DefBuckets,
+	})
+	flushRows = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "sensor_ingest_flush_rows",
+		Help:    "Rows written per coalesced batch.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 11),
+	})
+	rowsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sensor_ingest_rows_total",
+		Help: "Rows processed by the ingestion pipeline by result.",
+	}, []string{"result"})
+)
+
+// recordRows counts rows as stored or failed
+func recordRows(count int, err error) {
+	result := "stored"
+	if err != nil {
+		result = "failed"
+	}
+	rowsTotal.WithLabelValues(result).Add(float64(count))
+}
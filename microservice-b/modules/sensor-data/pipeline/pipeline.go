@@ -0,0 +1,214 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+)
+
+// ErrQueueFull is matched by QueueFullError with errors.Is
+var ErrQueueFull = errors.New("ingestion queue is full")
+
+// ErrClosed is returned for writes submitted after the pipeline was closed
+var ErrClosed = errors.New("ingestion pipeline closed")
+
+// QueueFullError is returned when a write cannot be queued, RetryAfter hints when to try again
+type QueueFullError struct {
+	RetryAfter time.Duration
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrQueueFull, e.RetryAfter)
+}
+
+// Is makes errors.Is(err, ErrQueueFull) match
+func (e *QueueFullError) Is(target error) bool {
+	return target == ErrQueueFull
+}
+
+// Config holds ingestion pipeline settings
+type Config struct {
+	QueueSize     int           // Maximum queued write requests
+	Workers       int           // Concurrent writers, bounds the database connections used for ingestion
+	MaxBatchSize  int           // Maximum rows coalesced into one insert
+	FlushInterval time.Duration // How long a worker waits for more requests before flushing
+	RetryAfter    time.Duration // Retry hint returned when the queue is full
+	WriteTimeout  time.Duration // Timeout for a single flush
+}
+
+// writeRequest is a queued Create or CreateBatch call
+type writeRequest struct {
+	data []*entities.SensorData
+	done chan error
+}
+
+// Pipeline is a sensor repository decorator that funnels writes through a bounded queue.
+// A fixed pool of workers coalesces queued writes into multi-row inserts; callers block until
+// their rows are flushed. Reads go straight to the wrapped repository.
+type Pipeline struct {
+	interfaces.SensorRepositoryInterface
+	cfg    Config
+	queue  chan *writeRequest
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewPipeline wraps repo with an ingestion pipeline and starts its workers
+func NewPipeline(repo interfaces.SensorRepositoryInterface, cfg Config) *Pipeline {
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxBatchSize < 1 {
+		cfg.MaxBatchSize = 1
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 30 * time.Second
+	}
+
+	p := &Pipeline{
+		SensorRepositoryInterface: repo,
+		cfg:                       cfg,
+		queue:                     make(chan *writeRequest, cfg.QueueSize),
+	}
+
+	queueCapacity.Set(float64(cfg.QueueSize))
+	for i := 0; i < cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+
+	return p
+}
+
+// Create queues a single reading and waits until it has been stored
+func (p *Pipeline) Create(ctx context.Context, data *entities.SensorData) error {
+	return p.submit(ctx, []*entities.SensorData{data})
+}
+
+// CreateBatch queues a batch of readings and waits until they have been stored
+func (p *Pipeline) CreateBatch(ctx context.Context, data []*entities.SensorData) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return p.submit(ctx, data)
+}
+
+// Close stops accepting writes, flushes everything already queued and waits for the workers
+func (p *Pipeline) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// submit queues a write without blocking and waits for its result.
+// If ctx ends first the write may still be applied.
+func (p *Pipeline) submit(ctx context.Context, data []*entities.SensorData) error {
+	request := &writeRequest{
+		data: data,
+		done: make(chan error, 1),
+	}
+
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrClosed
+	}
+	select {
+	case p.queue <- request:
+		queueDepth.Set(float64(len(p.queue)))
+	default:
+		p.mu.RUnlock()
+		rejectedTotal.Inc()
+		return &QueueFullError{RetryAfter: p.cfg.RetryAfter}
+	}
+	p.mu.RUnlock()
+
+	select {
+	case err := <-request.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker coalesces queued requests into batches until the queue is closed
+func (p *Pipeline) worker() {
+	defer p.wg.Done()
+
+	for request := range p.queue {
+		batch := []*writeRequest{request}
+		rows := len(request.data)
+
+		linger := time.NewTimer(p.cfg.FlushInterval)
+	collect:
+		for rows < p.cfg.MaxBatchSize {
+			select {
+			case next, ok := <-p.queue:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+				rows += len(next.data)
+			case <-linger.C:
+				break collect
+			}
+		}
+		linger.Stop()
+
+		queueDepth.Set(float64(len(p.queue)))
+		p.flush(batch)
+	}
+}
+
+// flush writes a batch of requests in one insert. If that fails each request is retried on its
+// own, so one bad request does not fail the others coalesced with it.
+func (p *Pipeline) flush(batch []*writeRequest) {
+	var rows []*entities.SensorData
+	for _, request := range batch {
+		rows = append(rows, request.data...)
+	}
+
+	started := time.Now()
+	err := p.write(rows)
+	flushDuration.Observe(time.Since(started).Seconds())
+	flushRows.Observe(float64(len(rows)))
+
+	if err == nil || len(batch) == 1 {
+		recordRows(len(rows), err)
+		for _, request := range batch {
+			request.done <- err
+		}
+		return
+	}
+
+	for _, request := range batch {
+		for _, item := range request.data {
+			item.ID = 0
+		}
+		err := p.write(request.data)
+		recordRows(len(request.data), err)
+		request.done <- err
+	}
+}
+
+// write inserts rows through the wrapped repository
+func (p *Pipeline) write(rows []*entities.SensorData) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.WriteTimeout)
+	defer cancel()
+
+	return p.SensorRepositoryInterface.CreateBatch(ctx, rows)
+}
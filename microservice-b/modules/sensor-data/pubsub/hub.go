@@ -0,0 +1,258 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// SlowConsumerPolicy decides what happens when a subscriber's buffer is full
+type SlowConsumerPolicy string
+
+const (
+	// PolicyDropOldest discards the oldest buffered reading to make room for the new one
+	PolicyDropOldest SlowConsumerPolicy = "drop_oldest"
+	// PolicyDisconnect closes the subscription
+	PolicyDisconnect SlowConsumerPolicy = "disconnect"
+)
+
+// ErrSlowConsumer is reported by subscriptions closed under PolicyDisconnect
+var ErrSlowConsumer = errors.New("subscriber could not keep up with incoming sensor data")
+
+// ErrHubClosed is reported by subscriptions closed because the hub shut down
+var ErrHubClosed = errors.New("sensor data hub closed")
+
+// ParseSlowConsumerPolicy parses a policy name, falling back to PolicyDropOldest
+func ParseSlowConsumerPolicy(value string) SlowConsumerPolicy {
+	if SlowConsumerPolicy(value) == PolicyDisconnect {
+		return PolicyDisconnect
+	}
+	return PolicyDropOldest
+}
+
+// Filter selects the readings delivered to a subscriber, empty fields match everything
+type Filter struct {
+	SensorTypes []string
+	ID1s        []string
+	ID2s        []int32
+	MinValue    *float64
+	MaxValue    *float64
+}
+
+// Matches reports whether a reading satisfies the filter
+func (f *Filter) Matches(data *entities.SensorData) bool {
+	if len(f.SensorTypes) > 0 && !utils.Contains(f.SensorTypes, data.SensorType) {
+		return false
+	}
+	if len(f.ID1s) > 0 && !utils.Contains(f.ID1s, data.ID1) {
+		return false
+	}
+	if len(f.ID2s) > 0 && !containsInt32(f.ID2s, data.ID2) {
+		return false
+	}
+	if f.MinValue != nil && data.SensorValue < *f.MinValue {
+		return false
+	}
+	if f.MaxValue != nil && data.SensorValue > *f.MaxValue {
+		return false
+	}
+	return true
+}
+
+// SubscribeOptions overrides the hub defaults for a single subscription
+type SubscribeOptions struct {
+	BufferSize int
+	Policy     SlowConsumerPolicy
+}
+
+// Subscription delivers matching readings to a single consumer
+type Subscription struct {
+	id      uint64
+	filter  Filter
+	policy  SlowConsumerPolicy
+	ch      chan *entities.SensorData
+	done    chan struct{}
+	once    sync.Once
+	err     error
+	dropped atomic.Uint64
+}
+
+// C returns the channel readings are delivered on
+func (s *Subscription) C() <-chan *entities.SensorData {
+	return s.ch
+}
+
+// Done is closed when the subscription has been terminated by the hub
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns why the subscription was terminated, valid once Done is closed
+func (s *Subscription) Err() error {
+	<-s.done
+	return s.err
+}
+
+// Dropped returns how many readings were discarded under PolicyDropOldest
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// close terminates the subscription with the given reason
+func (s *Subscription) close(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+// deliver hands a reading to the subscriber without blocking the publisher.
+// It returns false when the subscriber must be disconnected.
+func (s *Subscription) deliver(data *entities.SensorData) bool {
+	select {
+	case s.ch <- data:
+		return true
+	default:
+	}
+
+	if s.policy == PolicyDisconnect {
+		return false
+	}
+
+	// Drop the oldest buffered reading and retry once; losing the race to another
+	// publisher only means this reading is dropped instead.
+	select {
+	case <-s.ch:
+		s.dropped.Add(1)
+	default:
+	}
+	select {
+	case s.ch <- data:
+	default:
+		s.dropped.Add(1)
+	}
+	return true
+}
+
+// Hub fans out ingested sensor data to in-process subscribers
+type Hub struct {
+	mu            sync.RWMutex
+	subscribers   map[uint64]*Subscription
+	nextID        uint64
+	bufferSize    int
+	defaultPolicy SlowConsumerPolicy
+	closed        bool
+}
+
+// NewHub creates a hub with the default per-subscriber buffer size and slow consumer policy
+func NewHub(bufferSize int, policy SlowConsumerPolicy) *Hub {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	return &Hub{
+		subscribers:   make(map[uint64]*Subscription),
+		bufferSize:    bufferSize,
+		defaultPolicy: policy,
+	}
+}
+
+// Subscribe registers a subscriber for readings matching the filter
+func (h *Hub) Subscribe(filter Filter, opts SubscribeOptions) *Subscription {
+	bufferSize := opts.BufferSize
+	if bufferSize < 1 || bufferSize > h.bufferSize {
+		bufferSize = h.bufferSize
+	}
+	policy := opts.Policy
+	if policy == "" {
+		policy = h.defaultPolicy
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	sub := &Subscription{
+		id:     h.nextID,
+		filter: filter,
+		policy: policy,
+		ch:     make(chan *entities.SensorData, bufferSize),
+		done:   make(chan struct{}),
+	}
+
+	if h.closed {
+		sub.close(ErrHubClosed)
+		return sub
+	}
+
+	h.subscribers[sub.id] = sub
+	return sub
+}
+
+// Unsubscribe removes a subscriber from the hub
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	delete(h.subscribers, sub.id)
+	h.mu.Unlock()
+
+	sub.close(nil)
+}
+
+// Publish delivers readings to every matching subscriber without blocking on slow consumers
+func (h *Hub) Publish(ctx context.Context, data []*entities.SensorData) {
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for _, sub := range h.subscribers {
+		for _, item := range data {
+			if !sub.filter.Matches(item) {
+				continue
+			}
+			if !sub.deliver(item) {
+				slow = append(slow, sub)
+				break
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		h.mu.Lock()
+		delete(h.subscribers, sub.id)
+		h.mu.Unlock()
+		sub.close(ErrSlowConsumer)
+	}
+}
+
+// SubscriberCount returns the number of active subscribers
+func (h *Hub) SubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
+
+// Close terminates every subscription
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for id, sub := range h.subscribers {
+		delete(h.subscribers, id)
+		sub.close(ErrHubClosed)
+	}
+}
+
+// containsInt32 checks if slice contains element
+func containsInt32(slice []int32, item int32) bool {
+	for _, value := range slice {
+		if value == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// Broadcaster publishes stored sensor data on a Redis pub/sub channel so that the hubs of every replica,
+// fed by a Relay, deliver it. It takes the place of the local hub among the sensor data publishers.
+type Broadcaster struct {
+	client  *redis.Client
+	channel string
+	timeout time.Duration
+}
+
+// NewBroadcaster creates a broadcaster publishing on channel
+func NewBroadcaster(client *redis.Client, channel string, timeout time.Duration) *Broadcaster {
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	return &Broadcaster{client: client, channel: channel, timeout: timeout}
+}
+
+// Publish sends the readings as one message
+func (b *Broadcaster) Publish(ctx context.Context, data []*entities.SensorData) {
+	if len(data) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		utils.Error(fmt.Sprintf("Failed to encode %d sensor readings for channel %s: %v", len(data), b.channel, err))
+		return
+	}
+
+	ctx, cancel := utils.DetachedContext(ctx, b.timeout)
+	defer cancel()
+
+	if err := b.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		utils.Error(fmt.Sprintf("Failed to publish %d sensor readings to channel %s: %v", len(data), b.channel, err))
+	}
+}
+
+// Relay subscribes to the Broadcaster channel and publishes the readings to the local hub, including
+// the ones this replica broadcast. The subscription reconnects by itself after Redis failures; readings
+// published meanwhile are not delivered.
+type Relay struct {
+	client  *redis.Client
+	channel string
+	hub     *Hub
+	sub     *redis.PubSub
+	wg      sync.WaitGroup
+}
+
+// NewRelay creates a relay from channel to hub
+func NewRelay(client *redis.Client, channel string, hub *Hub) *Relay {
+	return &Relay{client: client, channel: channel, hub: hub}
+}
+
+// Start subscribes to the channel and relays messages in the background
+func (r *Relay) Start() {
+	r.sub = r.client.Subscribe(context.Background(), r.channel)
+	messages := r.sub.Channel()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for message := range messages {
+			var data []*entities.SensorData
+			if err := json.Unmarshal([]byte(message.Payload), &data); err != nil {
+				utils.Warn(fmt.Sprintf("Dropping malformed message on channel %s: %v", r.channel, err))
+				continue
+			}
+			r.hub.Publish(context.Background(), data)
+		}
+	}()
+}
+
+// Close unsubscribes and waits for the relay to stop
+func (r *Relay) Close() {
+	if r.sub == nil {
+		return
+	}
+	if err := r.sub.Close(); err != nil {
+		utils.Warn(fmt.Sprintf("Failed to close subscription to channel %s: %v", r.channel, err))
+	}
+	r.wg.Wait()
+}
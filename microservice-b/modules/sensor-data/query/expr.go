@@ -0,0 +1,378 @@
+package query
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Condition is a parameterized SQL condition
+type Condition struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Compile parses a filter expression and compiles it to a parameterized SQL condition.
+//
+// An expression combines comparisons with and, or, not and parentheses:
+//
+//	sensor_type in (temperature, humidity) and value > 30
+//	id1 ~ "A1*" and not (id2 = 7 or timestamp < 2024-01-01T00:00:00Z)
+//
+// Fields are sensor_type, id1, id2, value (or sensor_value) and timestamp (RFC3339). All fields support
+// =, !=, in (...) and not in (...); id2, value and timestamp also <, <=, > and >=. The labels sensor_type
+// and id1 can be matched against glob patterns with ~ and !~, where * matches any characters and ? one.
+// Values containing spaces or operator characters are quoted with " or '.
+func Compile(expression string) (*Condition, error) {
+	if len(expression) > MaxExpressionLength {
+		return nil, invalid("expression is longer than %d characters", MaxExpressionLength)
+	}
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, invalid("unexpected %s", next)
+	}
+	return condition, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// keyword reports whether t is the unquoted keyword word, ignoring case
+func (t token) keyword(word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+// operatorChars start comparison operators and end unquoted words
+const operatorChars = "=!<>~"
+
+// tokenize splits an expression into tokens
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ","})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, invalid("unterminated string starting at %s", string(runes[i:]))
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end])})
+			i = end + 1
+		case strings.ContainsRune(operatorChars, r):
+			text := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != '~' {
+				text += "="
+			} else if r == '!' && i+1 < len(runes) && runes[i+1] == '~' {
+				text = "!~"
+			}
+			if text == "!" {
+				return nil, invalid("unknown operator %q", text)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: text})
+			i += len([]rune(text))
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(operatorChars+"(),\"'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:end])})
+			i = end
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+type parser struct {
+	tokens     []token
+	pos        int
+	depth      int
+	conditions int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// parseOr parses conditions joined by or
+func (p *parser) parseOr() (*Condition, error) {
+	return p.parseJoined("or", " OR ", p.parseAnd)
+}
+
+// parseAnd parses conditions joined by and
+func (p *parser) parseAnd() (*Condition, error) {
+	return p.parseJoined("and", " AND ", p.parseNot)
+}
+
+func (p *parser) parseJoined(keyword, operator string, operand func() (*Condition, error)) (*Condition, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{first.SQL}
+	args := first.Args
+	for p.peek().keyword(keyword) {
+		p.next()
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, next.SQL)
+		args = append(args, next.Args...)
+	}
+	if len(parts) == 1 {
+		return first, nil
+	}
+	return &Condition{SQL: "(" + strings.Join(parts, operator) + ")", Args: args}, nil
+}
+
+// parseNot parses an optionally negated condition
+func (p *parser) parseNot() (*Condition, error) {
+	if !p.peek().keyword("not") {
+		return p.parsePrimary()
+	}
+	p.next()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	condition, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return &Condition{SQL: "NOT (" + condition.SQL + ")", Args: condition.Args}, nil
+}
+
+// parsePrimary parses a parenthesized expression or a comparison
+func (p *parser) parsePrimary() (*Condition, error) {
+	if p.peek().kind != tokenLeftParen {
+		return p.parseComparison()
+	}
+	p.next()
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.next(); t.kind != tokenRightParen {
+		return nil, invalid("expected ) but found %s", t)
+	}
+	return condition, nil
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return invalid("expression is nested deeper than %d levels", MaxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+// parseComparison parses field op value, field in (values) and field not in (values)
+func (p *parser) parseComparison() (*Condition, error) {
+	name := p.next()
+	if name.kind != tokenWord {
+		return nil, invalid("expected a field name but found %s", name)
+	}
+	f, ok := filterFields[strings.ToLower(name.text)]
+	if !ok {
+		return nil, invalid("unknown field %s", name)
+	}
+
+	p.conditions++
+	if p.conditions > MaxConditions {
+		return nil, invalid("expression has more than %d conditions", MaxConditions)
+	}
+
+	if p.peek().keyword("not") || p.peek().keyword("in") {
+		negate := p.next().keyword("not")
+		if negate {
+			if t := p.next(); !t.keyword("in") {
+				return nil, invalid("expected in after not but found %s", t)
+			}
+		}
+		values, err := p.parseList(f)
+		if err != nil {
+			return nil, err
+		}
+		operator := " IN ?"
+		if negate {
+			operator = " NOT IN ?"
+		}
+		return &Condition{SQL: f.Column + operator, Args: []interface{}{values}}, nil
+	}
+
+	operator := p.next()
+	if operator.kind != tokenOperator {
+		return nil, invalid("expected an operator after %s but found %s", name, operator)
+	}
+	raw := p.next()
+	if raw.kind != tokenWord && raw.kind != tokenString {
+		return nil, invalid("expected a value after %s %s but found %s", name, operator.text, raw)
+	}
+
+	switch operator.text {
+	case "~", "!~":
+		if f.Kind != kindString {
+			return nil, invalid("%s does not support pattern matching", name.text)
+		}
+		sql := f.Column + " LIKE ? ESCAPE '!'"
+		if operator.text == "!~" {
+			sql = f.Column + " NOT LIKE ? ESCAPE '!'"
+		}
+		return &Condition{SQL: sql, Args: []interface{}{globToLike(raw.text)}}, nil
+	case "<", "<=", ">", ">=":
+		if f.Kind == kindString {
+			return nil, invalid("%s does not support %s", name.text, operator.text)
+		}
+	case "=":
+	case "!=":
+		operator.text = "<>"
+	default:
+		return nil, invalid("unknown operator %q", operator.text)
+	}
+
+	value, err := parseValue(f, name.text, raw.text)
+	if err != nil {
+		return nil, err
+	}
+	return &Condition{SQL: f.Column + " " + operator.text + " ?", Args: []interface{}{value}}, nil
+}
+
+// parseList parses a parenthesized, comma separated list of values
+func (p *parser) parseList(f field) ([]interface{}, error) {
+	if t := p.next(); t.kind != tokenLeftParen {
+		return nil, invalid("expected ( but found %s", t)
+	}
+	var values []interface{}
+	for {
+		raw := p.next()
+		if raw.kind != tokenWord && raw.kind != tokenString {
+			return nil, invalid("expected a value but found %s", raw)
+		}
+		value, err := parseValue(f, f.Column, raw.text)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if len(values) > MaxInValues {
+			return nil, invalid("in lists are limited to %d values", MaxInValues)
+		}
+
+		switch t := p.next(); t.kind {
+		case tokenComma:
+		case tokenRightParen:
+			return values, nil
+		default:
+			return nil, invalid("expected , or ) but found %s", t)
+		}
+	}
+}
+
+// parseValue converts a literal to the type of the field
+func parseValue(f field, name, raw string) (interface{}, error) {
+	switch f.Kind {
+	case kindInt:
+		value, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return nil, invalid("%s must be an integer, got %q", name, raw)
+		}
+		return int32(value), nil
+	case kindFloat:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, invalid("%s must be a number, got %q", name, raw)
+		}
+		return value, nil
+	case kindTime:
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, invalid("%s must be an RFC3339 time, got %q", name, raw)
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
+
+// globToLike converts a glob pattern to a LIKE pattern escaped with !
+func globToLike(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteRune('%')
+		case '?':
+			b.WriteRune('_')
+		case '%', '_', '!':
+			b.WriteRune('!')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+// Package query validates user supplied sort orders and filter expressions for sensor data queries
+// and compiles them to SQL. Column names only ever come from the whitelists below; values are always
+// passed as query parameters.
+package query
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidQuery is wrapped by every error caused by an invalid sort or filter expression
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Limits protecting the database from oversized expressions
+const (
+	MaxExpressionLength = 2000
+	MaxInValues         = 100 // Values in one IN list
+	MaxConditions       = 50  // Comparisons in one expression
+	MaxDepth            = 10  // Nesting of parentheses and NOT
+)
+
+// kind is the value type of a field
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindFloat
+	kindTime
+)
+
+// field is a queryable sensor data column
+type field struct {
+	Column string
+	Kind   kind
+}
+
+// filterFields are the fields usable in filter expressions, by name
+var filterFields = map[string]field{
+	"sensor_type":  {Column: "sensor_type", Kind: kindString},
+	"id1":          {Column: "id1", Kind: kindString},
+	"id2":          {Column: "id2", Kind: kindInt},
+	"value":        {Column: "sensor_value", Kind: kindFloat},
+	"sensor_value": {Column: "sensor_value", Kind: kindFloat},
+	"timestamp":    {Column: "timestamp", Kind: kindTime},
+}
+
+// sortColumns are the fields usable for sorting, by name
+var sortColumns = map[string]string{
+	"id":           "id",
+	"sensor_type":  "sensor_type",
+	"id1":          "id1",
+	"id2":          "id2",
+	"value":        "sensor_value",
+	"sensor_value": "sensor_value",
+	"timestamp":    "timestamp",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
+// invalid returns an error wrapping ErrInvalidQuery
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidQuery, fmt.Sprintf(format, args...))
+}
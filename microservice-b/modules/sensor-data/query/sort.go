@@ -0,0 +1,85 @@
+package query
+
+import (
+	"strings"
+)
+
+// SortField is one column of an ORDER BY clause
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// ParseSort parses a comma separated list of sort fields. Each field may carry its own direction
+// as "field:asc", "field:desc" or "-field"; fields without one use order (asc or desc, default asc).
+func ParseSort(sort, order string) ([]SortField, error) {
+	defaultDesc := false
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case "", "asc":
+	case "desc":
+		defaultDesc = true
+	default:
+		return nil, invalid("unknown sort order %q, use asc or desc", order)
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		desc := defaultDesc
+		name := part
+		if trimmed, ok := strings.CutPrefix(name, "-"); ok {
+			name, desc = trimmed, true
+		} else if field, direction, ok := strings.Cut(name, ":"); ok {
+			name = field
+			switch strings.ToLower(direction) {
+			case "asc":
+				desc = false
+			case "desc":
+				desc = true
+			default:
+				return nil, invalid("unknown sort order %q for %s, use asc or desc", direction, field)
+			}
+		}
+
+		column, ok := sortColumns[strings.ToLower(name)]
+		if !ok {
+			return nil, invalid("cannot sort by %q", name)
+		}
+		if seen[column] {
+			return nil, invalid("%s is sorted by more than once", name)
+		}
+		seen[column] = true
+		fields = append(fields, SortField{Column: column, Desc: desc})
+	}
+	return fields, nil
+}
+
+// OrderBy returns the ORDER BY clause for fields, with the ID appended as a tie breaker so that
+// pages are stable. Only whitelisted column names can occur in it.
+func OrderBy(fields []SortField) string {
+	parts := make([]string, 0, len(fields)+1)
+	tieBreaker := true
+	for _, field := range fields {
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		parts = append(parts, field.Column+" "+direction)
+		if field.Column == "id" {
+			tieBreaker = false
+		}
+	}
+	if tieBreaker && len(fields) > 0 {
+		direction := "ASC"
+		if fields[len(fields)-1].Desc {
+			direction = "DESC"
+		}
+		parts = append(parts, "id "+direction)
+	}
+	return strings.Join(parts, ", ")
+}
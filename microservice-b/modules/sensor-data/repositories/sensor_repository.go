@@ -2,13 +2,16 @@ package repositories
 
 import (
 	"context"
-	"fmt"
+	"strings"
 	"time"
 
+	"github.com/worlder-team/microservice-server/microservice-b/database"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/query"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type sensorRepository struct {
@@ -30,6 +33,24 @@ func (r *sensorRepository) CreateBatch(ctx context.Context, data []*entities.Sen
 	return r.db.WithContext(ctx).CreateInBatches(data, 100).Error
 }
 
+// FindExisting returns stored readings sharing an ID1 and timestamp with any of the given readings
+func (r *sensorRepository) FindExisting(ctx context.Context, data []*entities.SensorData) ([]*entities.SensorData, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	id1s := make([]string, 0, len(data))
+	timestamps := make([]time.Time, 0, len(data))
+	for _, item := range data {
+		id1s = append(id1s, item.ID1)
+		timestamps = append(timestamps, item.Timestamp.Round(time.Second))
+	}
+
+	var existing []*entities.SensorData
+	err := r.db.WithContext(ctx).Where("id1 IN ? AND timestamp IN ?", id1s, timestamps).Find(&existing).Error
+	return existing, err
+}
+
 func (r *sensorRepository) GetByID(ctx context.Context, id uint) (*entities.SensorData, error) {
 	var data entities.SensorData
 	err := r.db.WithContext(ctx).First(&data, id).Error
@@ -55,97 +76,402 @@ func (r *sensorRepository) List(ctx context.Context, filter *dtos.SensorDataFilt
 	var data []*entities.SensorData
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&entities.SensorData{})
+	db := r.db.WithContext(ctx).Model(&entities.SensorData{})
 
 	// Apply filters
-	if filter != nil {
-		if filter.SensorType != nil {
-			query = query.Where("sensor_type = ?", *filter.SensorType)
-		}
-		if filter.ID1 != nil {
-			query = query.Where("id1 = ?", *filter.ID1)
-		}
-		if filter.ID2 != nil {
-			query = query.Where("id2 = ?", *filter.ID2)
-		}
-		if filter.FromTime != nil {
-			query = query.Where("timestamp >= ?", *filter.FromTime)
-		}
-		if filter.ToTime != nil {
-			query = query.Where("timestamp <= ?", *filter.ToTime)
-		}
-		if filter.MinValue != nil {
-			query = query.Where("sensor_value >= ?", *filter.MinValue)
-		}
-		if filter.MaxValue != nil {
-			query = query.Where("sensor_value <= ?", *filter.MaxValue)
+	db = applyFilter(db, filter)
+
+	// Validate sorting before counting
+	var sort []query.SortField
+	if pagination != nil {
+		var err error
+		if sort, err = query.ParseSort(pagination.Sort, pagination.Order); err != nil {
+			return nil, 0, err
 		}
 	}
 
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
+	if err := db.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Apply pagination
 	if pagination != nil {
 		offset := (pagination.Page - 1) * pagination.PageSize
-		query = query.Offset(offset).Limit(pagination.PageSize)
+		db = db.Offset(offset).Limit(pagination.PageSize)
 
 		// Apply sorting
-		if pagination.Sort != "" {
-			order := pagination.Order
-			if order != "asc" && order != "desc" {
-				order = "asc"
-			}
-			query = query.Order(fmt.Sprintf("%s %s", pagination.Sort, order))
+		if len(sort) > 0 {
+			db = db.Order(query.OrderBy(sort))
 		} else {
-			query = query.Order("created_at desc")
+			db = db.Order("created_at desc")
 		}
 	}
 
-	err := query.Find(&data).Error
+	err := db.Find(&data).Error
 	return data, total, err
 }
 
-func (r *sensorRepository) Update(ctx context.Context, id uint, data *entities.SensorData) error {
-	// Use Select to explicitly update all updatable fields, including zero values
-	return r.db.WithContext(ctx).Model(&entities.SensorData{}).Where("id = ?", id).
-		Select("sensor_value", "sensor_type", "timestamp").Updates(data).Error
+// ListByCursor returns up to limit readings next to the cursor position, ordered by timestamp and ID
+// in the given order. Without a cursor the list starts at the beginning; a Before cursor selects the
+// readings preceding it, which are read in reverse and returned in list order.
+func (r *sensorRepository) ListByCursor(ctx context.Context, filter *dtos.SensorDataFilter, cursor *dtos.Cursor, order string, limit int) ([]*entities.SensorData, error) {
+	// Read forward (ascending) unless the list is descending, flipped when paging backwards
+	ascending := order == dtos.CursorOrderAsc
+	if cursor != nil && cursor.Before {
+		ascending = !ascending
+	}
+
+	db := applyFilter(r.db.WithContext(ctx).Model(&entities.SensorData{}), filter)
+	if ascending {
+		if cursor != nil {
+			db = db.Where("(timestamp > ? OR (timestamp = ? AND id > ?))", cursor.Timestamp, cursor.Timestamp, cursor.ID)
+		}
+		db = db.Order("timestamp ASC, id ASC")
+	} else {
+		if cursor != nil {
+			db = db.Where("(timestamp < ? OR (timestamp = ? AND id < ?))", cursor.Timestamp, cursor.Timestamp, cursor.ID)
+		}
+		db = db.Order("timestamp DESC, id DESC")
+	}
+
+	var data []*entities.SensorData
+	if err := db.Limit(limit).Find(&data).Error; err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Before {
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+	return data, nil
+}
+
+// Count returns the number of readings matching the filter
+func (r *sensorRepository) Count(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error) {
+	var total int64
+	err := applyFilter(r.db.WithContext(ctx).Model(&entities.SensorData{}), filter).Count(&total).Error
+	return total, err
 }
 
-func (r *sensorRepository) Delete(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error) {
-	query := r.db.WithContext(ctx).Model(&entities.SensorData{})
+// Aggregate computes per bucket statistics in SQL, returning at most limit rows. Buckets are aligned
+// to the Unix epoch (UTC), first and last values are taken with window functions ordered by timestamp.
+func (r *sensorRepository) Aggregate(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.AggregateParams, limit int) ([]*dtos.AggregateBucket, error) {
+	seconds := int64(params.Bucket / time.Second)
 
-	// Apply filters
-	if filter != nil {
-		if filter.SensorType != nil {
-			query = query.Where("sensor_type = ?", *filter.SensorType)
+	// Group by fields are validated by dtos.ParseGroupBy, so they are safe to use as column names
+	partition := strings.Join(append([]string{"bucket_epoch"}, params.GroupBy...), ", ")
+
+	bucketed := applyFilter(r.db.WithContext(ctx).Model(&entities.SensorData{}), filter).
+		Select("id, sensor_type, id1, id2, sensor_value, timestamp, " + database.DialectOf(r.db).BucketEpoch("timestamp", seconds) + " AS bucket_epoch")
+
+	windowed := r.db.Table("(?) AS b", bucketed).
+		Select("bucket_epoch, sensor_type, id1, id2, sensor_value, " +
+			"FIRST_VALUE(sensor_value) OVER (PARTITION BY " + partition + " ORDER BY timestamp ASC, id ASC) AS first_value, " +
+			"FIRST_VALUE(sensor_value) OVER (PARTITION BY " + partition + " ORDER BY timestamp DESC, id DESC) AS last_value")
+
+	var rows []aggregateRow
+	err := r.db.WithContext(ctx).Table("(?) AS w", windowed).
+		Select(partition + ", COUNT(*) AS count_value, MIN(sensor_value) AS min_value, MAX(sensor_value) AS max_value, " +
+			"AVG(sensor_value) AS avg_value, SUM(sensor_value) AS sum_value, MIN(first_value) AS first_value, MIN(last_value) AS last_value").
+		Group(partition).
+		Order(partition).
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*dtos.AggregateBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, row.toBucket(params.GroupBy))
+	}
+	return buckets, nil
+}
+
+// Update updates the editable values of a reading and records the change in its history
+func (r *sensorRepository) Update(ctx context.Context, id uint, data *entities.SensorData, by entities.ChangeActor) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := lockReading(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Use Select to explicitly update all updatable fields, including zero values
+		err = tx.Model(&entities.SensorData{}).Where("id = ?", id).
+			Select("sensor_value", "sensor_type", "timestamp").Updates(data).Error
+		if err != nil {
+			return err
 		}
-		if filter.ID1 != nil {
-			query = query.Where("id1 = ?", *filter.ID1)
+
+		return tx.Create(&entities.SensorDataHistory{
+			SensorDataID: id,
+			Action:       entities.HistoryActionUpdate,
+			Old:          *entities.ValuesOf(current),
+			New:          entities.ValuesOf(data),
+			ChangeActor:  by,
+		}).Error
+	})
+}
+
+// DeleteChunk deletes up to limit readings matching the filter, records them in their history and
+// returns how many were deleted
+func (r *sensorRepository) DeleteChunk(ctx context.Context, filter *dtos.SensorDataFilter, limit int, by entities.ChangeActor) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var data []*entities.SensorData
+		err := applyFilter(tx.Model(&entities.SensorData{}), filter).
+			Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Limit(limit).Find(&data).Error
+		if err != nil || len(data) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(data))
+		history := make([]*entities.SensorDataHistory, 0, len(data))
+		for _, item := range data {
+			ids = append(ids, item.ID)
+			history = append(history, deletedHistory(item, by))
+		}
+
+		result := tx.Delete(&entities.SensorData{}, ids)
+		if result.Error != nil {
+			return result.Error
 		}
-		if filter.ID2 != nil {
-			query = query.Where("id2 = ?", *filter.ID2)
+		deleted = result.RowsAffected
+		return tx.CreateInBatches(history, 100).Error
+	})
+	return deleted, err
+}
+
+// DeleteByID deletes a reading and records it in its history
+func (r *sensorRepository) DeleteByID(ctx context.Context, id uint, by entities.ChangeActor) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := lockReading(tx, id)
+		if err != nil {
+			return err
 		}
-		if filter.FromTime != nil {
-			query = query.Where("timestamp >= ?", *filter.FromTime)
+		if err := tx.Delete(&entities.SensorData{}, id).Error; err != nil {
+			return err
+		}
+		return tx.Create(deletedHistory(current, by)).Error
+	})
+}
+
+// ListHistory returns the history of a reading, most recent change first. Deleted and purged readings keep their history.
+func (r *sensorRepository) ListHistory(ctx context.Context, id uint) ([]*entities.SensorDataHistory, error) {
+	var history []*entities.SensorDataHistory
+	err := r.db.WithContext(ctx).Where("sensor_data_id = ?", id).Order("id DESC").Find(&history).Error
+	return history, err
+}
+
+// Revert sets a reading back to the values it had before the given change of its history, undeleting it
+// if needed, and records the revert. It returns gorm.ErrRecordNotFound if the change is not part of the
+// reading's history or the reading has been purged.
+func (r *sensorRepository) Revert(ctx context.Context, id, historyID uint, by entities.ChangeActor) (*entities.SensorData, error) {
+	var reverted *entities.SensorData
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var change entities.SensorDataHistory
+		if err := tx.Where("sensor_data_id = ?", id).First(&change, historyID).Error; err != nil {
+			return err
 		}
-		if filter.ToTime != nil {
-			query = query.Where("timestamp <= ?", *filter.ToTime)
+		current, err := lockReading(tx.Unscoped(), id)
+		if err != nil {
+			return err
 		}
-		if filter.MinValue != nil {
-			query = query.Where("sensor_value >= ?", *filter.MinValue)
+
+		err = tx.Unscoped().Model(&entities.SensorData{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"sensor_value": change.Old.SensorValue,
+			"sensor_type":  change.Old.SensorType,
+			"timestamp":    change.Old.Timestamp,
+			"deleted_at":   nil,
+			"updated_at":   time.Now(),
+		}).Error
+		if err != nil {
+			return err
 		}
-		if filter.MaxValue != nil {
-			query = query.Where("sensor_value <= ?", *filter.MaxValue)
+
+		err = tx.Create(&entities.SensorDataHistory{
+			SensorDataID: id,
+			Action:       entities.HistoryActionRevert,
+			Old:          *entities.ValuesOf(current),
+			New:          &change.Old,
+			ChangeActor:  by,
+		}).Error
+		if err != nil {
+			return err
 		}
+
+		reverted = &entities.SensorData{}
+		return tx.First(reverted, id).Error
+	})
+	return reverted, err
+}
+
+// lockReading reads a reading for update within a transaction
+func lockReading(tx *gorm.DB, id uint) (*entities.SensorData, error) {
+	var data entities.SensorData
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// deletedHistory returns the history entry recording the delete of data
+func deletedHistory(data *entities.SensorData, by entities.ChangeActor) *entities.SensorDataHistory {
+	return &entities.SensorDataHistory{
+		SensorDataID: data.ID,
+		Action:       entities.HistoryActionDelete,
+		Old:          *entities.ValuesOf(data),
+		ChangeActor:  by,
+	}
+}
+
+// ListDeleted returns a page of soft-deleted readings matching the filter, most recently deleted first
+func (r *sensorRepository) ListDeleted(ctx context.Context, filter *dtos.SensorDataFilter, page, pageSize int) ([]*entities.SensorData, int64, error) {
+	db := applyFilter(r.deleted(ctx), filter)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	result := query.Delete(&entities.SensorData{})
+	var data []*entities.SensorData
+	err := db.Order("deleted_at DESC, id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&data).Error
+	return data, total, err
+}
+
+// FindDeletedIDs returns the IDs of up to limit soft-deleted readings matching the filter
+func (r *sensorRepository) FindDeletedIDs(ctx context.Context, filter *dtos.SensorDataFilter, limit int) ([]uint, error) {
+	var ids []uint
+	err := applyFilter(r.deleted(ctx), filter).Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore undeletes the given soft-deleted readings. Their updated_at is bumped, so rollups pick them up again.
+func (r *sensorRepository) Restore(ctx context.Context, ids []uint) (int64, error) {
+	result := r.deleted(ctx).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
 	return result.RowsAffected, result.Error
 }
 
-func (r *sensorRepository) DeleteByID(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entities.SensorData{}, id).Error
+// Purge permanently removes the given soft-deleted readings
+func (r *sensorRepository) Purge(ctx context.Context, ids []uint) (int64, error) {
+	result := r.deleted(ctx).Where("id IN ?", ids).Delete(&entities.SensorData{})
+	return result.RowsAffected, result.Error
+}
+
+// deleted starts a query on the soft-deleted readings only
+func (r *sensorRepository) deleted(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Unscoped().Model(&entities.SensorData{}).Where("deleted_at IS NOT NULL")
+}
+
+// Export passes matching readings to fn in batches ordered by timestamp and ID. Batches are read with
+// keyset pagination, so memory stays constant and no query stays open while fn writes a batch out.
+func (r *sensorRepository) Export(ctx context.Context, filter *dtos.SensorDataFilter, batchSize int, fn func(batch []*entities.SensorData) error) error {
+	var last *entities.SensorData
+	for {
+		db := applyFilter(r.db.WithContext(ctx).Model(&entities.SensorData{}), filter)
+		if last != nil {
+			db = db.Where("(timestamp > ? OR (timestamp = ? AND id > ?))", last.Timestamp, last.Timestamp, last.ID)
+		}
+
+		var batch []*entities.SensorData
+		if err := db.Order("timestamp, id").Limit(batchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last = batch[len(batch)-1]
+	}
+}
+
+// applyFilter adds the SensorDataFilter conditions to a query.
+// An invalid filter expression is added to the query's errors.
+func applyFilter(db *gorm.DB, filter *dtos.SensorDataFilter) *gorm.DB {
+	if filter == nil {
+		return db
+	}
+	if filter.SensorType != nil {
+		db = db.Where("sensor_type = ?", *filter.SensorType)
+	}
+	if len(filter.SensorTypes) > 0 {
+		db = db.Where("sensor_type IN ?", filter.SensorTypes)
+	}
+	if filter.ID1 != nil {
+		db = db.Where("id1 = ?", *filter.ID1)
+	}
+	if len(filter.ID1s) > 0 {
+		db = db.Where("id1 IN ?", filter.ID1s)
+	}
+	if filter.ID2 != nil {
+		db = db.Where("id2 = ?", *filter.ID2)
+	}
+	if filter.FromTime != nil {
+		db = db.Where("timestamp >= ?", *filter.FromTime)
+	}
+	if filter.ToTime != nil {
+		db = db.Where("timestamp <= ?", *filter.ToTime)
+	}
+	if filter.MinValue != nil {
+		db = db.Where("sensor_value >= ?", *filter.MinValue)
+	}
+	if filter.MaxValue != nil {
+		db = db.Where("sensor_value <= ?", *filter.MaxValue)
+	}
+	if filter.Expression != "" {
+		condition, err := query.Compile(filter.Expression)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		db = db.Where(condition.SQL, condition.Args...)
+	}
+	return db
+}
+
+// aggregateRow is a row of the aggregation db, only the grouped columns are set
+type aggregateRow struct {
+	BucketEpoch int64
+	SensorType  string
+	ID1         string `gorm:"column:id1"`
+	ID2         int32  `gorm:"column:id2"`
+	CountValue  int64
+	MinValue    float64
+	MaxValue    float64
+	AvgValue    float64
+	SumValue    float64
+	FirstValue  float64
+	LastValue   float64
+}
+
+func (row aggregateRow) toBucket(groupBy []string) *dtos.AggregateBucket {
+	bucket := &dtos.AggregateBucket{
+		BucketStart: time.Unix(row.BucketEpoch, 0).UTC(),
+		Count:       row.CountValue,
+		Min:         row.MinValue,
+		Max:         row.MaxValue,
+		Avg:         row.AvgValue,
+		Sum:         row.SumValue,
+		First:       row.FirstValue,
+		Last:        row.LastValue,
+	}
+	for _, field := range groupBy {
+		switch field {
+		case dtos.GroupBySensorType:
+			bucket.SensorType = &row.SensorType
+		case dtos.GroupByID1:
+			bucket.ID1 = &row.ID1
+		case dtos.GroupByID2:
+			bucket.ID2 = &row.ID2
+		}
+	}
+	return bucket
 }
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/configs"
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrate"
+	"github.com/worlder-team/microservice-server/microservice-b/database/migrations"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+)
+
+// openSQLite opens a SQLite database in a temporary directory the way the server does and migrates it
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "sensor_data.db"))
+	cfg := configs.LoadConfig()
+
+	db, err := database.Open(cfg.Database.Driver, cfg.GetDSN())
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	migrator, err := migrate.New(db, migrations.All(), time.Minute)
+	if err != nil {
+		t.Fatalf("new migrator: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		t.Fatalf("schema after migrate up: %v", err)
+	}
+	return db
+}
+
+func TestSensorRepositoryRoundTripOnSQLite(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSensorRepository(openSQLite(t))
+
+	timestamp := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	data := &entities.SensorData{SensorValue: 21.5, SensorType: "temperature", ID1: "device-1", ID2: 7, Timestamp: timestamp}
+	if err := repo.Create(ctx, data); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if data.ID == 0 {
+		t.Fatal("created reading has no ID")
+	}
+
+	listed, total, err := repo.List(ctx, &dtos.SensorDataFilter{}, &dtos.PaginationParams{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if total != 1 || len(listed) != 1 {
+		t.Fatalf("listed %d of %d readings, want 1 of 1", len(listed), total)
+	}
+	if got := listed[0]; got.ID != data.ID || got.SensorValue != 21.5 || got.ID1 != "device-1" || !got.Timestamp.Equal(timestamp) {
+		t.Errorf("listed reading = %d %g %s at %s, want %d 21.5 device-1 at %s", got.ID, got.SensorValue, got.ID1, got.Timestamp, data.ID, timestamp)
+	}
+
+	by := entities.ChangeActor{ActorID: 1, RequestID: "test"}
+	if err := repo.DeleteByID(ctx, data.ID, by); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, total, err := repo.List(ctx, &dtos.SensorDataFilter{}, nil); err != nil || total != 0 {
+		t.Errorf("readings after delete = %d (%v), want 0", total, err)
+	}
+
+	history, err := repo.ListHistory(ctx, data.ID)
+	if err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+	if len(history) != 1 || history[0].Action != entities.HistoryActionDelete || history[0].ActorID != 1 {
+		t.Errorf("history = %+v, want one delete by actor 1", history)
+	}
+
+	// Deleting again finds nothing, which the handler reports as 404
+	if err := repo.DeleteByID(ctx, data.ID, by); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("second delete error = %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+}
@@ -0,0 +1,257 @@
+package rollups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// JobConfig holds rollup job settings
+type JobConfig struct {
+	Interval  time.Duration // How often new changes are rolled up
+	Lag       time.Duration // Changes younger than this are left for the next run so in-flight writes can commit
+	MaxWindow time.Duration // Maximum span of modification time processed in one step while catching up
+}
+
+// Job keeps the rollup tables up to date. Each step picks up raw readings inserted, updated or
+// deleted since the watermark, recomputes the 1 minute buckets they fall into from the raw data and
+// then the enclosing hour and day buckets from the finer rollups, so late-arriving readings are
+// folded into old buckets. Readings moved to another minute by an update or revert are found through
+// their history, so the bucket they left is recomputed too. The watermark row is locked for the duration of a step, so only one
+// replica rolls up at a time.
+type Job struct {
+	db     *gorm.DB
+	cfg    JobConfig
+	cache  interfaces.CacheInvalidator
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewJob creates a rollup job. The sensor data cache is invalidated after buckets are recomputed,
+// as cached aggregations may have been answered from them; cache may be nil when reads are not cached.
+func NewJob(db *gorm.DB, cfg JobConfig, cache interfaces.CacheInvalidator) *Job {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Lag < 0 {
+		cfg.Lag = 0
+	}
+	if cfg.MaxWindow <= 0 {
+		cfg.MaxWindow = time.Hour
+	}
+	return &Job{db: db, cfg: cfg, cache: cache}
+}
+
+// Start runs the job in the background until Close is called
+func (j *Job) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+
+	j.wg.Add(1)
+	go j.loop(ctx)
+}
+
+// Close stops the job and waits for a running step to finish
+func (j *Job) Close() {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+}
+
+// loop runs steps every Interval, back to back while catching up
+func (j *Job) loop(ctx context.Context) {
+	defer j.wg.Done()
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			more, err := j.Step(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					utils.Error(fmt.Sprintf("Sensor rollup failed: %v", err))
+				}
+				break
+			}
+			if !more || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Step rolls up one window of changes and advances the watermark.
+// It reports whether more changes are waiting beyond the window.
+func (j *Job) Step(ctx context.Context) (bool, error) {
+	more, recomputed := false, false
+	err := j.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		watermark, err := lockWatermark(tx)
+		if err != nil {
+			return err
+		}
+
+		end := time.Now().Add(-j.cfg.Lag)
+		if !end.After(watermark.Watermark) {
+			return nil
+		}
+
+		// Skip idle periods without changes
+		next, err := nextChange(tx, watermark.Watermark)
+		if err != nil {
+			return err
+		}
+		if next != nil && !next.After(end) {
+			if limit := next.Add(j.cfg.MaxWindow); end.After(limit) {
+				end = limit
+				more = true
+			}
+
+			ranges, err := changedMinutes(tx, watermark.Watermark, end)
+			if err != nil {
+				return err
+			}
+			if err := recompute(tx, ranges); err != nil {
+				return err
+			}
+			recomputed = len(ranges) > 0
+		}
+
+		watermark.Watermark = end
+		return tx.Save(watermark).Error
+	})
+	if err == nil && recomputed && j.cache != nil {
+		j.cache.InvalidateAll(ctx)
+	}
+	return more, err
+}
+
+// Watermark returns the modification time up to which rollups are complete, zero if none ran yet
+func Watermark(ctx context.Context, db *gorm.DB) (time.Time, error) {
+	var watermark entities.RollupWatermark
+	err := db.WithContext(ctx).First(&watermark, "name = ?", watermarkName).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return watermark.Watermark, err
+}
+
+// lockWatermark reads the watermark row for update, creating it on the first run
+func lockWatermark(tx *gorm.DB) (*entities.RollupWatermark, error) {
+	var watermark entities.RollupWatermark
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&watermark, "name = ?", watermarkName).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		watermark = entities.RollupWatermark{Name: watermarkName, Watermark: time.Unix(0, 0).UTC()}
+		if err := tx.Create(&watermark).Error; err != nil {
+			return nil, err
+		}
+		return &watermark, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &watermark, nil
+}
+
+// nextChange returns the earliest modification after since, nil if there is none
+func nextChange(tx *gorm.DB, since time.Time) (*time.Time, error) {
+	var next *time.Time
+	for _, source := range []struct {
+		model  interface{}
+		column string
+	}{
+		{&entities.SensorData{}, "updated_at"},
+		{&entities.SensorData{}, "deleted_at"},
+		{&entities.SensorDataHistory{}, "created_at"},
+	} {
+		var times []time.Time
+		err := tx.Model(source.model).Unscoped().
+			Where(source.column+" > ?", since).Order(source.column).Limit(1).Pluck(source.column, &times).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(times) > 0 && (next == nil || times[0].Before(*next)) {
+			next = &times[0]
+		}
+	}
+	return next, nil
+}
+
+// changedMinutes returns the 1 minute buckets holding readings modified in (from, to], merged into
+// ranges. For updates and reverts this includes the bucket of the timestamp the reading had before.
+func changedMinutes(tx *gorm.DB, from, to time.Time) ([]timeRange, error) {
+	dialect := database.DialectOf(tx)
+	seconds := int64(Resolutions[0].Size / time.Second)
+
+	var epochs []int64
+	err := tx.Raw("SELECT DISTINCT "+dialect.BucketEpoch("timestamp", seconds)+" AS bucket_epoch FROM sensor_data "+
+		"WHERE (updated_at > ? AND updated_at <= ?) OR (deleted_at > ? AND deleted_at <= ?)",
+		from, to, from, to).Scan(&epochs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var movedFrom []int64
+	err = tx.Raw("SELECT DISTINCT "+dialect.BucketEpoch("old_timestamp", seconds)+" AS bucket_epoch FROM sensor_data_history "+
+		"WHERE created_at > ? AND created_at <= ? AND action IN ?",
+		from, to, []string{entities.HistoryActionUpdate, entities.HistoryActionRevert}).Scan(&movedFrom).Error
+	if err != nil {
+		return nil, err
+	}
+
+	epochs = append(epochs, movedFrom...)
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+
+	ranges := make([]timeRange, 0, len(epochs))
+	for _, epoch := range epochs {
+		start := time.Unix(epoch, 0).UTC()
+		ranges = append(ranges, timeRange{From: start, To: start.Add(Resolutions[0].Size)})
+	}
+	return coverRanges(ranges, Resolutions[0].Size), nil
+}
+
+// recompute rebuilds the given minute ranges at every resolution, each level from the one below
+func recompute(tx *gorm.DB, ranges []timeRange) error {
+	dialect := database.DialectOf(tx)
+	for i, resolution := range Resolutions {
+		source := rawSource + " AND timestamp >= ? AND timestamp < ?"
+		if i > 0 {
+			source = rollupSource(Resolutions[i-1].Table) + " AND bucket_start >= ? AND bucket_start < ?"
+			ranges = coverRanges(ranges, resolution.Size)
+		}
+
+		insert := "INSERT INTO " + resolution.Table + " (bucket_start, sensor_type, id1, id2, sample_count, " +
+			"min_value, max_value, sum_value, first_value, first_at, last_value, last_at) " +
+			"SELECT " + dialect.FromEpoch("bucket_epoch") + ", sensor_type, id1, id2, sample_count, " +
+			"min_value, max_value, sum_value, first_value, first_at, last_value, last_at FROM (" +
+			bucketSQL(dialect, source, resolution.Size, groupColumns) + ") AS a"
+
+		for _, r := range ranges {
+			if err := tx.Exec("DELETE FROM "+resolution.Table+" WHERE bucket_start >= ? AND bucket_start < ?", r.From, r.To).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(insert, r.From, r.To).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
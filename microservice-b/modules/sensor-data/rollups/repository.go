@@ -0,0 +1,183 @@
+package rollups
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+)
+
+// Repository is a sensor repository decorator that answers aggregations from the coarsest rollup
+// whose resolution divides the requested bucket. Buckets before the rollup watermark are read from
+// the rollup table and the remaining, most recent buckets from the raw data.
+type Repository struct {
+	interfaces.SensorRepositoryInterface
+	db *gorm.DB
+}
+
+// NewRepository wraps repo with rollup-backed aggregations
+func NewRepository(repo interfaces.SensorRepositoryInterface, db *gorm.DB) *Repository {
+	return &Repository{
+		SensorRepositoryInterface: repo,
+		db:                        db,
+	}
+}
+
+// Aggregate splits the requested range at the rollup watermark and merges both parts
+func (r *Repository) Aggregate(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.AggregateParams, limit int) ([]*dtos.AggregateBucket, error) {
+	resolution := r.resolutionFor(filter, params)
+	if resolution == nil {
+		return r.SensorRepositoryInterface.Aggregate(ctx, filter, params, limit)
+	}
+
+	watermark, err := Watermark(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	if watermark.IsZero() {
+		return r.SensorRepositoryInterface.Aggregate(ctx, filter, params, limit)
+	}
+
+	// Query buckets starting before the cutoff are complete in the rollups
+	cutoff := floorTime(watermark, params.Bucket)
+	if filter != nil && filter.ToTime != nil {
+		if end := floorTime(*filter.ToTime, params.Bucket); end.Before(cutoff) {
+			cutoff = end
+		}
+	}
+	if filter != nil && filter.FromTime != nil && !filter.FromTime.Before(cutoff) {
+		return r.SensorRepositoryInterface.Aggregate(ctx, filter, params, limit)
+	}
+
+	buckets, err := r.aggregateRollup(ctx, resolution, filter, params, cutoff, limit)
+	if err != nil || len(buckets) >= limit {
+		return buckets, err
+	}
+
+	recent := dtos.SensorDataFilter{}
+	if filter != nil {
+		recent = *filter
+	}
+	recent.FromTime = &cutoff
+	if recent.ToTime != nil && recent.ToTime.Before(cutoff) {
+		return buckets, nil
+	}
+
+	raw, err := r.SensorRepositoryInterface.Aggregate(ctx, &recent, params, limit-len(buckets))
+	if err != nil {
+		return nil, err
+	}
+	return append(buckets, raw...), nil
+}
+
+// resolutionFor picks the coarsest rollup usable for an aggregation, nil when only raw data can answer it.
+// Value filters and filter expressions need the individual readings, and a from_time must fall on a rollup boundary.
+func (r *Repository) resolutionFor(filter *dtos.SensorDataFilter, params *dtos.AggregateParams) *Resolution {
+	if filter != nil && filter.HasValueCriteria() {
+		return nil
+	}
+
+	for i := len(Resolutions) - 1; i >= 0; i-- {
+		resolution := Resolutions[i]
+		if params.Bucket%resolution.Size != 0 {
+			continue
+		}
+		if filter != nil && filter.FromTime != nil && !floorTime(*filter.FromTime, resolution.Size).Equal(*filter.FromTime) {
+			continue
+		}
+		return &resolution
+	}
+	return nil
+}
+
+// aggregateRollup aggregates the rollup rows of buckets starting before cutoff
+func (r *Repository) aggregateRollup(ctx context.Context, resolution *Resolution, filter *dtos.SensorDataFilter, params *dtos.AggregateParams, cutoff time.Time, limit int) ([]*dtos.AggregateBucket, error) {
+	conditions := []string{"bucket_start < ?"}
+	args := []interface{}{cutoff}
+	if filter != nil {
+		if filter.SensorType != nil {
+			conditions = append(conditions, "sensor_type = ?")
+			args = append(args, *filter.SensorType)
+		}
+		if len(filter.SensorTypes) > 0 {
+			conditions = append(conditions, "sensor_type IN ?")
+			args = append(args, filter.SensorTypes)
+		}
+		if filter.ID1 != nil {
+			conditions = append(conditions, "id1 = ?")
+			args = append(args, *filter.ID1)
+		}
+		if len(filter.ID1s) > 0 {
+			conditions = append(conditions, "id1 IN ?")
+			args = append(args, filter.ID1s)
+		}
+		if filter.ID2 != nil {
+			conditions = append(conditions, "id2 = ?")
+			args = append(args, *filter.ID2)
+		}
+		if filter.FromTime != nil {
+			conditions = append(conditions, "bucket_start >= ?")
+			args = append(args, *filter.FromTime)
+		}
+	}
+
+	source := rollupSource(resolution.Table) + " AND " + strings.Join(conditions, " AND ")
+	partition := strings.Join(append([]string{"bucket_epoch"}, params.GroupBy...), ", ")
+	query := bucketSQL(database.DialectOf(r.db), source, params.Bucket, params.GroupBy) + " ORDER BY " + partition + " LIMIT ?"
+
+	var rows []bucketRow
+	if err := r.db.WithContext(ctx).Raw(query, append(args, limit)...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*dtos.AggregateBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, row.toBucket(params.GroupBy))
+	}
+	return buckets, nil
+}
+
+// bucketRow is a row of a rollup aggregation, only the grouped columns are set
+type bucketRow struct {
+	BucketEpoch int64
+	SensorType  string
+	ID1         string `gorm:"column:id1"`
+	ID2         int32  `gorm:"column:id2"`
+	SampleCount int64
+	MinValue    float64
+	MaxValue    float64
+	SumValue    float64
+	FirstValue  float64
+	LastValue   float64
+}
+
+func (row bucketRow) toBucket(groupBy []string) *dtos.AggregateBucket {
+	bucket := &dtos.AggregateBucket{
+		BucketStart: time.Unix(row.BucketEpoch, 0).UTC(),
+		Count:       row.SampleCount,
+		Min:         row.MinValue,
+		Max:         row.MaxValue,
+		Sum:         row.SumValue,
+		First:       row.FirstValue,
+		Last:        row.LastValue,
+	}
+	if row.SampleCount > 0 {
+		bucket.Avg = row.SumValue / float64(row.SampleCount)
+	}
+	for _, field := range groupBy {
+		switch field {
+		case dtos.GroupBySensorType:
+			bucket.SensorType = &row.SensorType
+		case dtos.GroupByID1:
+			bucket.ID1 = &row.ID1
+		case dtos.GroupByID2:
+			bucket.ID2 = &row.ID2
+		}
+	}
+	return bucket
+}
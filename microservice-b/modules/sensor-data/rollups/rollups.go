@@ -0,0 +1,100 @@
+package rollups
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/database"
+)
+
+// Resolution is a rollup granularity stored in its own table
+type Resolution struct {
+	Name  string
+	Table string
+	Size  time.Duration
+}
+
+// Resolutions from finest to coarsest; each level is computed from the previous one
+var Resolutions = []Resolution{
+	{Name: "1m", Table: "sensor_rollups_1m", Size: time.Minute},
+	{Name: "1h", Table: "sensor_rollups_1h", Size: time.Hour},
+	{Name: "1d", Table: "sensor_rollups_1d", Size: 24 * time.Hour},
+}
+
+// watermarkName identifies the rollup job's row in rollup_watermarks
+const watermarkName = "sensor_rollups"
+
+// groupColumns are the columns every rollup is keyed by besides the bucket
+var groupColumns = []string{"sensor_type", "id1", "id2"}
+
+// rawSource selects raw readings in the rollup column layout, each reading being a one-sample rollup
+const rawSource = "SELECT sensor_type, id1, id2, timestamp AS bucket_ts, 1 AS sample_count, " +
+	"sensor_value AS min_value, sensor_value AS max_value, sensor_value AS sum_value, " +
+	"sensor_value AS first_value, timestamp AS first_at, sensor_value AS last_value, timestamp AS last_at " +
+	"FROM sensor_data WHERE deleted_at IS NULL"
+
+// rollupSource selects the rows of a rollup table
+func rollupSource(table string) string {
+	return "SELECT sensor_type, id1, id2, bucket_start AS bucket_ts, sample_count, " +
+		"min_value, max_value, sum_value, first_value, first_at, last_value, last_at FROM " + table + " WHERE 1 = 1"
+}
+
+// bucketSQL merges source rows (in the rollup column layout) into buckets of size, grouped by groupBy.
+// Group by fields must be validated column names. First and last values are taken with window functions
+// so that merging finer rollups gives the same result as aggregating the raw readings.
+func bucketSQL(dialect database.Dialect, source string, size time.Duration, groupBy []string) string {
+	seconds := int64(size / time.Second)
+	partition := strings.Join(append([]string{"bucket_epoch"}, groupBy...), ", ")
+
+	return fmt.Sprintf("SELECT %[1]s, SUM(sample_count) AS sample_count, MIN(min_value) AS min_value, "+
+		"MAX(max_value) AS max_value, SUM(sum_value) AS sum_value, MIN(bucket_first) AS first_value, "+
+		"MIN(first_at) AS first_at, MIN(bucket_last) AS last_value, MAX(last_at) AS last_at "+
+		"FROM (SELECT s.*, "+
+		"FIRST_VALUE(first_value) OVER (PARTITION BY %[1]s ORDER BY first_at ASC) AS bucket_first, "+
+		"FIRST_VALUE(last_value) OVER (PARTITION BY %[1]s ORDER BY last_at DESC) AS bucket_last "+
+		"FROM (SELECT src.*, %[2]s AS bucket_epoch FROM (%[3]s) AS src) AS s) AS w "+
+		"GROUP BY %[1]s", partition, dialect.BucketEpoch("bucket_ts", seconds), source)
+}
+
+// timeRange is a half-open interval [From, To)
+type timeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// floorTime aligns t down to a multiple of size since the Unix epoch
+func floorTime(t time.Time, size time.Duration) time.Time {
+	seconds := int64(size / time.Second)
+	unix := t.Unix()
+	floored := unix - unix%seconds
+	if unix < 0 && unix%seconds != 0 {
+		floored -= seconds
+	}
+	return time.Unix(floored, 0).UTC()
+}
+
+// ceilTime aligns t up to a multiple of size since the Unix epoch
+func ceilTime(t time.Time, size time.Duration) time.Time {
+	floored := floorTime(t, size)
+	if floored.Equal(t) {
+		return floored
+	}
+	return floored.Add(size)
+}
+
+// coverRanges widens ranges to whole buckets of size and merges the ones that touch
+func coverRanges(ranges []timeRange, size time.Duration) []timeRange {
+	var merged []timeRange
+	for _, r := range ranges {
+		r = timeRange{From: floorTime(r.From, size), To: ceilTime(r.To, size)}
+		if last := len(merged) - 1; last >= 0 && !r.From.After(merged[last].To) {
+			if r.To.After(merged[last].To) {
+				merged[last].To = r.To
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
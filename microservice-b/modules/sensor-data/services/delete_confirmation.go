@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+)
+
+// ErrInvalidConfirmation is returned for confirmation tokens that are malformed, expired,
+// issued to another user or issued for another filter
+var ErrInvalidConfirmation = errors.New("invalid or expired confirmation token, run the delete query without a token first")
+
+// DeleteConfirmer issues and checks the confirmation tokens of delete queries. A token is an HMAC
+// over the user, the expiry and the filter, so the server keeps no state between dry run and delete.
+type DeleteConfirmer struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewDeleteConfirmer creates a confirmer whose key is derived from secret; tokens are valid for ttl
+func NewDeleteConfirmer(secret string, ttl time.Duration) *DeleteConfirmer {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("sensor-data delete confirmation"))
+	return &DeleteConfirmer{key: mac.Sum(nil), ttl: ttl}
+}
+
+// Issue returns a token confirming the delete of filter by userID, and its expiry
+func (c *DeleteConfirmer) Issue(userID uint, filter *dtos.SensorDataFilter) (string, time.Time, error) {
+	expiresAt := time.Now().Add(c.ttl).Truncate(time.Second)
+	signature, err := c.sign(userID, expiresAt.Unix(), filter)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return strconv.FormatInt(expiresAt.Unix(), 10) + "." + signature, expiresAt, nil
+}
+
+// Verify checks that token was issued to userID for filter and has not expired
+func (c *DeleteConfirmer) Verify(token string, userID uint, filter *dtos.SensorDataFilter) error {
+	expiresStr, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidConfirmation
+	}
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return ErrInvalidConfirmation
+	}
+
+	expected, err := c.sign(userID, expires, filter)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrInvalidConfirmation
+	}
+	return nil
+}
+
+// sign computes the token signature. Times are normalized to UTC so that equal filters sign equally.
+func (c *DeleteConfirmer) sign(userID uint, expires int64, filter *dtos.SensorDataFilter) (string, error) {
+	normalized := *filter
+	if normalized.FromTime != nil {
+		from := normalized.FromTime.UTC()
+		normalized.FromTime = &from
+	}
+	if normalized.ToTime != nil {
+		to := normalized.ToTime.UTC()
+		normalized.ToTime = &to
+	}
+	payload, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, c.key)
+	fmt.Fprintf(mac, "%d\n%d\n", userID, expires)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -2,32 +2,173 @@ package services
 
 import (
 	"context"
+	"errors"
 	"math"
 	"time"
 
+	"gorm.io/gorm"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/actor"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/pipeline"
 	"github.com/worlder-team/microservice-server/shared/constants"
 )
 
+// ErrTooManyBuckets is returned when an aggregation would return more than dtos.MaxAggregateBuckets rows
+var ErrTooManyBuckets = errors.New("aggregation returns too many buckets, use a larger bucket or a shorter time range")
+
+// defaultDeleteChunkSize is the number of readings DeleteSensorData deletes per statement
+const defaultDeleteChunkSize = 1000
+
 type sensorService struct {
 	sensorRepo interfaces.SensorRepositoryInterface
+	publishers []interfaces.SensorDataPublisher
 }
 
-// NewSensorService creates a new sensor service
-func NewSensorService(sensorRepo interfaces.SensorRepositoryInterface) interfaces.SensorServiceInterface {
+// NewSensorService creates a new sensor service.
+// Publishers are notified with every reading once it has been stored.
+func NewSensorService(sensorRepo interfaces.SensorRepositoryInterface, publishers ...interfaces.SensorDataPublisher) interfaces.SensorServiceInterface {
 	return &sensorService{
 		sensorRepo: sensorRepo,
+		publishers: publishers,
 	}
 }
 
 func (s *sensorService) CreateSensorData(ctx context.Context, data *entities.SensorData) error {
-	return s.sensorRepo.Create(ctx, data)
+	if err := data.Validate(time.Now()); err != nil {
+		return err
+	}
+
+	if err := s.sensorRepo.Create(ctx, data); err != nil {
+		return err
+	}
+
+	s.publish(ctx, []*entities.SensorData{data})
+	return nil
+}
+
+// CreateSensorDataBatch stores the valid, previously unseen items of a batch and reports the outcome of each item.
+// Invalid items are rejected and duplicates (same sensor type, IDs and timestamp) are skipped without failing the batch.
+func (s *sensorService) CreateSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*dtos.BatchResult, error) {
+	outcomes, pending, pendingIndexes, err := s.checkBatch(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted, err := s.storeBatch(ctx, pending, pendingIndexes, outcomes)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, accepted)
+
+	return newBatchResult(outcomes), nil
 }
 
-func (s *sensorService) CreateSensorDataBatch(ctx context.Context, data []*entities.SensorData) error {
-	return s.sensorRepo.CreateBatch(ctx, data)
+// CheckSensorDataBatch reports the outcome CreateSensorDataBatch would have for each item without storing anything
+func (s *sensorService) CheckSensorDataBatch(ctx context.Context, data []*entities.SensorData) (*dtos.BatchResult, error) {
+	outcomes, _, _, err := s.checkBatch(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return newBatchResult(outcomes), nil
+}
+
+// checkBatch rejects invalid items and skips duplicates, returning the outcome of every item
+// along with the items left to store and their indexes in the batch
+func (s *sensorService) checkBatch(ctx context.Context, data []*entities.SensorData) ([]dtos.BatchItemResult, []*entities.SensorData, []int, error) {
+	now := time.Now()
+	outcomes := make([]dtos.BatchItemResult, len(data))
+
+	// Reject invalid items and duplicates within the batch
+	var candidates []*entities.SensorData
+	var candidateIndexes []int
+	seen := make(map[string]bool, len(data))
+	for i, item := range data {
+		outcomes[i] = dtos.BatchItemResult{Index: i, Status: dtos.BatchItemAccepted}
+
+		if err := item.Validate(now); err != nil {
+			outcomes[i] = dtos.BatchItemResult{Index: i, Status: dtos.BatchItemRejected, Reason: err.Error()}
+			continue
+		}
+
+		key := item.DedupKey()
+		if seen[key] {
+			outcomes[i] = dtos.BatchItemResult{Index: i, Status: dtos.BatchItemDuplicate, Reason: "duplicate of an earlier item in the batch"}
+			continue
+		}
+		seen[key] = true
+
+		candidates = append(candidates, item)
+		candidateIndexes = append(candidateIndexes, i)
+	}
+
+	// Skip items that are already stored
+	existing, err := s.sensorRepo.FindExisting(ctx, candidates)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stored := make(map[string]bool, len(existing))
+	for _, item := range existing {
+		stored[item.DedupKey()] = true
+	}
+
+	var pending []*entities.SensorData
+	var pendingIndexes []int
+	for i, item := range candidates {
+		if stored[item.DedupKey()] {
+			outcomes[candidateIndexes[i]] = dtos.BatchItemResult{Index: candidateIndexes[i], Status: dtos.BatchItemDuplicate, Reason: "already stored"}
+			continue
+		}
+		pending = append(pending, item)
+		pendingIndexes = append(pendingIndexes, candidateIndexes[i])
+	}
+
+	return outcomes, pending, pendingIndexes, nil
+}
+
+// newBatchResult summarises item outcomes
+func newBatchResult(outcomes []dtos.BatchItemResult) *dtos.BatchResult {
+	result := &dtos.BatchResult{}
+	for _, outcome := range outcomes {
+		result.Add(outcome)
+	}
+	return result
+}
+
+// storeBatch inserts pending items in one statement, falling back to single inserts to isolate failing items.
+// Items that cannot be stored are marked as retryable rejections; the stored items are returned.
+// A full ingestion queue fails the whole batch so the caller can back off.
+func (s *sensorService) storeBatch(ctx context.Context, pending []*entities.SensorData, indexes []int, outcomes []dtos.BatchItemResult) ([]*entities.SensorData, error) {
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	err := s.sensorRepo.CreateBatch(ctx, pending)
+	if err == nil {
+		return pending, nil
+	}
+	if errors.Is(err, pipeline.ErrQueueFull) {
+		return nil, err
+	}
+
+	var accepted []*entities.SensorData
+	for i, item := range pending {
+		item.ID = 0
+		if err := s.sensorRepo.Create(ctx, item); err != nil {
+			outcomes[indexes[i]] = dtos.BatchItemResult{Index: indexes[i], Status: dtos.BatchItemRejected, Reason: err.Error(), Retryable: true}
+			continue
+		}
+		accepted = append(accepted, item)
+	}
+	return accepted, nil
+}
+
+// publish notifies every registered publisher about newly stored data
+func (s *sensorService) publish(ctx context.Context, data []*entities.SensorData) {
+	for _, publisher := range s.publishers {
+		publisher.Publish(ctx, data)
+	}
 }
 
 func (s *sensorService) GetSensorData(ctx context.Context, id uint) (*entities.SensorData, error) {
@@ -77,26 +218,231 @@ func (s *sensorService) ListSensorData(ctx context.Context, filter *dtos.SensorD
 	}, nil
 }
 
-func (s *sensorService) UpdateSensorData(ctx context.Context, id uint, data *entities.SensorData) error {
-	// Check if record exists
-	_, err := s.sensorRepo.GetByID(ctx, id)
+// ListSensorDataByCursor returns a page of sensor data ordered by timestamp and ID along with cursors
+// to the neighbouring pages. The total is only counted when requested.
+func (s *sensorService) ListSensorDataByCursor(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.CursorParams) (*dtos.CursorPage, error) {
+	if params == nil {
+		params = &dtos.CursorParams{}
+	}
+	pageSize := params.PageSize
+	if pageSize < 1 || pageSize > constants.MaxPageSize {
+		pageSize = constants.DefaultPageSize
+	}
+	order := params.Order
+	if params.Cursor != nil {
+		order = params.Cursor.Order
+	}
+	if order != dtos.CursorOrderAsc {
+		order = dtos.CursorOrderDesc
+	}
+	backward := params.Cursor != nil && params.Cursor.Before
+
+	// Read one extra row to find out whether the list continues past the page
+	data, err := s.sensorRepo.ListByCursor(ctx, filter, params.Cursor, order, pageSize+1)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	more := len(data) > pageSize
+	if more {
+		if backward {
+			data = data[1:]
+		} else {
+			data = data[:pageSize]
+		}
 	}
 
-	return s.sensorRepo.Update(ctx, id, data)
+	page := &dtos.CursorPage{
+		Data:     data,
+		PageSize: pageSize,
+	}
+	hasNext, hasPrev := more, params.Cursor != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if len(data) > 0 {
+		first, last := data[0], data[len(data)-1]
+		if hasNext {
+			page.NextCursor = dtos.Cursor{Timestamp: last.Timestamp, ID: last.ID, Order: order}.Encode()
+		}
+		if hasPrev {
+			page.PrevCursor = dtos.Cursor{Timestamp: first.Timestamp, ID: first.ID, Order: order, Before: true}.Encode()
+		}
+	} else if params.Cursor != nil {
+		// Past either end of the list, only the way back is left
+		turn := *params.Cursor
+		turn.Before = !backward
+		if backward {
+			page.NextCursor = turn.Encode()
+		} else {
+			page.PrevCursor = turn.Encode()
+		}
+	}
+
+	if params.WithTotal {
+		total, err := s.sensorRepo.Count(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+func (s *sensorService) AggregateSensorData(ctx context.Context, filter *dtos.SensorDataFilter, params *dtos.AggregateParams) ([]*dtos.AggregateBucket, error) {
+	buckets, err := s.sensorRepo.Aggregate(ctx, filter, params, dtos.MaxAggregateBuckets+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(buckets) > dtos.MaxAggregateBuckets {
+		return nil, ErrTooManyBuckets
+	}
+	return buckets, nil
+}
+
+// ExportSensorData passes all matching readings to fn in batches, ordered by timestamp
+func (s *sensorService) ExportSensorData(ctx context.Context, filter *dtos.SensorDataFilter, fn func(batch []*entities.SensorData) error) error {
+	return s.sensorRepo.Export(ctx, filter, dtos.ExportBatchSize, fn)
+}
+
+// UpdateSensorData updates a reading; the change is recorded in its history
+func (s *sensorService) UpdateSensorData(ctx context.Context, id uint, data *entities.SensorData) error {
+	return s.sensorRepo.Update(ctx, id, data, changeActor(ctx))
+}
+
+func (s *sensorService) CountSensorData(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error) {
+	return s.sensorRepo.Count(ctx, filter)
 }
 
 func (s *sensorService) DeleteSensorData(ctx context.Context, filter *dtos.SensorDataFilter) (int64, error) {
-	return s.sensorRepo.Delete(ctx, filter)
+	return s.DeleteSensorDataInChunks(ctx, filter, defaultDeleteChunkSize, nil)
+}
+
+// DeleteSensorDataInChunks deletes the matching readings chunkSize rows at a time, so no statement holds
+// locks on a large part of the table, and reports the running total after each chunk
+func (s *sensorService) DeleteSensorDataInChunks(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int, progress func(deleted int64)) (int64, error) {
+	by := changeActor(ctx)
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		n, err := s.sensorRepo.DeleteChunk(ctx, filter, chunkSize, by)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+		if n == 0 {
+			return deleted, nil
+		}
+		if progress != nil {
+			progress(deleted)
+		}
+	}
 }
 
+// DeleteSensorDataByID deletes a reading; the delete is recorded in its history
 func (s *sensorService) DeleteSensorDataByID(ctx context.Context, id uint) error {
-	// Check if record exists
-	_, err := s.sensorRepo.GetByID(ctx, id)
+	return s.sensorRepo.DeleteByID(ctx, id, changeActor(ctx))
+}
+
+// GetSensorDataHistory returns the updates, deletes and reverts of a reading, most recent first
+func (s *sensorService) GetSensorDataHistory(ctx context.Context, id uint) ([]*entities.SensorDataHistory, error) {
+	return s.sensorRepo.ListHistory(ctx, id)
+}
+
+// RevertSensorData sets a reading back to the values it had before a change of its history
+func (s *sensorService) RevertSensorData(ctx context.Context, id, historyID uint) (*entities.SensorData, error) {
+	return s.sensorRepo.Revert(ctx, id, historyID, changeActor(ctx))
+}
+
+// changeActor returns the user making a change, taken from the request context
+func changeActor(ctx context.Context) entities.ChangeActor {
+	a, _ := actor.FromContext(ctx)
+	return entities.ChangeActor{ActorID: a.UserID, ActorEmail: a.Email, RequestID: a.RequestID}
+}
+
+// ListDeletedSensorData returns a page of soft-deleted sensor data, most recently deleted first
+func (s *sensorService) ListDeletedSensorData(ctx context.Context, filter *dtos.SensorDataFilter, pagination *dtos.PaginationParams) (*dtos.PaginatedResponse, error) {
+	page, pageSize := 1, constants.DefaultPageSize
+	if pagination != nil {
+		if pagination.Page > 1 {
+			page = pagination.Page
+		}
+		if pagination.PageSize >= 1 && pagination.PageSize <= constants.MaxPageSize {
+			pageSize = pagination.PageSize
+		}
+	}
+
+	data, total, err := s.sensorRepo.ListDeleted(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]*dtos.DeletedSensorData, 0, len(data))
+	for _, item := range data {
+		deleted = append(deleted, &dtos.DeletedSensorData{SensorData: *item, DeletedAt: item.DeletedAt.Time})
+	}
+
+	return &dtos.PaginatedResponse{
+		Data:       deleted,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+	}, nil
+}
+
+// RestoreSensorData undeletes a soft-deleted reading, gorm.ErrRecordNotFound if it is not in the trash
+func (s *sensorService) RestoreSensorData(ctx context.Context, id uint) error {
+	restored, err := s.sensorRepo.Restore(ctx, []uint{id})
 	if err != nil {
 		return err
 	}
+	if restored == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RestoreSensorDataByFilter undeletes the soft-deleted readings matching the filter, chunkSize rows at a time
+func (s *sensorService) RestoreSensorDataByFilter(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int) (int64, error) {
+	return s.deletedInChunks(ctx, filter, chunkSize, s.sensorRepo.Restore)
+}
+
+// PurgeSensorData permanently removes a soft-deleted reading, gorm.ErrRecordNotFound if it is not in the trash
+func (s *sensorService) PurgeSensorData(ctx context.Context, id uint) error {
+	purged, err := s.sensorRepo.Purge(ctx, []uint{id})
+	if err != nil {
+		return err
+	}
+	if purged == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeSensorDataByFilter permanently removes the soft-deleted readings matching the filter, chunkSize rows at a time
+func (s *sensorService) PurgeSensorDataByFilter(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int) (int64, error) {
+	return s.deletedInChunks(ctx, filter, chunkSize, s.sensorRepo.Purge)
+}
 
-	return s.sensorRepo.DeleteByID(ctx, id)
+// deletedInChunks applies fn to the IDs of the soft-deleted readings matching the filter, chunk by chunk,
+// until none are left. fn must take the readings out of the trash.
+func (s *sensorService) deletedInChunks(ctx context.Context, filter *dtos.SensorDataFilter, chunkSize int, fn func(ctx context.Context, ids []uint) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		ids, err := s.sensorRepo.FindDeletedIDs(ctx, filter, chunkSize)
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+		n, err := fn(ctx, ids)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
 }
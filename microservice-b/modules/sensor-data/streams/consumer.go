@@ -0,0 +1,323 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/dtos"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/interfaces"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// ConsumerConfig holds Redis Stream ingestion settings
+type ConsumerConfig struct {
+	Stream           string        // Inbound stream producers append readings to
+	Group            string        // Consumer group shared by all replicas
+	Consumer         string        // Consumer name, unique per replica
+	DeadLetterStream string        // Stream invalid or repeatedly failing entries are moved to, empty to only log them
+	BatchSize        int64         // Maximum entries read per call
+	Block            time.Duration // How long a read waits for new entries
+	ClaimMinIdle     time.Duration // Pending entries idle this long are reclaimed and retried
+	ReapInterval     time.Duration // How often pending entries are checked
+	MaxDeliveries    int64         // Deliveries after which a pending entry is dead-lettered
+	IngestTimeout    time.Duration // Timeout for storing one batch
+}
+
+// Consumer ingests sensor readings from a Redis Stream through a consumer group.
+// An entry is acknowledged once it is stored, recognised as a duplicate or dead-lettered; entries that
+// fail on a transient error stay pending and are reclaimed by the reaper after ClaimMinIdle, including
+// entries left behind by a replica that crashed.
+type Consumer struct {
+	client        *redis.Client
+	sensorService interfaces.SensorServiceInterface
+	cfg           ConsumerConfig
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewConsumer creates a stream consumer
+func NewConsumer(client *redis.Client, sensorService interfaces.SensorServiceInterface, cfg ConsumerConfig) *Consumer {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = 2 * time.Second
+	}
+	if cfg.ClaimMinIdle <= 0 {
+		cfg.ClaimMinIdle = time.Minute
+	}
+	if cfg.ReapInterval <= 0 {
+		cfg.ReapInterval = 30 * time.Second
+	}
+	if cfg.MaxDeliveries < 1 {
+		cfg.MaxDeliveries = 5
+	}
+	if cfg.IngestTimeout <= 0 {
+		cfg.IngestTimeout = 10 * time.Second
+	}
+
+	return &Consumer{
+		client:        client,
+		sensorService: sensorService,
+		cfg:           cfg,
+	}
+}
+
+// Start creates the consumer group if needed and starts the read loop and the pending entry reaper
+func (c *Consumer) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := c.createGroup(ctx); err != nil {
+		cancel()
+		return err
+	}
+	c.cancel = cancel
+
+	c.wg.Add(2)
+	go c.readLoop(ctx)
+	go c.reapLoop(ctx)
+
+	utils.Info(fmt.Sprintf("Consuming sensor data from stream %s as %s/%s", c.cfg.Stream, c.cfg.Group, c.cfg.Consumer))
+	return nil
+}
+
+// Close stops consuming and waits for in-flight batches; a blocked read returns within Block
+func (c *Consumer) Close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+}
+
+// createGroup creates the stream and consumer group, reading only entries added from now on
+func (c *Consumer) createGroup(ctx context.Context) error {
+	err := c.client.XGroupCreateMkStream(ctx, c.cfg.Stream, c.cfg.Group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on %s: %w", c.cfg.Group, c.cfg.Stream, err)
+	}
+	return nil
+}
+
+// readLoop reads new entries for this consumer until ctx is cancelled
+func (c *Consumer) readLoop(ctx context.Context) {
+	defer c.wg.Done()
+
+	for ctx.Err() == nil {
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.cfg.Group,
+			Consumer: c.cfg.Consumer,
+			Streams:  []string{c.cfg.Stream, ">"},
+			Count:    c.cfg.BatchSize,
+			Block:    c.cfg.Block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			utils.Error(fmt.Sprintf("Failed to read from stream %s: %v", c.cfg.Stream, err))
+			// The stream or group may have been deleted
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				if err := c.createGroup(ctx); err != nil {
+					utils.Error(err.Error())
+				}
+			}
+			c.sleep(ctx, time.Second)
+			continue
+		}
+
+		for _, stream := range streams {
+			c.process(ctx, stream.Messages)
+		}
+	}
+}
+
+// reapLoop periodically retries or dead-letters entries that stayed pending too long
+func (c *Consumer) reapLoop(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.ReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.reap(ctx); err != nil && ctx.Err() == nil {
+				utils.Error(fmt.Sprintf("Failed to reclaim pending entries on %s: %v", c.cfg.Stream, err))
+			}
+		}
+	}
+}
+
+// reap claims pending entries idle for at least ClaimMinIdle. Entries delivered MaxDeliveries times
+// are dead-lettered, the others are processed again by this consumer.
+func (c *Consumer) reap(ctx context.Context) error {
+	pending, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.cfg.Stream,
+		Group:  c.cfg.Group,
+		Idle:   c.cfg.ClaimMinIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  c.cfg.BatchSize,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	var retry, exhausted []string
+	for _, entry := range pending {
+		if entry.RetryCount >= c.cfg.MaxDeliveries {
+			exhausted = append(exhausted, entry.ID)
+		} else {
+			retry = append(retry, entry.ID)
+		}
+	}
+
+	if len(exhausted) > 0 {
+		messages, err := c.claim(ctx, exhausted)
+		if err != nil {
+			return err
+		}
+		reason := fmt.Sprintf("not stored after %d deliveries", c.cfg.MaxDeliveries)
+		var done []string
+		for _, message := range messages {
+			if c.deadLetter(ctx, message, reason) {
+				done = append(done, message.ID)
+			}
+		}
+		c.ack(ctx, done)
+	}
+
+	if len(retry) > 0 {
+		messages, err := c.claim(ctx, retry)
+		if err != nil {
+			return err
+		}
+		if len(messages) > 0 {
+			utils.Info(fmt.Sprintf("Retrying %d pending entries from stream %s", len(messages), c.cfg.Stream))
+			c.process(ctx, messages)
+		}
+	}
+
+	return nil
+}
+
+// claim transfers pending entries to this consumer, entries claimed by another reaper meanwhile are skipped
+func (c *Consumer) claim(ctx context.Context, ids []string) ([]redis.XMessage, error) {
+	messages, err := c.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   c.cfg.Stream,
+		Group:    c.cfg.Group,
+		Consumer: c.cfg.Consumer,
+		MinIdle:  c.cfg.ClaimMinIdle,
+		Messages: ids,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return messages, err
+}
+
+// process stores a set of entries as one batch and acknowledges the ones that are done with
+func (c *Consumer) process(ctx context.Context, messages []redis.XMessage) {
+	if len(messages) == 0 {
+		return
+	}
+
+	now := time.Now()
+	var done []string
+	var readings []*entities.SensorData
+	var sources []redis.XMessage
+	for _, message := range messages {
+		reading, err := decodeFields(message.Values, now)
+		if err != nil {
+			if c.deadLetter(ctx, message, err.Error()) {
+				done = append(done, message.ID)
+			}
+			continue
+		}
+		readings = append(readings, reading)
+		sources = append(sources, message)
+	}
+
+	if len(readings) > 0 {
+		ingestCtx, cancel := context.WithTimeout(ctx, c.cfg.IngestTimeout)
+		result, err := c.sensorService.CreateSensorDataBatch(ingestCtx, readings)
+		cancel()
+
+		if err != nil {
+			utils.Warn(fmt.Sprintf("Failed to store %d entries from stream %s, leaving them pending: %v", len(readings), c.cfg.Stream, err))
+		} else {
+			for _, item := range result.Items {
+				source := sources[item.Index]
+				switch {
+				case item.Status == dtos.BatchItemRejected && item.Retryable:
+					// Left pending for the reaper
+				case item.Status == dtos.BatchItemRejected:
+					if c.deadLetter(ctx, source, item.Reason) {
+						done = append(done, source.ID)
+					}
+				default:
+					done = append(done, source.ID)
+				}
+			}
+		}
+	}
+
+	c.ack(ctx, done)
+}
+
+// ack acknowledges processed entries
+func (c *Consumer) ack(ctx context.Context, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	if err := c.client.XAck(ctx, c.cfg.Stream, c.cfg.Group, ids...).Err(); err != nil {
+		utils.Error(fmt.Sprintf("Failed to acknowledge %d entries on stream %s: %v", len(ids), c.cfg.Stream, err))
+	}
+}
+
+// deadLetter copies an entry that cannot be stored to the dead-letter stream along with the reason.
+// It returns false if the copy failed, in which case the entry must stay pending.
+func (c *Consumer) deadLetter(ctx context.Context, message redis.XMessage, reason string) bool {
+	utils.Warn(fmt.Sprintf("Dead-lettering stream entry %s from %s: %s", message.ID, c.cfg.Stream, reason))
+	if c.cfg.DeadLetterStream == "" {
+		return true
+	}
+
+	values := make(map[string]interface{}, len(message.Values)+2)
+	for key, value := range message.Values {
+		values[key] = value
+	}
+	values["source_id"] = message.ID
+	values["error"] = reason
+
+	if err := c.client.XAdd(ctx, &redis.XAddArgs{Stream: c.cfg.DeadLetterStream, Values: values}).Err(); err != nil {
+		utils.Error(fmt.Sprintf("Failed to dead-letter stream entry %s: %v", message.ID, err))
+		return false
+	}
+	return true
+}
+
+// sleep waits for d or until ctx is cancelled
+func (c *Consumer) sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,79 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+)
+
+// Stream entry field names, shared by the inbound and outbound streams
+const (
+	fieldID          = "id"
+	fieldSensorValue = "sensor_value"
+	fieldSensorType  = "sensor_type"
+	fieldID1         = "id1"
+	fieldID2         = "id2"
+	fieldTimestamp   = "timestamp"
+)
+
+// encodeFields converts a stored reading into stream entry fields
+func encodeFields(data *entities.SensorData) map[string]interface{} {
+	return map[string]interface{}{
+		fieldID:          strconv.FormatUint(uint64(data.ID), 10),
+		fieldSensorValue: strconv.FormatFloat(data.SensorValue, 'f', -1, 64),
+		fieldSensorType:  data.SensorType,
+		fieldID1:         data.ID1,
+		fieldID2:         strconv.FormatInt(int64(data.ID2), 10),
+		fieldTimestamp:   data.Timestamp.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// decodeFields parses an inbound stream entry. sensor_value, sensor_type and id1 are required;
+// id2 defaults to 0 and a missing timestamp to now.
+func decodeFields(values map[string]interface{}, now time.Time) (*entities.SensorData, error) {
+	data := &entities.SensorData{
+		SensorType: stringField(values, fieldSensorType),
+		ID1:        stringField(values, fieldID1),
+		Timestamp:  now,
+	}
+
+	value := stringField(values, fieldSensorValue)
+	if value == "" {
+		return nil, errors.New("sensor_value is required")
+	}
+	sensorValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sensor_value %q", value)
+	}
+	data.SensorValue = sensorValue
+
+	if id2 := stringField(values, fieldID2); id2 != "" {
+		parsed, err := strconv.ParseInt(id2, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id2 %q", id2)
+		}
+		data.ID2 = int32(parsed)
+	}
+
+	if timestamp := stringField(values, fieldTimestamp); timestamp != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q, expected RFC 3339", timestamp)
+		}
+		data.Timestamp = parsed
+	}
+
+	if err := data.Validate(now); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// stringField reads a field as a string, go-redis returns entry values as strings
+func stringField(values map[string]interface{}, name string) string {
+	value, _ := values[name].(string)
+	return value
+}
@@ -0,0 +1,58 @@
+package streams
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/entities"
+	"github.com/worlder-team/microservice-server/shared/utils"
+)
+
+// PublisherConfig holds change feed settings
+type PublisherConfig struct {
+	Stream  string        // Outbound stream every stored reading is appended to
+	MaxLen  int64         // Approximate stream length cap, 0 keeps every entry
+	Timeout time.Duration // Timeout for appending one batch
+}
+
+// Publisher appends stored sensor data to a Redis Stream so other services can consume a change feed.
+// It is registered with the sensor service as a SensorDataPublisher; delivery is best effort and a
+// failed append is logged without failing the ingestion request.
+type Publisher struct {
+	client *redis.Client
+	cfg    PublisherConfig
+}
+
+// NewPublisher creates a change feed publisher
+func NewPublisher(client *redis.Client, cfg PublisherConfig) *Publisher {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+	return &Publisher{client: client, cfg: cfg}
+}
+
+// Publish appends each reading as a stream entry in one round trip
+func (p *Publisher) Publish(ctx context.Context, data []*entities.SensorData) {
+	if len(data) == 0 {
+		return
+	}
+
+	ctx, cancel := utils.DetachedContext(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	pipe := p.client.Pipeline()
+	for _, item := range data {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: p.cfg.Stream,
+			MaxLen: p.cfg.MaxLen,
+			Approx: p.cfg.MaxLen > 0,
+			Values: encodeFields(item),
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		utils.Error(fmt.Sprintf("Failed to publish %d sensor readings to stream %s: %v", len(data), p.cfg.Stream, err))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
+	authServices "github.com/worlder-team/microservice-server/microservice-b/modules/auth/services"
 )
 
 type SeederService struct {
@@ -78,6 +79,34 @@ func (s *SeederService) SeedDefaultUsers() error {
 	return nil
 }
 
+// SeedAPIKey registers a pre-shared generator API key if it doesn't exist
+func (s *SeederService) SeedAPIKey(name, rawKey string, scopes []string) error {
+	keyHash := authServices.HashAPIKey(rawKey)
+
+	var existingKey entities.APIKey
+	err := s.db.Where("key_hash = ?", keyHash).First(&existingKey).Error
+	if err == nil {
+		log.Printf("API key %s already exists, skipping", name)
+		return nil
+	} else if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	apiKey := entities.APIKey{
+		Name:      name,
+		KeyPrefix: authServices.APIKeyDisplayPrefix(rawKey),
+		KeyHash:   keyHash,
+		Scopes:    entities.JoinList(scopes),
+	}
+
+	if err := s.db.Create(&apiKey).Error; err != nil {
+		return err
+	}
+
+	log.Printf("Created API key: %s", name)
+	return nil
+}
+
 // SeedAll runs all seeding operations
 func (s *SeederService) SeedAll() error {
 	log.Println("Starting database seeding...")
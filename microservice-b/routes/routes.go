@@ -2,14 +2,24 @@ package routes
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	"github.com/worlder-team/microservice-server/microservice-b/configs"
 	"github.com/worlder-team/microservice-server/microservice-b/docs"
+	alertHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/alerts/handlers"
+	"github.com/worlder-team/microservice-server/microservice-b/modules/audit/actor"
+	auditHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/audit/handlers"
+	authEntities "github.com/worlder-team/microservice-server/microservice-b/modules/auth/entities"
 	authHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/auth/handlers"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/interfaces"
+	generatorHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/generators/handlers"
 	healthHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/health/handlers"
+	jobHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/jobs/handlers"
+	retentionHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/retention/handlers"
 	sensorHandlers "github.com/worlder-team/microservice-server/microservice-b/modules/sensor-data/handlers"
+	"github.com/worlder-team/microservice-server/shared/constants"
 	sharedMiddleware "github.com/worlder-team/microservice-server/shared/middleware"
 )
 
@@ -21,30 +31,70 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+// @securityDefinitions.apikey APIKey
+// @in header
+// @name X-API-Key
+// @description Device API key issued through /auth/api-keys.
 
 // Router holds all dependencies needed for routing
 type Router struct {
-	sensorHandler *sensorHandlers.SensorHandler
-	authHandler   *authHandlers.AuthHandler
-	healthHandler *healthHandlers.HealthHandler
-	jwtService    interfaces.JWTServiceInterface
-	config        *configs.Config
+	sensorHandler    *sensorHandlers.SensorHandler
+	liveHandler      *sensorHandlers.LiveHandler
+	authHandler      *authHandlers.AuthHandler
+	apiKeyHandler    *authHandlers.APIKeyHandler
+	generatorHandler *generatorHandlers.GeneratorHandler
+	ingestHandler    *sensorHandlers.IngestHandler
+	importHandler    *sensorHandlers.ImportHandler
+	deleteHandler    *sensorHandlers.DeleteQueryHandler
+	trashHandler     *sensorHandlers.TrashHandler
+	jobHandler       *jobHandlers.JobHandler
+	retentionHandler *retentionHandlers.RetentionHandler
+	auditHandler     *auditHandlers.AuditHandler
+	alertHandler     *alertHandlers.AlertHandler
+	healthHandler    *healthHandlers.HealthHandler
+	jwtService       interfaces.JWTServiceInterface
+	apiKeyService    interfaces.APIKeyServiceInterface
+	config           *configs.Config
 }
 
 // NewRouter creates a new router instance
 func NewRouter(
 	sensorHandler *sensorHandlers.SensorHandler,
+	liveHandler *sensorHandlers.LiveHandler,
 	authHandler *authHandlers.AuthHandler,
+	apiKeyHandler *authHandlers.APIKeyHandler,
+	generatorHandler *generatorHandlers.GeneratorHandler,
+	ingestHandler *sensorHandlers.IngestHandler,
+	importHandler *sensorHandlers.ImportHandler,
+	deleteHandler *sensorHandlers.DeleteQueryHandler,
+	trashHandler *sensorHandlers.TrashHandler,
+	jobHandler *jobHandlers.JobHandler,
+	retentionHandler *retentionHandlers.RetentionHandler,
+	auditHandler *auditHandlers.AuditHandler,
+	alertHandler *alertHandlers.AlertHandler,
 	healthHandler *healthHandlers.HealthHandler,
 	jwtService interfaces.JWTServiceInterface,
+	apiKeyService interfaces.APIKeyServiceInterface,
 	config *configs.Config,
 ) *Router {
 	return &Router{
-		sensorHandler: sensorHandler,
-		authHandler:   authHandler,
-		healthHandler: healthHandler,
-		jwtService:    jwtService,
-		config:        config,
+		sensorHandler:    sensorHandler,
+		liveHandler:      liveHandler,
+		authHandler:      authHandler,
+		apiKeyHandler:    apiKeyHandler,
+		generatorHandler: generatorHandler,
+		ingestHandler:    ingestHandler,
+		importHandler:    importHandler,
+		deleteHandler:    deleteHandler,
+		trashHandler:     trashHandler,
+		jobHandler:       jobHandler,
+		retentionHandler: retentionHandler,
+		auditHandler:     auditHandler,
+		alertHandler:     alertHandler,
+		healthHandler:    healthHandler,
+		jwtService:       jwtService,
+		apiKeyService:    apiKeyService,
+		config:           config,
 	}
 }
 
@@ -56,6 +106,9 @@ func (r *Router) SetupRoutes(e *echo.Echo) {
 	// Swagger documentation
 	r.setupSwaggerRoutes(e)
 
+	// Prometheus metrics
+	r.setupMetricsRoutes(e)
+
 	// Setup API versions
 	r.setupV1Routes(e)
 	// Future: r.setupV2Routes(e) - when you need API v2
@@ -68,7 +121,14 @@ func (r *Router) setupV1Routes(e *echo.Echo) {
 	// Module-specific routes for v1
 	r.setupHealthRoutes(v1)
 	r.setupAuthRoutes(v1)
+	r.setupLiveRoutes(v1)
 	r.setupSensorRoutes(v1)
+	r.setupGeneratorRoutes(v1)
+	r.setupIngestRoutes(v1)
+	r.setupRetentionRoutes(v1)
+	r.setupJobRoutes(v1)
+	r.setupAuditRoutes(v1)
+	r.setupAlertRoutes(v1)
 }
 
 // setupSwaggerRoutes configures Swagger documentation routes
@@ -76,6 +136,11 @@ func (r *Router) setupSwaggerRoutes(e *echo.Echo) {
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 }
 
+// setupMetricsRoutes exposes Prometheus metrics
+func (r *Router) setupMetricsRoutes(e *echo.Echo) {
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+}
+
 // setupHealthRoutes configures health check routes
 func (r *Router) setupHealthRoutes(api *echo.Group) {
 	api.GET("/health", r.healthHandler.Health)
@@ -85,17 +150,124 @@ func (r *Router) setupHealthRoutes(api *echo.Group) {
 func (r *Router) setupAuthRoutes(api *echo.Group) {
 	auth := api.Group("/auth")
 	auth.POST("/login", r.authHandler.Login)
+
+	// Generator API key management (admin only)
+	apiKeys := auth.Group("/api-keys")
+	apiKeys.Use(sharedMiddleware.JWTAuth(r.jwtService))
+	apiKeys.Use(sharedMiddleware.RequireRole(constants.RoleAdmin))
+
+	apiKeys.GET("", r.apiKeyHandler.List)
+	apiKeys.POST("", r.apiKeyHandler.Create)
+	apiKeys.DELETE("/:id", r.apiKeyHandler.Revoke)
 }
 
 // setupSensorRoutes configures sensor data routes (protected)
 func (r *Router) setupSensorRoutes(api *echo.Group) {
 	sensors := api.Group("/sensors")
 	sensors.Use(sharedMiddleware.JWTAuth(r.jwtService))
+	sensors.Use(actor.Middleware())
 
 	sensors.GET("", r.sensorHandler.List)
 	sensors.GET("/duration", r.sensorHandler.GetByDuration)
+	sensors.GET("/aggregate", r.sensorHandler.Aggregate)
+	sensors.GET("/export", r.sensorHandler.Export)
+	sensors.POST("/import", r.importHandler.Import, middleware.BodyLimit(r.config.Import.MaxSize))
+	sensors.POST("/delete-query", r.deleteHandler.DeleteQuery, sharedMiddleware.RequireRole(constants.RoleAdmin))
 	sensors.GET("/:id", r.sensorHandler.GetByID)
 	sensors.GET("/:id1/:id2", r.sensorHandler.GetByIDCombination)
+	sensors.GET("/:id/history", r.sensorHandler.History)
+	sensors.POST("/:id/history/:history_id/revert", r.sensorHandler.Revert)
 	sensors.PATCH("/:id", r.sensorHandler.Update)
 	sensors.DELETE("/:id", r.sensorHandler.Delete)
+
+	// Soft-deleted sensor data (admin only)
+	trash := sensors.Group("/trash")
+	trash.Use(sharedMiddleware.RequireRole(constants.RoleAdmin))
+
+	trash.GET("", r.trashHandler.List)
+	trash.POST("/restore", r.trashHandler.RestoreByFilter)
+	trash.POST("/purge", r.trashHandler.PurgeByFilter)
+	trash.POST("/:id/restore", r.trashHandler.Restore)
+	trash.DELETE("/:id", r.trashHandler.Purge)
+}
+
+// setupLiveRoutes configures the live sensor data feeds (protected). The token may be sent as a query
+// parameter since browsers cannot set headers on EventSource and WebSocket requests.
+func (r *Router) setupLiveRoutes(api *echo.Group) {
+	live := api.Group("/sensors/live")
+	live.Use(sharedMiddleware.TokenFromQuery(constants.AccessTokenParam))
+	live.Use(sharedMiddleware.JWTAuth(r.jwtService))
+
+	live.GET("", r.liveHandler.SSE)
+	live.GET("/ws", r.liveHandler.WebSocket)
+}
+
+// setupGeneratorRoutes configures generator control plane routes (admin only)
+func (r *Router) setupGeneratorRoutes(api *echo.Group) {
+	generators := api.Group("/generators")
+	generators.Use(sharedMiddleware.JWTAuth(r.jwtService))
+	generators.Use(sharedMiddleware.RequireRole(constants.RoleAdmin))
+
+	generators.GET("", r.generatorHandler.List)
+	generators.GET("/:id", r.generatorHandler.Get)
+	generators.POST("/:id/start", r.generatorHandler.Start)
+	generators.POST("/:id/stop", r.generatorHandler.Stop)
+	generators.PUT("/:id/frequency", r.generatorHandler.SetFrequency)
+}
+
+// setupIngestRoutes configures HTTP/JSON ingestion routes for devices (API key)
+func (r *Router) setupIngestRoutes(api *echo.Group) {
+	ingest := api.Group("/ingest")
+	ingest.Use(middleware.BodyLimit("4M"))
+	ingest.Use(sharedMiddleware.APIKeyAuth(r.apiKeyService, authEntities.APIKeyScopeSensorWrite))
+
+	ingest.POST("", r.ingestHandler.Ingest)
+	ingest.POST("/batch", r.ingestHandler.IngestBatch)
+}
+
+// setupRetentionRoutes configures data retention routes (admin only)
+func (r *Router) setupRetentionRoutes(api *echo.Group) {
+	retention := api.Group("/retention")
+	retention.Use(sharedMiddleware.JWTAuth(r.jwtService))
+	retention.Use(sharedMiddleware.RequireRole(constants.RoleAdmin))
+
+	retention.GET("/policies", r.retentionHandler.ListPolicies)
+	retention.PUT("/policies/:sensor_type", r.retentionHandler.SetPolicy)
+	retention.DELETE("/policies/:sensor_type", r.retentionHandler.DeletePolicy)
+	retention.GET("/preview", r.retentionHandler.Preview)
+	retention.POST("/purge", r.retentionHandler.Purge)
+}
+
+// setupJobRoutes configures background job routes (protected)
+func (r *Router) setupJobRoutes(api *echo.Group) {
+	jobs := api.Group("/jobs")
+	jobs.Use(sharedMiddleware.JWTAuth(r.jwtService))
+	jobs.Use(actor.Middleware())
+
+	jobs.GET("/:id", r.jobHandler.Get)
+}
+
+// setupAuditRoutes configures audit trail routes (admin only)
+func (r *Router) setupAuditRoutes(api *echo.Group) {
+	audit := api.Group("/audit")
+	audit.Use(sharedMiddleware.JWTAuth(r.jwtService))
+	audit.Use(sharedMiddleware.RequireRole(constants.RoleAdmin))
+
+	audit.GET("", r.auditHandler.List)
+}
+
+// setupAlertRoutes configures alert rule and alert routes (protected)
+func (r *Router) setupAlertRoutes(api *echo.Group) {
+	alerts := api.Group("/alerts")
+	alerts.Use(sharedMiddleware.JWTAuth(r.jwtService))
+	alerts.Use(actor.Middleware())
+
+	alerts.GET("", r.alertHandler.List)
+	alerts.GET("/rules", r.alertHandler.ListRules)
+	alerts.POST("/rules", r.alertHandler.CreateRule)
+	alerts.GET("/rules/:id", r.alertHandler.GetRule)
+	alerts.PUT("/rules/:id", r.alertHandler.UpdateRule)
+	alerts.DELETE("/rules/:id", r.alertHandler.DeleteRule)
+	alerts.GET("/:id", r.alertHandler.Get)
+	alerts.POST("/:id/acknowledge", r.alertHandler.Acknowledge)
 }
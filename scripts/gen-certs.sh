@@ -0,0 +1,54 @@
+#!/usr/bin/env sh
+# Generates a local development CA plus server and client certificates for the gRPC channel.
+# Usage: scripts/gen-certs.sh [output-dir]   (default: ./certs)
+# Existing files are kept; delete the directory to rotate everything.
+set -eu
+
+OUT_DIR="${1:-certs}"
+DAYS="${CERT_DAYS:-825}"
+SERVER_NAMES="${CERT_SERVER_NAMES:-DNS:microservice-b,DNS:localhost,IP:127.0.0.1}"
+
+mkdir -p "$OUT_DIR"
+cd "$OUT_DIR"
+
+if [ ! -f ca.key ]; then
+	echo "Generating development CA..."
+	openssl req -x509 -newkey rsa:4096 -nodes -sha256 -days "$DAYS" \
+		-keyout ca.key -out ca.crt -subj "/CN=worlder-dev-ca"
+fi
+
+# issue <name> <common-name> <extended-key-usage> [subject-alt-names]
+issue() {
+	name="$1"
+	cn="$2"
+	eku="$3"
+	san="${4:-}"
+
+	if [ -f "$name.crt" ]; then
+		echo "$name.crt already exists, skipping"
+		return
+	fi
+
+	echo "Generating $name certificate..."
+	openssl req -newkey rsa:2048 -nodes -sha256 -keyout "$name.key" -out "$name.csr" -subj "/CN=$cn"
+
+	{
+		echo "basicConstraints=CA:FALSE"
+		echo "keyUsage=digitalSignature,keyEncipherment"
+		echo "extendedKeyUsage=$eku"
+		if [ -n "$san" ]; then
+			echo "subjectAltName=$san"
+		fi
+	} > "$name.ext"
+
+	openssl x509 -req -in "$name.csr" -CA ca.crt -CAkey ca.key -CAcreateserial \
+		-days "$DAYS" -sha256 -extfile "$name.ext" -out "$name.crt"
+	rm -f "$name.csr" "$name.ext"
+}
+
+issue server microservice-b serverAuth "$SERVER_NAMES"
+issue client microservice-a-generator clientAuth
+
+chmod 644 ./*.crt
+chmod 600 ./*.key
+echo "Certificates written to $OUT_DIR"
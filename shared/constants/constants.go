@@ -11,6 +11,17 @@ const (
 	SensorTypePressure    = "pressure"
 	SensorTypeLight       = "light"
 	SensorTypeMotion      = "motion"
+
+	// User roles
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+
+	// gRPC metadata key carrying generator API keys
+	APIKeyMetadataKey = "x-api-key"
+	// HTTP header carrying device API keys
+	APIKeyHeader = "X-API-Key"
+	// Query parameter carrying a JWT on the live feed, kept out of access logs
+	AccessTokenParam = "access_token"
 )
 
 // Error messages
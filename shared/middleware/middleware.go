@@ -11,6 +11,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/worlder-team/microservice-server/microservice-b/modules/auth/interfaces"
 	"github.com/worlder-team/microservice-server/shared/constants"
+	"github.com/worlder-team/microservice-server/shared/utils"
 )
 
 // ErrorResponse represents error response structure
@@ -81,7 +82,7 @@ func CORS() echo.MiddlewareFunc {
 			}
 
 			c.Response().Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
-			c.Response().Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,X-Requested-With")
+			c.Response().Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,X-Requested-With,X-API-Key")
 			c.Response().Header().Set("Access-Control-Allow-Credentials", "true")
 
 			if c.Request().Method == "OPTIONS" {
@@ -150,6 +151,112 @@ func JWTAuth(jwtService interfaces.JWTServiceInterface) echo.MiddlewareFunc {
 	}
 }
 
+// TokenFromQuery middleware copies a bearer token from the query parameter param into the
+// Authorization header when the header is missing, for clients such as EventSource and browser
+// WebSockets that cannot set headers. It must run before JWTAuth; register RedactQueryParams
+// with the same parameter so the token does not end up in access logs.
+func TokenFromQuery(param string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token := c.QueryParam(param); token != "" && c.Request().Header.Get("Authorization") == "" {
+				c.Request().Header.Set("Authorization", "Bearer "+token)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RedactQueryParams middleware replaces the values of the given query parameters in the request
+// URI once the request has been handled, so that the access log written by the Logger middleware,
+// which must be registered before it, does not contain them.
+func RedactQueryParams(params ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			defer redactQuery(c.Request(), params)
+			return next(c)
+		}
+	}
+}
+
+// redactQuery replaces the values of params in the URL and RequestURI of req
+func redactQuery(req *http.Request, params []string) {
+	if req.URL.RawQuery == "" {
+		return
+	}
+
+	query := req.URL.Query()
+	redacted := false
+	for _, param := range params {
+		if query.Has(param) {
+			query.Set(param, "redacted")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return
+	}
+
+	req.URL.RawQuery = query.Encode()
+	req.RequestURI = req.URL.RequestURI()
+}
+
+// RequireRole middleware restricts access to authenticated users with one of the given roles.
+// It must run after JWTAuth.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get("user_role").(string)
+			if !utils.Contains(roles, role) {
+				return c.JSON(http.StatusForbidden, ErrorResponse{
+					Status:  constants.StatusError,
+					Message: constants.ErrForbidden,
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// APIKeyAuth middleware authenticates devices with an API key sent in the X-API-Key header.
+// The key must grant scope; the resolved key is stored as "api_key" in the context.
+func APIKeyAuth(apiKeyService interfaces.APIKeyServiceInterface, scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rawKey := c.Request().Header.Get(constants.APIKeyHeader)
+			if rawKey == "" {
+				return c.JSON(http.StatusUnauthorized, ErrorResponse{
+					Status:  constants.StatusError,
+					Message: constants.ErrUnauthorized,
+					Error:   "missing api key",
+				})
+			}
+
+			apiKey, err := apiKeyService.ValidateAPIKey(c.Request().Context(), rawKey)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, ErrorResponse{
+					Status:  constants.StatusError,
+					Message: constants.ErrUnauthorized,
+					Error:   err.Error(),
+				})
+			}
+
+			if !apiKey.HasScope(scope) {
+				return c.JSON(http.StatusForbidden, ErrorResponse{
+					Status:  constants.StatusError,
+					Message: constants.ErrForbidden,
+					Error:   fmt.Sprintf("api key lacks scope %s", scope),
+				})
+			}
+
+			c.Set("api_key", apiKey)
+
+			return next(c)
+		}
+	}
+}
+
 // Security headers middleware
 func SecurityHeaders() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
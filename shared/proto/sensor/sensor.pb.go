@@ -8,6 +8,7 @@ package sensor
 
 import (
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -22,6 +23,160 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SensorItemResult_Status int32
+
+const (
+	SensorItemResult_UNKNOWN   SensorItemResult_Status = 0
+	SensorItemResult_ACCEPTED  SensorItemResult_Status = 1
+	SensorItemResult_DUPLICATE SensorItemResult_Status = 2
+	SensorItemResult_REJECTED  SensorItemResult_Status = 3
+)
+
+// Enum value maps for SensorItemResult_Status.
+var (
+	SensorItemResult_Status_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "ACCEPTED",
+		2: "DUPLICATE",
+		3: "REJECTED",
+	}
+	SensorItemResult_Status_value = map[string]int32{
+		"UNKNOWN":   0,
+		"ACCEPTED":  1,
+		"DUPLICATE": 2,
+		"REJECTED":  3,
+	}
+)
+
+func (x SensorItemResult_Status) Enum() *SensorItemResult_Status {
+	p := new(SensorItemResult_Status)
+	*p = x
+	return p
+}
+
+func (x SensorItemResult_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SensorItemResult_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_sensor_sensor_proto_enumTypes[0].Descriptor()
+}
+
+func (SensorItemResult_Status) Type() protoreflect.EnumType {
+	return &file_shared_proto_sensor_sensor_proto_enumTypes[0]
+}
+
+func (x SensorItemResult_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SensorItemResult_Status.Descriptor instead.
+func (SensorItemResult_Status) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{3, 0}
+}
+
+// What the server does when the subscriber falls behind
+type WatchRequest_SlowConsumerPolicy int32
+
+const (
+	WatchRequest_DEFAULT     WatchRequest_SlowConsumerPolicy = 0
+	WatchRequest_DROP_OLDEST WatchRequest_SlowConsumerPolicy = 1
+	WatchRequest_DISCONNECT  WatchRequest_SlowConsumerPolicy = 2
+)
+
+// Enum value maps for WatchRequest_SlowConsumerPolicy.
+var (
+	WatchRequest_SlowConsumerPolicy_name = map[int32]string{
+		0: "DEFAULT",
+		1: "DROP_OLDEST",
+		2: "DISCONNECT",
+	}
+	WatchRequest_SlowConsumerPolicy_value = map[string]int32{
+		"DEFAULT":     0,
+		"DROP_OLDEST": 1,
+		"DISCONNECT":  2,
+	}
+)
+
+func (x WatchRequest_SlowConsumerPolicy) Enum() *WatchRequest_SlowConsumerPolicy {
+	p := new(WatchRequest_SlowConsumerPolicy)
+	*p = x
+	return p
+}
+
+func (x WatchRequest_SlowConsumerPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchRequest_SlowConsumerPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_sensor_sensor_proto_enumTypes[1].Descriptor()
+}
+
+func (WatchRequest_SlowConsumerPolicy) Type() protoreflect.EnumType {
+	return &file_shared_proto_sensor_sensor_proto_enumTypes[1]
+}
+
+func (x WatchRequest_SlowConsumerPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchRequest_SlowConsumerPolicy.Descriptor instead.
+func (WatchRequest_SlowConsumerPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{12, 0}
+}
+
+type GeneratorCommand_Action int32
+
+const (
+	GeneratorCommand_UNKNOWN       GeneratorCommand_Action = 0
+	GeneratorCommand_START         GeneratorCommand_Action = 1
+	GeneratorCommand_STOP          GeneratorCommand_Action = 2
+	GeneratorCommand_SET_FREQUENCY GeneratorCommand_Action = 3
+)
+
+// Enum value maps for GeneratorCommand_Action.
+var (
+	GeneratorCommand_Action_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "START",
+		2: "STOP",
+		3: "SET_FREQUENCY",
+	}
+	GeneratorCommand_Action_value = map[string]int32{
+		"UNKNOWN":       0,
+		"START":         1,
+		"STOP":          2,
+		"SET_FREQUENCY": 3,
+	}
+)
+
+func (x GeneratorCommand_Action) Enum() *GeneratorCommand_Action {
+	p := new(GeneratorCommand_Action)
+	*p = x
+	return p
+}
+
+func (x GeneratorCommand_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GeneratorCommand_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_sensor_sensor_proto_enumTypes[2].Descriptor()
+}
+
+func (GeneratorCommand_Action) Type() protoreflect.EnumType {
+	return &file_shared_proto_sensor_sensor_proto_enumTypes[2]
+}
+
+func (x GeneratorCommand_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GeneratorCommand_Action.Descriptor instead.
+func (GeneratorCommand_Action) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{17, 0}
+}
+
 type HealthCheckResponse_ServingStatus int32
 
 const (
@@ -55,11 +210,11 @@ func (x HealthCheckResponse_ServingStatus) String() string {
 }
 
 func (HealthCheckResponse_ServingStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_shared_proto_sensor_sensor_proto_enumTypes[0].Descriptor()
+	return file_shared_proto_sensor_sensor_proto_enumTypes[3].Descriptor()
 }
 
 func (HealthCheckResponse_ServingStatus) Type() protoreflect.EnumType {
-	return &file_shared_proto_sensor_sensor_proto_enumTypes[0]
+	return &file_shared_proto_sensor_sensor_proto_enumTypes[3]
 }
 
 func (x HealthCheckResponse_ServingStatus) Number() protoreflect.EnumNumber {
@@ -68,7 +223,7 @@ func (x HealthCheckResponse_ServingStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use HealthCheckResponse_ServingStatus.Descriptor instead.
 func (HealthCheckResponse_ServingStatus) EnumDescriptor() ([]byte, []int) {
-	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{4, 0}
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{19, 0}
 }
 
 // Sensor data message
@@ -254,28 +409,32 @@ func (x *SensorDataBatch) GetData() []*SensorData {
 	return nil
 }
 
-// Health check messages
-type HealthCheckRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+// Outcome of a single item in a batch
+type SensorItemResult struct {
+	state  protoimpl.MessageState  `protogen:"open.v1"`
+	Index  int32                   `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Status SensorItemResult_Status `protobuf:"varint,2,opt,name=status,proto3,enum=sensor.SensorItemResult_Status" json:"status,omitempty"`
+	Reason string                  `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Set for rejections caused by transient failures, the item may be sent again
+	Retryable     bool `protobuf:"varint,4,opt,name=retryable,proto3" json:"retryable,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckRequest) Reset() {
-	*x = HealthCheckRequest{}
+func (x *SensorItemResult) Reset() {
+	*x = SensorItemResult{}
 	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckRequest) String() string {
+func (x *SensorItemResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckRequest) ProtoMessage() {}
+func (*SensorItemResult) ProtoMessage() {}
 
-func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+func (x *SensorItemResult) ProtoReflect() protoreflect.Message {
 	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -287,39 +446,67 @@ func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
-func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SensorItemResult.ProtoReflect.Descriptor instead.
+func (*SensorItemResult) Descriptor() ([]byte, []int) {
 	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *HealthCheckRequest) GetService() string {
+func (x *SensorItemResult) GetIndex() int32 {
 	if x != nil {
-		return x.Service
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SensorItemResult) GetStatus() SensorItemResult_Status {
+	if x != nil {
+		return x.Status
+	}
+	return SensorItemResult_UNKNOWN
+}
+
+func (x *SensorItemResult) GetReason() string {
+	if x != nil {
+		return x.Reason
 	}
 	return ""
 }
 
-type HealthCheckResponse struct {
-	state         protoimpl.MessageState            `protogen:"open.v1"`
-	Status        HealthCheckResponse_ServingStatus `protobuf:"varint,1,opt,name=status,proto3,enum=sensor.HealthCheckResponse_ServingStatus" json:"status,omitempty"`
+func (x *SensorItemResult) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+// Response for batch operations, fields 1-3 match SensorResponse
+type SensorBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Results       []*SensorItemResult    `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	Accepted      int32                  `protobuf:"varint,5,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Duplicates    int32                  `protobuf:"varint,6,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Rejected      int32                  `protobuf:"varint,7,opt,name=rejected,proto3" json:"rejected,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckResponse) Reset() {
-	*x = HealthCheckResponse{}
+func (x *SensorBatchResponse) Reset() {
+	*x = SensorBatchResponse{}
 	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckResponse) String() string {
+func (x *SensorBatchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckResponse) ProtoMessage() {}
+func (*SensorBatchResponse) ProtoMessage() {}
 
-func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+func (x *SensorBatchResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -331,104 +518,1371 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
-func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SensorBatchResponse.ProtoReflect.Descriptor instead.
+func (*SensorBatchResponse) Descriptor() ([]byte, []int) {
 	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *HealthCheckResponse) GetStatus() HealthCheckResponse_ServingStatus {
+func (x *SensorBatchResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Status
+		return x.Success
 	}
-	return HealthCheckResponse_UNKNOWN
+	return false
 }
 
-var File_shared_proto_sensor_sensor_proto protoreflect.FileDescriptor
+func (x *SensorBatchResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
 
-const file_shared_proto_sensor_sensor_proto_rawDesc = "" +
-	"\n" +
-	" shared/proto/sensor/sensor.proto\x12\x06sensor\x1a\x1fgoogle/protobuf/timestamp.proto\"\xae\x01\n" +
-	"\n" +
-	"SensorData\x12!\n" +
-	"\fsensor_value\x18\x01 \x01(\x01R\vsensorValue\x12\x1f\n" +
-	"\vsensor_type\x18\x02 \x01(\tR\n" +
-	"sensorType\x12\x10\n" +
-	"\x03id1\x18\x03 \x01(\tR\x03id1\x12\x10\n" +
-	"\x03id2\x18\x04 \x01(\x05R\x03id2\x128\n" +
-	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"Z\n" +
-	"\x0eSensorResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error\"9\n" +
-	"\x0fSensorDataBatch\x12&\n" +
-	"\x04data\x18\x01 \x03(\v2\x12.sensor.SensorDataR\x04data\".\n" +
-	"\x12HealthCheckRequest\x12\x18\n" +
-	"\aservice\x18\x01 \x01(\tR\aservice\"\x94\x01\n" +
-	"\x13HealthCheckResponse\x12A\n" +
-	"\x06status\x18\x01 \x01(\x0e2).sensor.HealthCheckResponse.ServingStatusR\x06status\":\n" +
-	"\rServingStatus\x12\v\n" +
-	"\aUNKNOWN\x10\x00\x12\v\n" +
-	"\aSERVING\x10\x01\x12\x0f\n" +
-	"\vNOT_SERVING\x10\x022\xdd\x01\n" +
-	"\rSensorService\x12<\n" +
-	"\x0eSendSensorData\x12\x12.sensor.SensorData\x1a\x16.sensor.SensorResponse\x12F\n" +
-	"\x13SendSensorDataBatch\x12\x17.sensor.SensorDataBatch\x1a\x16.sensor.SensorResponse\x12F\n" +
-	"\vHealthCheck\x12\x1a.sensor.HealthCheckRequest\x1a\x1b.sensor.HealthCheckResponseBAZ?github.com/worlder-team/microservice-server/shared/proto/sensorb\x06proto3"
+func (x *SensorBatchResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
 
-var (
-	file_shared_proto_sensor_sensor_proto_rawDescOnce sync.Once
-	file_shared_proto_sensor_sensor_proto_rawDescData []byte
-)
+func (x *SensorBatchResponse) GetResults() []*SensorItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
 
-func file_shared_proto_sensor_sensor_proto_rawDescGZIP() []byte {
-	file_shared_proto_sensor_sensor_proto_rawDescOnce.Do(func() {
-		file_shared_proto_sensor_sensor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shared_proto_sensor_sensor_proto_rawDesc), len(file_shared_proto_sensor_sensor_proto_rawDesc)))
-	})
-	return file_shared_proto_sensor_sensor_proto_rawDescData
+func (x *SensorBatchResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
 }
 
-var file_shared_proto_sensor_sensor_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_shared_proto_sensor_sensor_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_shared_proto_sensor_sensor_proto_goTypes = []any{
-	(HealthCheckResponse_ServingStatus)(0), // 0: sensor.HealthCheckResponse.ServingStatus
-	(*SensorData)(nil),                     // 1: sensor.SensorData
-	(*SensorResponse)(nil),                 // 2: sensor.SensorResponse
-	(*SensorDataBatch)(nil),                // 3: sensor.SensorDataBatch
-	(*HealthCheckRequest)(nil),             // 4: sensor.HealthCheckRequest
-	(*HealthCheckResponse)(nil),            // 5: sensor.HealthCheckResponse
-	(*timestamp.Timestamp)(nil),            // 6: google.protobuf.Timestamp
+func (x *SensorBatchResponse) GetDuplicates() int32 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
 }
-var file_shared_proto_sensor_sensor_proto_depIdxs = []int32{
-	6, // 0: sensor.SensorData.timestamp:type_name -> google.protobuf.Timestamp
-	1, // 1: sensor.SensorDataBatch.data:type_name -> sensor.SensorData
-	0, // 2: sensor.HealthCheckResponse.status:type_name -> sensor.HealthCheckResponse.ServingStatus
-	1, // 3: sensor.SensorService.SendSensorData:input_type -> sensor.SensorData
-	3, // 4: sensor.SensorService.SendSensorDataBatch:input_type -> sensor.SensorDataBatch
-	4, // 5: sensor.SensorService.HealthCheck:input_type -> sensor.HealthCheckRequest
-	2, // 6: sensor.SensorService.SendSensorData:output_type -> sensor.SensorResponse
-	2, // 7: sensor.SensorService.SendSensorDataBatch:output_type -> sensor.SensorResponse
-	5, // 8: sensor.SensorService.HealthCheck:output_type -> sensor.HealthCheckResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+
+func (x *SensorBatchResponse) GetRejected() int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
 }
 
-func init() { file_shared_proto_sensor_sensor_proto_init() }
-func file_shared_proto_sensor_sensor_proto_init() {
-	if File_shared_proto_sensor_sensor_proto != nil {
-		return
+// Stored sensor data record returned by query operations
+type SensorDataRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SensorValue   float64                `protobuf:"fixed64,2,opt,name=sensor_value,json=sensorValue,proto3" json:"sensor_value,omitempty"`
+	SensorType    string                 `protobuf:"bytes,3,opt,name=sensor_type,json=sensorType,proto3" json:"sensor_type,omitempty"`
+	Id1           string                 `protobuf:"bytes,4,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2           int32                  `protobuf:"varint,5,opt,name=id2,proto3" json:"id2,omitempty"`
+	Timestamp     *timestamp.Timestamp   `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	CreatedAt     *timestamp.Timestamp   `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamp.Timestamp   `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SensorDataRecord) Reset() {
+	*x = SensorDataRecord{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SensorDataRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorDataRecord) ProtoMessage() {}
+
+func (x *SensorDataRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorDataRecord.ProtoReflect.Descriptor instead.
+func (*SensorDataRecord) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SensorDataRecord) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SensorDataRecord) GetSensorValue() float64 {
+	if x != nil {
+		return x.SensorValue
+	}
+	return 0
+}
+
+func (x *SensorDataRecord) GetSensorType() string {
+	if x != nil {
+		return x.SensorType
+	}
+	return ""
+}
+
+func (x *SensorDataRecord) GetId1() string {
+	if x != nil {
+		return x.Id1
+	}
+	return ""
+}
+
+func (x *SensorDataRecord) GetId2() int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return 0
+}
+
+func (x *SensorDataRecord) GetTimestamp() *timestamp.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SensorDataRecord) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SensorDataRecord) GetUpdatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Filter criteria for sensor data queries, unset fields are not applied
+type SensorDataFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SensorType    *wrappers.StringValue  `protobuf:"bytes,1,opt,name=sensor_type,json=sensorType,proto3" json:"sensor_type,omitempty"`
+	Id1           *wrappers.StringValue  `protobuf:"bytes,2,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2           *wrappers.Int32Value   `protobuf:"bytes,3,opt,name=id2,proto3" json:"id2,omitempty"`
+	FromTime      *timestamp.Timestamp   `protobuf:"bytes,4,opt,name=from_time,json=fromTime,proto3" json:"from_time,omitempty"`
+	ToTime        *timestamp.Timestamp   `protobuf:"bytes,5,opt,name=to_time,json=toTime,proto3" json:"to_time,omitempty"`
+	MinValue      *wrappers.DoubleValue  `protobuf:"bytes,6,opt,name=min_value,json=minValue,proto3" json:"min_value,omitempty"`
+	MaxValue      *wrappers.DoubleValue  `protobuf:"bytes,7,opt,name=max_value,json=maxValue,proto3" json:"max_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SensorDataFilter) Reset() {
+	*x = SensorDataFilter{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SensorDataFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorDataFilter) ProtoMessage() {}
+
+func (x *SensorDataFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorDataFilter.ProtoReflect.Descriptor instead.
+func (*SensorDataFilter) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SensorDataFilter) GetSensorType() *wrappers.StringValue {
+	if x != nil {
+		return x.SensorType
+	}
+	return nil
+}
+
+func (x *SensorDataFilter) GetId1() *wrappers.StringValue {
+	if x != nil {
+		return x.Id1
+	}
+	return nil
+}
+
+func (x *SensorDataFilter) GetId2() *wrappers.Int32Value {
+	if x != nil {
+		return x.Id2
+	}
+	return nil
+}
+
+func (x *SensorDataFilter) GetFromTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.FromTime
+	}
+	return nil
+}
+
+func (x *SensorDataFilter) GetToTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.ToTime
+	}
+	return nil
+}
+
+func (x *SensorDataFilter) GetMinValue() *wrappers.DoubleValue {
+	if x != nil {
+		return x.MinValue
+	}
+	return nil
+}
+
+func (x *SensorDataFilter) GetMaxValue() *wrappers.DoubleValue {
+	if x != nil {
+		return x.MaxValue
+	}
+	return nil
+}
+
+type GetSensorDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSensorDataRequest) Reset() {
+	*x = GetSensorDataRequest{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSensorDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSensorDataRequest) ProtoMessage() {}
+
+func (x *GetSensorDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSensorDataRequest.ProtoReflect.Descriptor instead.
+func (*GetSensorDataRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSensorDataRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListSensorDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *SensorDataFilter      `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Sort          string                 `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	Order         string                 `protobuf:"bytes,5,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSensorDataRequest) Reset() {
+	*x = ListSensorDataRequest{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSensorDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSensorDataRequest) ProtoMessage() {}
+
+func (x *ListSensorDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSensorDataRequest.ProtoReflect.Descriptor instead.
+func (*ListSensorDataRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSensorDataRequest) GetFilter() *SensorDataFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListSensorDataRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSensorDataRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSensorDataRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListSensorDataRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+type ListSensorDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []*SensorDataRecord    `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSensorDataResponse) Reset() {
+	*x = ListSensorDataResponse{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSensorDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSensorDataResponse) ProtoMessage() {}
+
+func (x *ListSensorDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSensorDataResponse.ProtoReflect.Descriptor instead.
+func (*ListSensorDataResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListSensorDataResponse) GetData() []*SensorDataRecord {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ListSensorDataResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSensorDataResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSensorDataResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListSensorDataResponse) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+type IDCombinationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id1           string                 `protobuf:"bytes,1,opt,name=id1,proto3" json:"id1,omitempty"`
+	Id2           int32                  `protobuf:"varint,2,opt,name=id2,proto3" json:"id2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IDCombinationRequest) Reset() {
+	*x = IDCombinationRequest{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IDCombinationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IDCombinationRequest) ProtoMessage() {}
+
+func (x *IDCombinationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IDCombinationRequest.ProtoReflect.Descriptor instead.
+func (*IDCombinationRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *IDCombinationRequest) GetId1() string {
+	if x != nil {
+		return x.Id1
+	}
+	return ""
+}
+
+func (x *IDCombinationRequest) GetId2() int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return 0
+}
+
+type DurationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromTime      *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=from_time,json=fromTime,proto3" json:"from_time,omitempty"`
+	ToTime        *timestamp.Timestamp   `protobuf:"bytes,2,opt,name=to_time,json=toTime,proto3" json:"to_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DurationRequest) Reset() {
+	*x = DurationRequest{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DurationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DurationRequest) ProtoMessage() {}
+
+func (x *DurationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DurationRequest.ProtoReflect.Descriptor instead.
+func (*DurationRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DurationRequest) GetFromTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.FromTime
+	}
+	return nil
+}
+
+func (x *DurationRequest) GetToTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.ToTime
+	}
+	return nil
+}
+
+// Subscription filter for live sensor data, empty fields match everything
+type WatchRequest struct {
+	state              protoimpl.MessageState          `protogen:"open.v1"`
+	SensorTypes        []string                        `protobuf:"bytes,1,rep,name=sensor_types,json=sensorTypes,proto3" json:"sensor_types,omitempty"`
+	Id1                []string                        `protobuf:"bytes,2,rep,name=id1,proto3" json:"id1,omitempty"`
+	Id2                []int32                         `protobuf:"varint,3,rep,packed,name=id2,proto3" json:"id2,omitempty"`
+	MinValue           *wrappers.DoubleValue           `protobuf:"bytes,4,opt,name=min_value,json=minValue,proto3" json:"min_value,omitempty"`
+	MaxValue           *wrappers.DoubleValue           `protobuf:"bytes,5,opt,name=max_value,json=maxValue,proto3" json:"max_value,omitempty"`
+	SlowConsumerPolicy WatchRequest_SlowConsumerPolicy `protobuf:"varint,6,opt,name=slow_consumer_policy,json=slowConsumerPolicy,proto3,enum=sensor.WatchRequest_SlowConsumerPolicy" json:"slow_consumer_policy,omitempty"`
+	BufferSize         int32                           `protobuf:"varint,7,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchRequest) GetSensorTypes() []string {
+	if x != nil {
+		return x.SensorTypes
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetId1() []string {
+	if x != nil {
+		return x.Id1
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetId2() []int32 {
+	if x != nil {
+		return x.Id2
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetMinValue() *wrappers.DoubleValue {
+	if x != nil {
+		return x.MinValue
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetMaxValue() *wrappers.DoubleValue {
+	if x != nil {
+		return x.MaxValue
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetSlowConsumerPolicy() WatchRequest_SlowConsumerPolicy {
+	if x != nil {
+		return x.SlowConsumerPolicy
+	}
+	return WatchRequest_DEFAULT
+}
+
+func (x *WatchRequest) GetBufferSize() int32 {
+	if x != nil {
+		return x.BufferSize
+	}
+	return 0
+}
+
+// Generator state reported over the control stream
+type GeneratorStatusReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsRunning     bool                   `protobuf:"varint,1,opt,name=is_running,json=isRunning,proto3" json:"is_running,omitempty"`
+	Frequency     string                 `protobuf:"bytes,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	TotalSent     int64                  `protobuf:"varint,3,opt,name=total_sent,json=totalSent,proto3" json:"total_sent,omitempty"`
+	Errors        int64                  `protobuf:"varint,4,opt,name=errors,proto3" json:"errors,omitempty"`
+	LastGenerated *timestamp.Timestamp   `protobuf:"bytes,5,opt,name=last_generated,json=lastGenerated,proto3" json:"last_generated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratorStatusReport) Reset() {
+	*x = GeneratorStatusReport{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratorStatusReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratorStatusReport) ProtoMessage() {}
+
+func (x *GeneratorStatusReport) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratorStatusReport.ProtoReflect.Descriptor instead.
+func (*GeneratorStatusReport) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GeneratorStatusReport) GetIsRunning() bool {
+	if x != nil {
+		return x.IsRunning
+	}
+	return false
+}
+
+func (x *GeneratorStatusReport) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *GeneratorStatusReport) GetTotalSent() int64 {
+	if x != nil {
+		return x.TotalSent
+	}
+	return 0
+}
+
+func (x *GeneratorStatusReport) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *GeneratorStatusReport) GetLastGenerated() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastGenerated
+	}
+	return nil
+}
+
+// First message a generator sends on the control stream
+type GeneratorRegistration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	SensorType    string                 `protobuf:"bytes,2,opt,name=sensor_type,json=sensorType,proto3" json:"sensor_type,omitempty"`
+	Status        *GeneratorStatusReport `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratorRegistration) Reset() {
+	*x = GeneratorRegistration{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratorRegistration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratorRegistration) ProtoMessage() {}
+
+func (x *GeneratorRegistration) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratorRegistration.ProtoReflect.Descriptor instead.
+func (*GeneratorRegistration) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GeneratorRegistration) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *GeneratorRegistration) GetSensorType() string {
+	if x != nil {
+		return x.SensorType
+	}
+	return ""
+}
+
+func (x *GeneratorRegistration) GetStatus() *GeneratorStatusReport {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+// Outcome of a command, sent back by the generator once applied
+type GeneratorCommandResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommandId     string                 `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Status        *GeneratorStatusReport `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratorCommandResult) Reset() {
+	*x = GeneratorCommandResult{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratorCommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratorCommandResult) ProtoMessage() {}
+
+func (x *GeneratorCommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratorCommandResult.ProtoReflect.Descriptor instead.
+func (*GeneratorCommandResult) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GeneratorCommandResult) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *GeneratorCommandResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GeneratorCommandResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GeneratorCommandResult) GetStatus() *GeneratorStatusReport {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+// Messages sent by a generator to microservice-b
+type GeneratorMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*GeneratorMessage_Register
+	//	*GeneratorMessage_Status
+	//	*GeneratorMessage_CommandResult
+	Payload       isGeneratorMessage_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratorMessage) Reset() {
+	*x = GeneratorMessage{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratorMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratorMessage) ProtoMessage() {}
+
+func (x *GeneratorMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratorMessage.ProtoReflect.Descriptor instead.
+func (*GeneratorMessage) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GeneratorMessage) GetPayload() isGeneratorMessage_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *GeneratorMessage) GetRegister() *GeneratorRegistration {
+	if x != nil {
+		if x, ok := x.Payload.(*GeneratorMessage_Register); ok {
+			return x.Register
+		}
+	}
+	return nil
+}
+
+func (x *GeneratorMessage) GetStatus() *GeneratorStatusReport {
+	if x != nil {
+		if x, ok := x.Payload.(*GeneratorMessage_Status); ok {
+			return x.Status
+		}
+	}
+	return nil
+}
+
+func (x *GeneratorMessage) GetCommandResult() *GeneratorCommandResult {
+	if x != nil {
+		if x, ok := x.Payload.(*GeneratorMessage_CommandResult); ok {
+			return x.CommandResult
+		}
+	}
+	return nil
+}
+
+type isGeneratorMessage_Payload interface {
+	isGeneratorMessage_Payload()
+}
+
+type GeneratorMessage_Register struct {
+	Register *GeneratorRegistration `protobuf:"bytes,1,opt,name=register,proto3,oneof"`
+}
+
+type GeneratorMessage_Status struct {
+	Status *GeneratorStatusReport `protobuf:"bytes,2,opt,name=status,proto3,oneof"`
+}
+
+type GeneratorMessage_CommandResult struct {
+	CommandResult *GeneratorCommandResult `protobuf:"bytes,3,opt,name=command_result,json=commandResult,proto3,oneof"`
+}
+
+func (*GeneratorMessage_Register) isGeneratorMessage_Payload() {}
+
+func (*GeneratorMessage_Status) isGeneratorMessage_Payload() {}
+
+func (*GeneratorMessage_CommandResult) isGeneratorMessage_Payload() {}
+
+// Command pushed by microservice-b to a generator
+type GeneratorCommand struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	CommandId     string                  `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	Action        GeneratorCommand_Action `protobuf:"varint,2,opt,name=action,proto3,enum=sensor.GeneratorCommand_Action" json:"action,omitempty"`
+	Frequency     string                  `protobuf:"bytes,3,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratorCommand) Reset() {
+	*x = GeneratorCommand{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratorCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratorCommand) ProtoMessage() {}
+
+func (x *GeneratorCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratorCommand.ProtoReflect.Descriptor instead.
+func (*GeneratorCommand) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GeneratorCommand) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *GeneratorCommand) GetAction() GeneratorCommand_Action {
+	if x != nil {
+		return x.Action
+	}
+	return GeneratorCommand_UNKNOWN
+}
+
+func (x *GeneratorCommand) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+// Health check messages
+type HealthCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckRequest) Reset() {
+	*x = HealthCheckRequest{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckRequest) ProtoMessage() {}
+
+func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HealthCheckRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState            `protogen:"open.v1"`
+	Status        HealthCheckResponse_ServingStatus `protobuf:"varint,1,opt,name=status,proto3,enum=sensor.HealthCheckResponse_ServingStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_sensor_sensor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_sensor_sensor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *HealthCheckResponse) GetStatus() HealthCheckResponse_ServingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HealthCheckResponse_UNKNOWN
+}
+
+var File_shared_proto_sensor_sensor_proto protoreflect.FileDescriptor
+
+const file_shared_proto_sensor_sensor_proto_rawDesc = "" +
+	"\n" +
+	" shared/proto/sensor/sensor.proto\x12\x06sensor\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\xae\x01\n" +
+	"\n" +
+	"SensorData\x12!\n" +
+	"\fsensor_value\x18\x01 \x01(\x01R\vsensorValue\x12\x1f\n" +
+	"\vsensor_type\x18\x02 \x01(\tR\n" +
+	"sensorType\x12\x10\n" +
+	"\x03id1\x18\x03 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x04 \x01(\x05R\x03id2\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"Z\n" +
+	"\x0eSensorResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"9\n" +
+	"\x0fSensorDataBatch\x12&\n" +
+	"\x04data\x18\x01 \x03(\v2\x12.sensor.SensorDataR\x04data\"\xd9\x01\n" +
+	"\x10SensorItemResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x127\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1f.sensor.SensorItemResult.StatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1c\n" +
+	"\tretryable\x18\x04 \x01(\bR\tretryable\"@\n" +
+	"\x06Status\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\f\n" +
+	"\bACCEPTED\x10\x01\x12\r\n" +
+	"\tDUPLICATE\x10\x02\x12\f\n" +
+	"\bREJECTED\x10\x03\"\xeb\x01\n" +
+	"\x13SensorBatchResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x122\n" +
+	"\aresults\x18\x04 \x03(\v2\x18.sensor.SensorItemResultR\aresults\x12\x1a\n" +
+	"\baccepted\x18\x05 \x01(\x05R\baccepted\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x06 \x01(\x05R\n" +
+	"duplicates\x12\x1a\n" +
+	"\brejected\x18\a \x01(\x05R\brejected\"\xba\x02\n" +
+	"\x10SensorDataRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12!\n" +
+	"\fsensor_value\x18\x02 \x01(\x01R\vsensorValue\x12\x1f\n" +
+	"\vsensor_type\x18\x03 \x01(\tR\n" +
+	"sensorType\x12\x10\n" +
+	"\x03id1\x18\x04 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x05 \x01(\x05R\x03id2\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x94\x03\n" +
+	"\x10SensorDataFilter\x12=\n" +
+	"\vsensor_type\x18\x01 \x01(\v2\x1c.google.protobuf.StringValueR\n" +
+	"sensorType\x12.\n" +
+	"\x03id1\x18\x02 \x01(\v2\x1c.google.protobuf.StringValueR\x03id1\x12-\n" +
+	"\x03id2\x18\x03 \x01(\v2\x1b.google.protobuf.Int32ValueR\x03id2\x127\n" +
+	"\tfrom_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bfromTime\x123\n" +
+	"\ato_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06toTime\x129\n" +
+	"\tmin_value\x18\x06 \x01(\v2\x1c.google.protobuf.DoubleValueR\bminValue\x129\n" +
+	"\tmax_value\x18\a \x01(\v2\x1c.google.protobuf.DoubleValueR\bmaxValue\"&\n" +
+	"\x14GetSensorDataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xa4\x01\n" +
+	"\x15ListSensorDataRequest\x120\n" +
+	"\x06filter\x18\x01 \x01(\v2\x18.sensor.SensorDataFilterR\x06filter\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x05 \x01(\tR\x05order\"\xae\x01\n" +
+	"\x16ListSensorDataResponse\x12,\n" +
+	"\x04data\x18\x01 \x03(\v2\x18.sensor.SensorDataRecordR\x04data\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\x12\x1f\n" +
+	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
+	"totalPages\":\n" +
+	"\x14IDCombinationRequest\x12\x10\n" +
+	"\x03id1\x18\x01 \x01(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x02 \x01(\x05R\x03id2\"\x7f\n" +
+	"\x0fDurationRequest\x127\n" +
+	"\tfrom_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\bfromTime\x123\n" +
+	"\ato_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x06toTime\"\x8b\x03\n" +
+	"\fWatchRequest\x12!\n" +
+	"\fsensor_types\x18\x01 \x03(\tR\vsensorTypes\x12\x10\n" +
+	"\x03id1\x18\x02 \x03(\tR\x03id1\x12\x10\n" +
+	"\x03id2\x18\x03 \x03(\x05R\x03id2\x129\n" +
+	"\tmin_value\x18\x04 \x01(\v2\x1c.google.protobuf.DoubleValueR\bminValue\x129\n" +
+	"\tmax_value\x18\x05 \x01(\v2\x1c.google.protobuf.DoubleValueR\bmaxValue\x12Y\n" +
+	"\x14slow_consumer_policy\x18\x06 \x01(\x0e2'.sensor.WatchRequest.SlowConsumerPolicyR\x12slowConsumerPolicy\x12\x1f\n" +
+	"\vbuffer_size\x18\a \x01(\x05R\n" +
+	"bufferSize\"B\n" +
+	"\x12SlowConsumerPolicy\x12\v\n" +
+	"\aDEFAULT\x10\x00\x12\x0f\n" +
+	"\vDROP_OLDEST\x10\x01\x12\x0e\n" +
+	"\n" +
+	"DISCONNECT\x10\x02\"\xce\x01\n" +
+	"\x15GeneratorStatusReport\x12\x1d\n" +
+	"\n" +
+	"is_running\x18\x01 \x01(\bR\tisRunning\x12\x1c\n" +
+	"\tfrequency\x18\x02 \x01(\tR\tfrequency\x12\x1d\n" +
+	"\n" +
+	"total_sent\x18\x03 \x01(\x03R\ttotalSent\x12\x16\n" +
+	"\x06errors\x18\x04 \x01(\x03R\x06errors\x12A\n" +
+	"\x0elast_generated\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rlastGenerated\"\x90\x01\n" +
+	"\x15GeneratorRegistration\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12\x1f\n" +
+	"\vsensor_type\x18\x02 \x01(\tR\n" +
+	"sensorType\x125\n" +
+	"\x06status\x18\x03 \x01(\v2\x1d.sensor.GeneratorStatusReportR\x06status\"\x9e\x01\n" +
+	"\x16GeneratorCommandResult\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x01 \x01(\tR\tcommandId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x125\n" +
+	"\x06status\x18\x04 \x01(\v2\x1d.sensor.GeneratorStatusReportR\x06status\"\xdc\x01\n" +
+	"\x10GeneratorMessage\x12;\n" +
+	"\bregister\x18\x01 \x01(\v2\x1d.sensor.GeneratorRegistrationH\x00R\bregister\x127\n" +
+	"\x06status\x18\x02 \x01(\v2\x1d.sensor.GeneratorStatusReportH\x00R\x06status\x12G\n" +
+	"\x0ecommand_result\x18\x03 \x01(\v2\x1e.sensor.GeneratorCommandResultH\x00R\rcommandResultB\t\n" +
+	"\apayload\"\xc7\x01\n" +
+	"\x10GeneratorCommand\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x01 \x01(\tR\tcommandId\x127\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x1f.sensor.GeneratorCommand.ActionR\x06action\x12\x1c\n" +
+	"\tfrequency\x18\x03 \x01(\tR\tfrequency\"=\n" +
+	"\x06Action\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\t\n" +
+	"\x05START\x10\x01\x12\b\n" +
+	"\x04STOP\x10\x02\x12\x11\n" +
+	"\rSET_FREQUENCY\x10\x03\".\n" +
+	"\x12HealthCheckRequest\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\"\x94\x01\n" +
+	"\x13HealthCheckResponse\x12A\n" +
+	"\x06status\x18\x01 \x01(\x0e2).sensor.HealthCheckResponse.ServingStatusR\x06status\":\n" +
+	"\rServingStatus\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\v\n" +
+	"\aSERVING\x10\x01\x12\x0f\n" +
+	"\vNOT_SERVING\x10\x022\xd7\x04\n" +
+	"\rSensorService\x12<\n" +
+	"\x0eSendSensorData\x12\x12.sensor.SensorData\x1a\x16.sensor.SensorResponse\x12K\n" +
+	"\x13SendSensorDataBatch\x12\x17.sensor.SensorDataBatch\x1a\x1b.sensor.SensorBatchResponse\x12G\n" +
+	"\rGetSensorData\x12\x1c.sensor.GetSensorDataRequest\x1a\x18.sensor.SensorDataRecord\x12O\n" +
+	"\x0eListSensorData\x12\x1d.sensor.ListSensorDataRequest\x1a\x1e.sensor.ListSensorDataResponse\x12N\n" +
+	"\x12GetByIDCombination\x12\x1c.sensor.IDCombinationRequest\x1a\x18.sensor.SensorDataRecord0\x01\x12D\n" +
+	"\rGetByDuration\x12\x17.sensor.DurationRequest\x1a\x18.sensor.SensorDataRecord0\x01\x12C\n" +
+	"\x0fWatchSensorData\x12\x14.sensor.WatchRequest\x1a\x18.sensor.SensorDataRecord0\x01\x12F\n" +
+	"\vHealthCheck\x12\x1a.sensor.HealthCheckRequest\x1a\x1b.sensor.HealthCheckResponse2\\\n" +
+	"\x17GeneratorControlService\x12A\n" +
+	"\aConnect\x12\x18.sensor.GeneratorMessage\x1a\x18.sensor.GeneratorCommand(\x010\x01BAZ?github.com/worlder-team/microservice-server/shared/proto/sensorb\x06proto3"
+
+var (
+	file_shared_proto_sensor_sensor_proto_rawDescOnce sync.Once
+	file_shared_proto_sensor_sensor_proto_rawDescData []byte
+)
+
+func file_shared_proto_sensor_sensor_proto_rawDescGZIP() []byte {
+	file_shared_proto_sensor_sensor_proto_rawDescOnce.Do(func() {
+		file_shared_proto_sensor_sensor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shared_proto_sensor_sensor_proto_rawDesc), len(file_shared_proto_sensor_sensor_proto_rawDesc)))
+	})
+	return file_shared_proto_sensor_sensor_proto_rawDescData
+}
+
+var file_shared_proto_sensor_sensor_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_shared_proto_sensor_sensor_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_shared_proto_sensor_sensor_proto_goTypes = []any{
+	(SensorItemResult_Status)(0),           // 0: sensor.SensorItemResult.Status
+	(WatchRequest_SlowConsumerPolicy)(0),   // 1: sensor.WatchRequest.SlowConsumerPolicy
+	(GeneratorCommand_Action)(0),           // 2: sensor.GeneratorCommand.Action
+	(HealthCheckResponse_ServingStatus)(0), // 3: sensor.HealthCheckResponse.ServingStatus
+	(*SensorData)(nil),                     // 4: sensor.SensorData
+	(*SensorResponse)(nil),                 // 5: sensor.SensorResponse
+	(*SensorDataBatch)(nil),                // 6: sensor.SensorDataBatch
+	(*SensorItemResult)(nil),               // 7: sensor.SensorItemResult
+	(*SensorBatchResponse)(nil),            // 8: sensor.SensorBatchResponse
+	(*SensorDataRecord)(nil),               // 9: sensor.SensorDataRecord
+	(*SensorDataFilter)(nil),               // 10: sensor.SensorDataFilter
+	(*GetSensorDataRequest)(nil),           // 11: sensor.GetSensorDataRequest
+	(*ListSensorDataRequest)(nil),          // 12: sensor.ListSensorDataRequest
+	(*ListSensorDataResponse)(nil),         // 13: sensor.ListSensorDataResponse
+	(*IDCombinationRequest)(nil),           // 14: sensor.IDCombinationRequest
+	(*DurationRequest)(nil),                // 15: sensor.DurationRequest
+	(*WatchRequest)(nil),                   // 16: sensor.WatchRequest
+	(*GeneratorStatusReport)(nil),          // 17: sensor.GeneratorStatusReport
+	(*GeneratorRegistration)(nil),          // 18: sensor.GeneratorRegistration
+	(*GeneratorCommandResult)(nil),         // 19: sensor.GeneratorCommandResult
+	(*GeneratorMessage)(nil),               // 20: sensor.GeneratorMessage
+	(*GeneratorCommand)(nil),               // 21: sensor.GeneratorCommand
+	(*HealthCheckRequest)(nil),             // 22: sensor.HealthCheckRequest
+	(*HealthCheckResponse)(nil),            // 23: sensor.HealthCheckResponse
+	(*timestamp.Timestamp)(nil),            // 24: google.protobuf.Timestamp
+	(*wrappers.StringValue)(nil),           // 25: google.protobuf.StringValue
+	(*wrappers.Int32Value)(nil),            // 26: google.protobuf.Int32Value
+	(*wrappers.DoubleValue)(nil),           // 27: google.protobuf.DoubleValue
+}
+var file_shared_proto_sensor_sensor_proto_depIdxs = []int32{
+	24, // 0: sensor.SensorData.timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 1: sensor.SensorDataBatch.data:type_name -> sensor.SensorData
+	0,  // 2: sensor.SensorItemResult.status:type_name -> sensor.SensorItemResult.Status
+	7,  // 3: sensor.SensorBatchResponse.results:type_name -> sensor.SensorItemResult
+	24, // 4: sensor.SensorDataRecord.timestamp:type_name -> google.protobuf.Timestamp
+	24, // 5: sensor.SensorDataRecord.created_at:type_name -> google.protobuf.Timestamp
+	24, // 6: sensor.SensorDataRecord.updated_at:type_name -> google.protobuf.Timestamp
+	25, // 7: sensor.SensorDataFilter.sensor_type:type_name -> google.protobuf.StringValue
+	25, // 8: sensor.SensorDataFilter.id1:type_name -> google.protobuf.StringValue
+	26, // 9: sensor.SensorDataFilter.id2:type_name -> google.protobuf.Int32Value
+	24, // 10: sensor.SensorDataFilter.from_time:type_name -> google.protobuf.Timestamp
+	24, // 11: sensor.SensorDataFilter.to_time:type_name -> google.protobuf.Timestamp
+	27, // 12: sensor.SensorDataFilter.min_value:type_name -> google.protobuf.DoubleValue
+	27, // 13: sensor.SensorDataFilter.max_value:type_name -> google.protobuf.DoubleValue
+	10, // 14: sensor.ListSensorDataRequest.filter:type_name -> sensor.SensorDataFilter
+	9,  // 15: sensor.ListSensorDataResponse.data:type_name -> sensor.SensorDataRecord
+	24, // 16: sensor.DurationRequest.from_time:type_name -> google.protobuf.Timestamp
+	24, // 17: sensor.DurationRequest.to_time:type_name -> google.protobuf.Timestamp
+	27, // 18: sensor.WatchRequest.min_value:type_name -> google.protobuf.DoubleValue
+	27, // 19: sensor.WatchRequest.max_value:type_name -> google.protobuf.DoubleValue
+	1,  // 20: sensor.WatchRequest.slow_consumer_policy:type_name -> sensor.WatchRequest.SlowConsumerPolicy
+	24, // 21: sensor.GeneratorStatusReport.last_generated:type_name -> google.protobuf.Timestamp
+	17, // 22: sensor.GeneratorRegistration.status:type_name -> sensor.GeneratorStatusReport
+	17, // 23: sensor.GeneratorCommandResult.status:type_name -> sensor.GeneratorStatusReport
+	18, // 24: sensor.GeneratorMessage.register:type_name -> sensor.GeneratorRegistration
+	17, // 25: sensor.GeneratorMessage.status:type_name -> sensor.GeneratorStatusReport
+	19, // 26: sensor.GeneratorMessage.command_result:type_name -> sensor.GeneratorCommandResult
+	2,  // 27: sensor.GeneratorCommand.action:type_name -> sensor.GeneratorCommand.Action
+	3,  // 28: sensor.HealthCheckResponse.status:type_name -> sensor.HealthCheckResponse.ServingStatus
+	4,  // 29: sensor.SensorService.SendSensorData:input_type -> sensor.SensorData
+	6,  // 30: sensor.SensorService.SendSensorDataBatch:input_type -> sensor.SensorDataBatch
+	11, // 31: sensor.SensorService.GetSensorData:input_type -> sensor.GetSensorDataRequest
+	12, // 32: sensor.SensorService.ListSensorData:input_type -> sensor.ListSensorDataRequest
+	14, // 33: sensor.SensorService.GetByIDCombination:input_type -> sensor.IDCombinationRequest
+	15, // 34: sensor.SensorService.GetByDuration:input_type -> sensor.DurationRequest
+	16, // 35: sensor.SensorService.WatchSensorData:input_type -> sensor.WatchRequest
+	22, // 36: sensor.SensorService.HealthCheck:input_type -> sensor.HealthCheckRequest
+	20, // 37: sensor.GeneratorControlService.Connect:input_type -> sensor.GeneratorMessage
+	5,  // 38: sensor.SensorService.SendSensorData:output_type -> sensor.SensorResponse
+	8,  // 39: sensor.SensorService.SendSensorDataBatch:output_type -> sensor.SensorBatchResponse
+	9,  // 40: sensor.SensorService.GetSensorData:output_type -> sensor.SensorDataRecord
+	13, // 41: sensor.SensorService.ListSensorData:output_type -> sensor.ListSensorDataResponse
+	9,  // 42: sensor.SensorService.GetByIDCombination:output_type -> sensor.SensorDataRecord
+	9,  // 43: sensor.SensorService.GetByDuration:output_type -> sensor.SensorDataRecord
+	9,  // 44: sensor.SensorService.WatchSensorData:output_type -> sensor.SensorDataRecord
+	23, // 45: sensor.SensorService.HealthCheck:output_type -> sensor.HealthCheckResponse
+	21, // 46: sensor.GeneratorControlService.Connect:output_type -> sensor.GeneratorCommand
+	38, // [38:47] is the sub-list for method output_type
+	29, // [29:38] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
+}
+
+func init() { file_shared_proto_sensor_sensor_proto_init() }
+func file_shared_proto_sensor_sensor_proto_init() {
+	if File_shared_proto_sensor_sensor_proto != nil {
+		return
+	}
+	file_shared_proto_sensor_sensor_proto_msgTypes[16].OneofWrappers = []any{
+		(*GeneratorMessage_Register)(nil),
+		(*GeneratorMessage_Status)(nil),
+		(*GeneratorMessage_CommandResult)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shared_proto_sensor_sensor_proto_rawDesc), len(file_shared_proto_sensor_sensor_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   5,
+			NumEnums:      4,
+			NumMessages:   20,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_shared_proto_sensor_sensor_proto_goTypes,
 		DependencyIndexes: file_shared_proto_sensor_sensor_proto_depIdxs,
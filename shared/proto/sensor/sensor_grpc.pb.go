@@ -21,6 +21,11 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	SensorService_SendSensorData_FullMethodName      = "/sensor.SensorService/SendSensorData"
 	SensorService_SendSensorDataBatch_FullMethodName = "/sensor.SensorService/SendSensorDataBatch"
+	SensorService_GetSensorData_FullMethodName       = "/sensor.SensorService/GetSensorData"
+	SensorService_ListSensorData_FullMethodName      = "/sensor.SensorService/ListSensorData"
+	SensorService_GetByIDCombination_FullMethodName  = "/sensor.SensorService/GetByIDCombination"
+	SensorService_GetByDuration_FullMethodName       = "/sensor.SensorService/GetByDuration"
+	SensorService_WatchSensorData_FullMethodName     = "/sensor.SensorService/WatchSensorData"
 	SensorService_HealthCheck_FullMethodName         = "/sensor.SensorService/HealthCheck"
 )
 
@@ -33,7 +38,17 @@ type SensorServiceClient interface {
 	// Send single sensor data
 	SendSensorData(ctx context.Context, in *SensorData, opts ...grpc.CallOption) (*SensorResponse, error)
 	// Send batch sensor data
-	SendSensorDataBatch(ctx context.Context, in *SensorDataBatch, opts ...grpc.CallOption) (*SensorResponse, error)
+	SendSensorDataBatch(ctx context.Context, in *SensorDataBatch, opts ...grpc.CallOption) (*SensorBatchResponse, error)
+	// Get sensor data by ID
+	GetSensorData(ctx context.Context, in *GetSensorDataRequest, opts ...grpc.CallOption) (*SensorDataRecord, error)
+	// List sensor data with filtering and pagination
+	ListSensorData(ctx context.Context, in *ListSensorDataRequest, opts ...grpc.CallOption) (*ListSensorDataResponse, error)
+	// Stream sensor data by ID1 and ID2 combination
+	GetByIDCombination(ctx context.Context, in *IDCombinationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorDataRecord], error)
+	// Stream sensor data within a time range
+	GetByDuration(ctx context.Context, in *DurationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorDataRecord], error)
+	// Stream newly ingested sensor data matching the filter
+	WatchSensorData(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorDataRecord], error)
 	// Health check
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 }
@@ -56,9 +71,9 @@ func (c *sensorServiceClient) SendSensorData(ctx context.Context, in *SensorData
 	return out, nil
 }
 
-func (c *sensorServiceClient) SendSensorDataBatch(ctx context.Context, in *SensorDataBatch, opts ...grpc.CallOption) (*SensorResponse, error) {
+func (c *sensorServiceClient) SendSensorDataBatch(ctx context.Context, in *SensorDataBatch, opts ...grpc.CallOption) (*SensorBatchResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SensorResponse)
+	out := new(SensorBatchResponse)
 	err := c.cc.Invoke(ctx, SensorService_SendSensorDataBatch_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -66,6 +81,83 @@ func (c *sensorServiceClient) SendSensorDataBatch(ctx context.Context, in *Senso
 	return out, nil
 }
 
+func (c *sensorServiceClient) GetSensorData(ctx context.Context, in *GetSensorDataRequest, opts ...grpc.CallOption) (*SensorDataRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SensorDataRecord)
+	err := c.cc.Invoke(ctx, SensorService_GetSensorData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorServiceClient) ListSensorData(ctx context.Context, in *ListSensorDataRequest, opts ...grpc.CallOption) (*ListSensorDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSensorDataResponse)
+	err := c.cc.Invoke(ctx, SensorService_ListSensorData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sensorServiceClient) GetByIDCombination(ctx context.Context, in *IDCombinationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorDataRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SensorService_ServiceDesc.Streams[0], SensorService_GetByIDCombination_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IDCombinationRequest, SensorDataRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorService_GetByIDCombinationClient = grpc.ServerStreamingClient[SensorDataRecord]
+
+func (c *sensorServiceClient) GetByDuration(ctx context.Context, in *DurationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorDataRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SensorService_ServiceDesc.Streams[1], SensorService_GetByDuration_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DurationRequest, SensorDataRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorService_GetByDurationClient = grpc.ServerStreamingClient[SensorDataRecord]
+
+func (c *sensorServiceClient) WatchSensorData(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SensorDataRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SensorService_ServiceDesc.Streams[2], SensorService_WatchSensorData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, SensorDataRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorService_WatchSensorDataClient = grpc.ServerStreamingClient[SensorDataRecord]
+
 func (c *sensorServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HealthCheckResponse)
@@ -85,7 +177,17 @@ type SensorServiceServer interface {
 	// Send single sensor data
 	SendSensorData(context.Context, *SensorData) (*SensorResponse, error)
 	// Send batch sensor data
-	SendSensorDataBatch(context.Context, *SensorDataBatch) (*SensorResponse, error)
+	SendSensorDataBatch(context.Context, *SensorDataBatch) (*SensorBatchResponse, error)
+	// Get sensor data by ID
+	GetSensorData(context.Context, *GetSensorDataRequest) (*SensorDataRecord, error)
+	// List sensor data with filtering and pagination
+	ListSensorData(context.Context, *ListSensorDataRequest) (*ListSensorDataResponse, error)
+	// Stream sensor data by ID1 and ID2 combination
+	GetByIDCombination(*IDCombinationRequest, grpc.ServerStreamingServer[SensorDataRecord]) error
+	// Stream sensor data within a time range
+	GetByDuration(*DurationRequest, grpc.ServerStreamingServer[SensorDataRecord]) error
+	// Stream newly ingested sensor data matching the filter
+	WatchSensorData(*WatchRequest, grpc.ServerStreamingServer[SensorDataRecord]) error
 	// Health check
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
 	mustEmbedUnimplementedSensorServiceServer()
@@ -101,9 +203,24 @@ type UnimplementedSensorServiceServer struct{}
 func (UnimplementedSensorServiceServer) SendSensorData(context.Context, *SensorData) (*SensorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendSensorData not implemented")
 }
-func (UnimplementedSensorServiceServer) SendSensorDataBatch(context.Context, *SensorDataBatch) (*SensorResponse, error) {
+func (UnimplementedSensorServiceServer) SendSensorDataBatch(context.Context, *SensorDataBatch) (*SensorBatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendSensorDataBatch not implemented")
 }
+func (UnimplementedSensorServiceServer) GetSensorData(context.Context, *GetSensorDataRequest) (*SensorDataRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSensorData not implemented")
+}
+func (UnimplementedSensorServiceServer) ListSensorData(context.Context, *ListSensorDataRequest) (*ListSensorDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSensorData not implemented")
+}
+func (UnimplementedSensorServiceServer) GetByIDCombination(*IDCombinationRequest, grpc.ServerStreamingServer[SensorDataRecord]) error {
+	return status.Errorf(codes.Unimplemented, "method GetByIDCombination not implemented")
+}
+func (UnimplementedSensorServiceServer) GetByDuration(*DurationRequest, grpc.ServerStreamingServer[SensorDataRecord]) error {
+	return status.Errorf(codes.Unimplemented, "method GetByDuration not implemented")
+}
+func (UnimplementedSensorServiceServer) WatchSensorData(*WatchRequest, grpc.ServerStreamingServer[SensorDataRecord]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSensorData not implemented")
+}
 func (UnimplementedSensorServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -164,6 +281,75 @@ func _SensorService_SendSensorDataBatch_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SensorService_GetSensorData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSensorDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorServiceServer).GetSensorData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorService_GetSensorData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorServiceServer).GetSensorData(ctx, req.(*GetSensorDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorService_ListSensorData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSensorDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SensorServiceServer).ListSensorData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SensorService_ListSensorData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SensorServiceServer).ListSensorData(ctx, req.(*ListSensorDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SensorService_GetByIDCombination_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IDCombinationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SensorServiceServer).GetByIDCombination(m, &grpc.GenericServerStream[IDCombinationRequest, SensorDataRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorService_GetByIDCombinationServer = grpc.ServerStreamingServer[SensorDataRecord]
+
+func _SensorService_GetByDuration_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DurationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SensorServiceServer).GetByDuration(m, &grpc.GenericServerStream[DurationRequest, SensorDataRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorService_GetByDurationServer = grpc.ServerStreamingServer[SensorDataRecord]
+
+func _SensorService_WatchSensorData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SensorServiceServer).WatchSensorData(m, &grpc.GenericServerStream[WatchRequest, SensorDataRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SensorService_WatchSensorDataServer = grpc.ServerStreamingServer[SensorDataRecord]
+
 func _SensorService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(HealthCheckRequest)
 	if err := dec(in); err != nil {
@@ -197,11 +383,138 @@ var SensorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendSensorDataBatch",
 			Handler:    _SensorService_SendSensorDataBatch_Handler,
 		},
+		{
+			MethodName: "GetSensorData",
+			Handler:    _SensorService_GetSensorData_Handler,
+		},
+		{
+			MethodName: "ListSensorData",
+			Handler:    _SensorService_ListSensorData_Handler,
+		},
 		{
 			MethodName: "HealthCheck",
 			Handler:    _SensorService_HealthCheck_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetByIDCombination",
+			Handler:       _SensorService_GetByIDCombination_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetByDuration",
+			Handler:       _SensorService_GetByDuration_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchSensorData",
+			Handler:       _SensorService_WatchSensorData_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "shared/proto/sensor/sensor.proto",
+}
+
+const (
+	GeneratorControlService_Connect_FullMethodName = "/sensor.GeneratorControlService/Connect"
+)
+
+// GeneratorControlServiceClient is the client API for GeneratorControlService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Control plane for sensor data generators
+type GeneratorControlServiceClient interface {
+	// Register a generator and receive commands for it until the stream closes
+	Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GeneratorMessage, GeneratorCommand], error)
+}
+
+type generatorControlServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGeneratorControlServiceClient(cc grpc.ClientConnInterface) GeneratorControlServiceClient {
+	return &generatorControlServiceClient{cc}
+}
+
+func (c *generatorControlServiceClient) Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GeneratorMessage, GeneratorCommand], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GeneratorControlService_ServiceDesc.Streams[0], GeneratorControlService_Connect_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GeneratorMessage, GeneratorCommand]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GeneratorControlService_ConnectClient = grpc.BidiStreamingClient[GeneratorMessage, GeneratorCommand]
+
+// GeneratorControlServiceServer is the server API for GeneratorControlService service.
+// All implementations must embed UnimplementedGeneratorControlServiceServer
+// for forward compatibility.
+//
+// Control plane for sensor data generators
+type GeneratorControlServiceServer interface {
+	// Register a generator and receive commands for it until the stream closes
+	Connect(grpc.BidiStreamingServer[GeneratorMessage, GeneratorCommand]) error
+	mustEmbedUnimplementedGeneratorControlServiceServer()
+}
+
+// UnimplementedGeneratorControlServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGeneratorControlServiceServer struct{}
+
+func (UnimplementedGeneratorControlServiceServer) Connect(grpc.BidiStreamingServer[GeneratorMessage, GeneratorCommand]) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedGeneratorControlServiceServer) mustEmbedUnimplementedGeneratorControlServiceServer() {
+}
+func (UnimplementedGeneratorControlServiceServer) testEmbeddedByValue() {}
+
+// UnsafeGeneratorControlServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GeneratorControlServiceServer will
+// result in compilation errors.
+type UnsafeGeneratorControlServiceServer interface {
+	mustEmbedUnimplementedGeneratorControlServiceServer()
+}
+
+func RegisterGeneratorControlServiceServer(s grpc.ServiceRegistrar, srv GeneratorControlServiceServer) {
+	// If the following call pancis, it indicates UnimplementedGeneratorControlServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GeneratorControlService_ServiceDesc, srv)
+}
+
+func _GeneratorControlService_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GeneratorControlServiceServer).Connect(&grpc.GenericServerStream[GeneratorMessage, GeneratorCommand]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GeneratorControlService_ConnectServer = grpc.BidiStreamingServer[GeneratorMessage, GeneratorCommand]
+
+// GeneratorControlService_ServiceDesc is the grpc.ServiceDesc for GeneratorControlService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GeneratorControlService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sensor.GeneratorControlService",
+	HandlerType: (*GeneratorControlServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _GeneratorControlService_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "shared/proto/sensor/sensor.proto",
 }
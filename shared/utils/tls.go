@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSOptions holds certificate locations used to build TLS configurations
+type TLSOptions struct {
+	CertFile       string        // PEM certificate presented to the peer
+	KeyFile        string        // PEM private key for CertFile
+	CAFile         string        // PEM CA bundle used to verify the peer
+	ClientAuth     bool          // Server only: require and verify client certificates (mTLS)
+	ServerName     string        // Client only: expected server name, defaults to the dialed host
+	ReloadInterval time.Duration // How often files are checked for changes, 0 disables reloading
+}
+
+// CertReloader keeps a certificate and CA pool in sync with files on disk
+type CertReloader struct {
+	opts     TLSOptions
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes map[string]time.Time
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewCertReloader loads the configured files and starts watching them for changes
+func NewCertReloader(opts TLSOptions) (*CertReloader, error) {
+	r := &CertReloader{
+		opts:     opts,
+		modTimes: make(map[string]time.Time),
+		done:     make(chan struct{}),
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	if opts.ReloadInterval > 0 {
+		go r.watch()
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the current certificate for TLS servers
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.cert == nil {
+		return nil, errors.New("no certificate configured")
+	}
+	return r.cert, nil
+}
+
+// GetClientCertificate returns the current certificate for TLS clients
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.cert == nil {
+		// An empty certificate lets the server decide whether one is required
+		return &tls.Certificate{}, nil
+	}
+	return r.cert, nil
+}
+
+// CAPool returns the current CA pool, or nil when no CA file is configured
+func (r *CertReloader) CAPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.caPool
+}
+
+// Close stops watching files for changes
+func (r *CertReloader) Close() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+}
+
+// watch periodically reloads the files when their modification time changes
+func (r *CertReloader) watch() {
+	ticker := time.NewTicker(r.opts.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				Error(fmt.Sprintf("Failed to reload TLS certificates: %v", err))
+				continue
+			}
+			Info("Reloaded TLS certificates")
+		}
+	}
+}
+
+// changed reports whether any watched file has a new modification time
+func (r *CertReloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// reload reads the certificate, key and CA bundle from disk
+func (r *CertReloader) reload() error {
+	var cert *tls.Certificate
+	if r.opts.CertFile != "" || r.opts.KeyFile != "" {
+		loaded, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load key pair: %w", err)
+		}
+		cert = &loaded
+	}
+
+	var caPool *x509.CertPool
+	if r.opts.CAFile != "" {
+		pem, err := os.ReadFile(r.opts.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", r.opts.CAFile)
+		}
+	}
+
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.files() {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = cert
+	r.caPool = caPool
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	return nil
+}
+
+// files returns the configured file paths
+func (r *CertReloader) files() []string {
+	var files []string
+	for _, file := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.CAFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// NewServerTLSConfig builds a server TLS configuration that picks up certificate changes from disk
+func NewServerTLSConfig(opts TLSOptions) (*tls.Config, *CertReloader, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, nil, errors.New("server TLS requires a certificate and key file")
+	}
+	if opts.ClientAuth && opts.CAFile == "" {
+		return nil, nil, errors.New("client certificate verification requires a CA file")
+	}
+
+	reloader, err := NewCertReloader(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientAuth := tls.NoClientCert
+	if opts.ClientAuth {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{"h2"},
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	// Resolve the CA pool per handshake so a rotated bundle applies to new connections
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			NextProtos:     []string{"h2"},
+			GetCertificate: reloader.GetCertificate,
+			ClientAuth:     clientAuth,
+			ClientCAs:      reloader.CAPool(),
+		}, nil
+	}
+
+	return config, reloader, nil
+}
+
+// NewClientTLSConfig builds a client TLS configuration that picks up client certificate and CA bundle
+// changes from disk. Without a CA file the system roots are used to verify the server. With one, the
+// server certificate is checked against opts.ServerName, or the SNI name sent when it is empty; as no
+// SNI is sent for IP addresses, dialing an IP requires opts.ServerName.
+func NewClientTLSConfig(opts TLSOptions) (*tls.Config, *CertReloader, error) {
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return nil, nil, errors.New("client certificate and key file must be set together")
+	}
+
+	reloader, err := NewCertReloader(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		ServerName:           opts.ServerName,
+		GetClientCertificate: reloader.GetClientCertificate,
+	}
+
+	if opts.CAFile != "" {
+		// RootCAs is fixed for the lifetime of the config, so verify against the current pool
+		// ourselves to let a rotated bundle apply to new connections
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			serverName := opts.ServerName
+			if serverName == "" {
+				serverName = cs.ServerName
+			}
+			return verifyServerChain(cs, reloader.CAPool(), serverName)
+		}
+	}
+
+	return config, reloader, nil
+}
+
+// verifyServerChain performs the verification crypto/tls would do with RootCAs set to roots and
+// ServerName set to serverName. It fails when serverName is empty rather than skip the hostname check.
+func verifyServerChain(cs tls.ConnectionState, roots *x509.CertPool, serverName string) error {
+	if serverName == "" {
+		return errors.New("no server name to verify the server certificate against")
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       serverName,
+	})
+	return err
+}
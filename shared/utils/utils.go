@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -54,6 +55,14 @@ func ParseInt(s string) int {
 	return 0
 }
 
+// ParseBool parses string to bool with fallback to false
+func ParseBool(s string) bool {
+	if value, err := strconv.ParseBool(s); err == nil {
+		return value
+	}
+	return false
+}
+
 // ParseDurationOrZero parses duration string with fallback to 0
 func ParseDurationOrZero(s string) time.Duration {
 	if duration, err := time.ParseDuration(s); err == nil {
@@ -61,3 +70,10 @@ func ParseDurationOrZero(s string) time.Duration {
 	}
 	return 0
 }
+
+// DetachedContext returns a context with the values of ctx but not its cancellation, limited to timeout.
+// It is meant for work following a request, such as publishing or caching its result, since the request
+// context may be cancelled as soon as the caller has its response.
+func DetachedContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), timeout)
+}